@@ -2,98 +2,172 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
+	_ "time/tzdata" // embed the timezone database for user schedules
 
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/config"
 	gwhttp "github.com/Xausdorf/pr-reviewer-assignment/internal/gateway/http"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/health"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/metrics"
+	teampolicy "github.com/Xausdorf/pr-reviewer-assignment/internal/policy"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
 	repopg "github.com/Xausdorf/pr-reviewer-assignment/internal/repository/postgres"
+	reposqlite "github.com/Xausdorf/pr-reviewer-assignment/internal/repository/sqlite"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/worker"
 	"github.com/Xausdorf/pr-reviewer-assignment/pkg/migrate"
 	pg "github.com/Xausdorf/pr-reviewer-assignment/pkg/postgres"
-)
-
-const (
-	defaultAddr              = ":8080"
-	defaultReadHeaderTimeout = 1 * time.Second
-	defaultReadTimeout       = 5 * time.Second
-	defaultWriteTimeout      = 5 * time.Second
-	defaultShutdownTimeout   = 10 * time.Second
-
-	defaultDBMaxConns          = 10
-	defaultDBMinConns          = 1
-	defaultDBPingTimeout       = 5 * time.Second
-	defaultDBHealthCheckPeriod = 10 * time.Second
+	pkgsqlite "github.com/Xausdorf/pr-reviewer-assignment/pkg/sqlite"
 )
 
 func main() {
-	logger := log.New()
-	logger.SetFormatter(&log.TextFormatter{FullTimestamp: true})
-	if lvl := os.Getenv("LOG_LEVEL"); lvl != "" {
-		if l, err := log.ParseLevel(lvl); err == nil {
-			logger.SetLevel(l)
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		err := runSync(os.Args[0]+" sync", os.Args[2:])
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		if err != nil {
+			log.WithError(err).Fatal("org sync failed")
 		}
+		return
 	}
 
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		logger.Fatal("DATABASE_URL is required")
+	cfg, err := config.Load(os.Args[0], os.Args[1:], os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-
-	ctx := context.Background()
-
-	// run migrations before creating pgx pool
-	migrationsDir := "./migrations"
-	if mdir := os.Getenv("MIGRATIONS_DIR"); mdir != "" {
-		migrationsDir = mdir
+	if err != nil {
+		log.WithError(err).Fatal("invalid configuration")
 	}
-	if err := migrate.RunMigrations(dbURL, migrationsDir, logger); err != nil {
-		logger.WithError(err).Fatal("migrations failed")
+	if cfg.PrintConfig {
+		if err = cfg.Print(os.Stdout); err != nil {
+			log.WithError(err).Fatal("failed to print configuration")
+		}
+		return
 	}
 
-	pgCfg := pg.Config{
-		ConnString:        dbURL,
-		MaxConns:          defaultDBMaxConns,
-		MinConns:          defaultDBMinConns,
-		HealthCheckPeriod: defaultDBHealthCheckPeriod,
-		PingTimeout:       defaultDBPingTimeout,
+	logger, err := logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		log.WithError(err).Fatal("failed to set up logging")
 	}
-	pool, err := pg.NewPool(ctx, pgCfg, logger)
+
+	policy, policyReloader, err := selectionPolicy(cfg.Assignment, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("invalid team policies")
+	}
+
+	ctx := context.Background()
+	appMetrics := metrics.New()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing.Exporter)
 	if err != nil {
-		logger.WithError(err).Fatal("failed to connect to db")
+		logger.WithError(err).Fatal("failed to set up tracing")
 	}
-	defer pg.ClosePool(pool)
 
-	// repositories
-	prRepo := repopg.NewPRRepository(pool)
-	teamRepo := repopg.NewTeamRepository(pool)
-	userRepo := repopg.NewUserRepository(pool)
+	checker := health.New(cfg.HTTP.ReadinessTimeout)
+
+	var repos repositories
+	switch cfg.Storage.Type {
+	case config.StoragePostgres:
+		// the DSN scheme picks the database
+		if path, ok := cfg.Storage.SQLitePath(); ok {
+			db, err := openSQLite(ctx, cfg.Storage, path, logger)
+			if err != nil {
+				logger.WithError(err).Fatal("failed to open db")
+			}
+			defer pkgsqlite.Close(db)
+			appMetrics.RegisterDB(db, "sqlite")
+			checker.Add("database", db.PingContext)
+			checker.Add("migrations", sqliteVersionCheck(cfg.Storage, db, logger).Check)
+			repos = sqliteRepositories(db)
+			break
+		}
+		pool, err := openPostgres(ctx, cfg.Storage, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("failed to open db")
+		}
+		defer pg.ClosePool(pool)
+		appMetrics.RegisterPool(pool)
+		checker.Add("database", pool.Ping)
+		checker.Add("migrations", postgresVersionCheck(cfg.Storage, pool, logger).Check)
+		repos = postgresRepositories(pool)
+	case config.StorageMemory:
+		logger.Warn("using in-memory storage, data is lost on restart")
+		repos = memoryRepositories(memory.NewStore())
+	}
 
 	// services
-	prUseCase := usecase.NewPRUseCase(prRepo, userRepo, logger)
-	teamUseCase := usecase.NewTeamUseCase(teamRepo, logger)
-	userUseCase := usecase.NewUserUseCase(userRepo, logger)
+	prUseCase := usecase.NewPRUseCase(repos.pr, repos.user, repos.team, repos.schedule, repos.audit, repos.decline,
+		policy, appMetrics, logger)
+	teamUseCase := usecase.NewTeamUseCase(repos.team, logger)
+	userUseCase := usecase.NewUserUseCase(repos.user, logger)
+	scheduleUseCase := usecase.NewScheduleUseCase(repos.schedule, repos.user, logger)
+	statusChangeUseCase := usecase.NewStatusChangeUseCase(repos.statusChange, repos.user, userUseCase, logger)
+	slaUseCase := usecase.NewSLAUseCase(repos.sla, prUseCase, cfg.Assignment.EscalationMode, logger)
+	loadUseCase := usecase.NewLoadUseCase(repos.team, appMetrics, logger)
+	orgUseCase := usecase.NewOrgUseCase(repos.org, repos.user, prUseCase, logger)
+
+	// background workers
+	ctxWorkers, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	statusPoller := worker.NewPoller("scheduled_status_changes", cfg.Workers.StatusChangeInterval,
+		statusChangeUseCase.ApplyDueChanges, logger)
+	go statusPoller.Run(ctxWorkers)
+	slaPoller := worker.NewPoller("review_sla", cfg.Workers.SLAInterval, slaUseCase.EscalateOverdue, logger)
+	go slaPoller.Run(ctxWorkers)
+	loadPoller := worker.NewPoller("team_load_metrics", cfg.Workers.LoadInterval, loadUseCase.RefreshLoad, logger)
+	go loadPoller.Run(ctxWorkers)
+	if policyReloader != nil {
+		go watchTeamPolicies(ctxWorkers, policyReloader, logger)
+	}
 
 	// http server
-	server := gwhttp.NewServer(prUseCase, teamUseCase, userUseCase, logger)
-	handler := gwhttp.Handler(server)
+	server := gwhttp.NewServer(
+		prUseCase,
+		teamUseCase,
+		userUseCase,
+		scheduleUseCase,
+		statusChangeUseCase,
+		slaUseCase,
+		orgUseCase,
+		logger,
+	)
+	router := chi.NewRouter()
+	router.Use(logging.Middleware(logger, "/healthz", "/readyz", "/metrics"))
+	router.Handle("/metrics", appMetrics.Handler())
+	router.Handle("/healthz", checker.LiveHandler())
+	router.Handle("/readyz", checker.ReadyHandler())
+	handler := gwhttp.HandlerWithOptions(server, gwhttp.ChiServerOptions{
+		BaseRouter:  router,
+		Middlewares: []gwhttp.MiddlewareFunc{appMetrics.Middleware, tracing.Middleware},
+	})
 
 	httpServer := &http.Server{
-		Addr:              defaultAddr,
+		Addr:              cfg.HTTP.Addr,
 		Handler:           handler,
-		ReadTimeout:       defaultReadTimeout,
-		WriteTimeout:      defaultWriteTimeout,
-		ReadHeaderTimeout: defaultReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
 	}
 
 	go func() {
 		logger.WithField("addr", httpServer.Addr).Info("starting server")
-		if err = httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.WithError(err).Fatal("http server failed")
 		}
 	}()
@@ -103,9 +177,182 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("shutting down")
-	ctxShut, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+	// fail readiness first so the orchestrator stops routing new requests here
+	checker.Drain()
+	if cfg.HTTP.DrainDelay > 0 {
+		logger.WithField("delay", cfg.HTTP.DrainDelay).Info("draining before shutdown")
+		time.Sleep(cfg.HTTP.DrainDelay)
+	}
+	stopWorkers()
+	ctxShut, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
-	if err = httpServer.Shutdown(ctxShut); err != nil {
+	if err := httpServer.Shutdown(ctxShut); err != nil {
 		logger.WithError(err).Error("error during shutdown")
 	}
+	if err := shutdownTracing(ctxShut); err != nil {
+		logger.WithError(err).Error("failed to flush traces")
+	}
+}
+
+// selectionPolicy builds the reviewer selection policy. The reloader is nil
+// unless team policies are configured.
+func selectionPolicy(
+	cfg config.Assignment,
+	logger *log.Logger,
+) (usecase.SelectionPolicy, *teampolicy.Reloader, error) {
+	policy := usecase.SelectionPolicy{
+		Mode:           cfg.Mode,
+		FairnessWindow: cfg.FairnessWindow,
+		Seeder:         usecase.RandomSeeder(),
+		OverCapacity:   cfg.CapacityPolicy,
+		MaxReviewers:   cfg.MaxReviewers,
+	}
+	if seed := cfg.Seed; seed != nil {
+		logger.WithField("seed", *seed).Warn("reviewer selection uses a fixed seed")
+		policy.Seeder = usecase.FixedSeeder(*seed)
+	}
+	if cfg.TeamPoliciesFile == "" {
+		return policy, nil, nil
+	}
+
+	policy.Teams = &usecase.TeamPolicies{}
+	reloader, err := teampolicy.NewReloader(cfg.TeamPoliciesFile, policy.Teams, logger)
+	if err != nil {
+		return usecase.SelectionPolicy{}, nil, err
+	}
+	return policy, reloader, nil
+}
+
+// watchTeamPolicies reloads the team policies when the file changes and on
+// SIGHUP until ctx is done.
+func watchTeamPolicies(ctx context.Context, reloader *teampolicy.Reloader, logger *log.Logger) {
+	go func() {
+		if err := reloader.Watch(ctx); err != nil {
+			logger.WithError(err).Error("failed to watch team policies, reload with SIGHUP")
+		}
+	}()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			_ = reloader.Reload()
+		}
+	}
+}
+
+type repositories struct {
+	pr           usecase.PRRepository
+	team         usecase.TeamRepository
+	user         usecase.UserRepository
+	schedule     usecase.ScheduleRepository
+	statusChange usecase.StatusChangeRepository
+	sla          usecase.SLARepository
+	audit        usecase.ReviewerAuditRepository
+	decline      usecase.DeclineRepository
+	org          usecase.OrgRepository
+}
+
+// openPostgres applies migrations and connects to the database.
+func openPostgres(ctx context.Context, cfg config.Storage, logger *log.Logger) (*pgxpool.Pool, error) {
+	// run migrations before creating pgx pool
+	if err := migrate.RunMigrations(cfg.DatabaseURL, cfg.MigrationsDir, logger); err != nil {
+		return nil, fmt.Errorf("migrations failed: %w", err)
+	}
+
+	pgCfg := pg.Config{
+		ConnString:        cfg.DatabaseURL,
+		MaxConns:          cfg.MaxConns,
+		MinConns:          cfg.MinConns,
+		HealthCheckPeriod: cfg.HealthCheckPeriod,
+		PingTimeout:       cfg.PingTimeout,
+	}
+	pool, err := pg.NewPool(ctx, pgCfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to db: %w", err)
+	}
+
+	return pool, nil
+}
+
+// postgresVersionCheck reports whether the schema is at the latest migration of
+// the migrations directory.
+func postgresVersionCheck(cfg config.Storage, pool *pgxpool.Pool, logger *log.Logger) *migrate.VersionCheck {
+	check, err := migrate.NewVersionCheck(migrate.PostgresVersion(pool), cfg.MigrationsDir)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to read migrations")
+	}
+	return check
+}
+
+func sqliteVersionCheck(cfg config.Storage, db *sql.DB, logger *log.Logger) *migrate.VersionCheck {
+	check, err := migrate.NewVersionCheck(migrate.SQLiteVersion(db), filepath.Join(cfg.MigrationsDir, "sqlite"))
+	if err != nil {
+		logger.WithError(err).Fatal("failed to read migrations")
+	}
+	return check
+}
+
+// openSQLite applies migrations from the sqlite subdirectory and opens the database file.
+func openSQLite(ctx context.Context, cfg config.Storage, path string, logger *log.Logger) (*sql.DB, error) {
+	if path == "" {
+		return nil, errors.New("DATABASE_URL must contain the database file path, e.g. sqlite://data/reviewers.db")
+	}
+
+	if err := migrate.RunSQLiteMigrations(path, filepath.Join(cfg.MigrationsDir, "sqlite"), logger); err != nil {
+		return nil, fmt.Errorf("migrations failed: %w", err)
+	}
+
+	db, err := pkgsqlite.Open(ctx, pkgsqlite.Config{Path: path}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+func postgresRepositories(pool *pgxpool.Pool) repositories {
+	return repositories{
+		pr:           repopg.NewPRRepository(pool),
+		team:         repopg.NewTeamRepository(pool),
+		user:         repopg.NewUserRepository(pool),
+		schedule:     repopg.NewScheduleRepository(pool),
+		statusChange: repopg.NewStatusChangeRepository(pool),
+		sla:          repopg.NewSLARepository(pool),
+		audit:        repopg.NewReviewerAuditRepository(pool),
+		decline:      repopg.NewDeclineRepository(pool),
+		org:          repopg.NewOrgRepository(pool),
+	}
+}
+
+func memoryRepositories(store *memory.Store) repositories {
+	return repositories{
+		pr:           memory.NewPRRepository(store),
+		team:         memory.NewTeamRepository(store),
+		user:         memory.NewUserRepository(store),
+		schedule:     memory.NewScheduleRepository(store),
+		statusChange: memory.NewStatusChangeRepository(store),
+		sla:          memory.NewSLARepository(store),
+		audit:        memory.NewReviewerAuditRepository(store),
+		decline:      memory.NewDeclineRepository(store),
+		org:          memory.NewOrgRepository(store),
+	}
+}
+
+func sqliteRepositories(db *sql.DB) repositories {
+	return repositories{
+		pr:           reposqlite.NewPRRepository(db),
+		team:         reposqlite.NewTeamRepository(db),
+		user:         reposqlite.NewUserRepository(db),
+		schedule:     reposqlite.NewScheduleRepository(db),
+		statusChange: reposqlite.NewStatusChangeRepository(db),
+		sla:          reposqlite.NewSLARepository(db),
+		audit:        reposqlite.NewReviewerAuditRepository(db),
+		decline:      reposqlite.NewDeclineRepository(db),
+		org:          reposqlite.NewOrgRepository(db),
+	}
 }
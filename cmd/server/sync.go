@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/config"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	gwhttp "github.com/Xausdorf/pr-reviewer-assignment/internal/gateway/http"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+	pg "github.com/Xausdorf/pr-reviewer-assignment/pkg/postgres"
+	pkgsqlite "github.com/Xausdorf/pr-reviewer-assignment/pkg/sqlite"
+)
+
+// errUsage is returned by runSync after printing the usage for a missing or
+// extra argument.
+var errUsage = errors.New("invalid usage")
+
+// runSync applies the org manifest to the database configured like the
+// server: sync [--dry-run] [flags] manifest.yml, "-" reads stdin.
+func runSync(name string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [--dry-run] [flags] manifest.yml\n", name)
+		fs.PrintDefaults()
+	}
+	cfg, err := config.LoadFlags(fs, args, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	if cfg.Storage.Type == config.StorageMemory {
+		return errors.New("sync needs a database, the in-memory storage is empty on every start")
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		return fmt.Errorf("failed to set up logging: %w", err)
+	}
+
+	manifest, err := readManifest(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	ctx := context.Background()
+	var repos repositories
+	if path, ok := cfg.Storage.SQLitePath(); ok {
+		db, err := openSQLite(ctx, cfg.Storage, path, logger)
+		if err != nil {
+			return err
+		}
+		defer pkgsqlite.Close(db)
+		repos = sqliteRepositories(db)
+	} else {
+		pool, err := openPostgres(ctx, cfg.Storage, logger)
+		if err != nil {
+			return err
+		}
+		defer pg.ClosePool(pool)
+		repos = postgresRepositories(pool)
+	}
+
+	policy, _, err := selectionPolicy(cfg.Assignment, logger)
+	if err != nil {
+		return fmt.Errorf("invalid team policies: %w", err)
+	}
+	prUseCase := usecase.NewPRUseCase(repos.pr, repos.user, repos.team, repos.schedule, repos.audit, repos.decline,
+		policy, nil, logger)
+	orgUseCase := usecase.NewOrgUseCase(repos.org, repos.user, prUseCase, logger)
+
+	result, err := orgUseCase.Sync(ctx, manifest, *dryRun)
+	if err != nil {
+		return err
+	}
+	writeSyncResult(os.Stdout, result)
+	return nil
+}
+
+// readManifest reads a YAML or JSON manifest in the format of the /org/sync
+// request body.
+func readManifest(path string) ([]entity.OrgTeam, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is decoded through JSON to apply the field names of the API schema
+	var raw any
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var m gwhttp.OrgManifest
+	if err = dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return gwhttp.OrgManifestToEntity(m), nil
+}
+
+// writeSyncResult prints the plan the way terraform does: + for new entries,
+// - for removed ones and ~ for changes.
+func writeSyncResult(w io.Writer, result *entity.OrgSyncResult) {
+	plan := result.Plan
+	if plan.Empty() {
+		fmt.Fprintln(w, "No changes, the database matches the manifest.")
+		return
+	}
+
+	for _, name := range plan.AddTeams {
+		fmt.Fprintf(w, "  + team %s\n", name)
+	}
+	for _, name := range plan.RemoveTeams {
+		fmt.Fprintf(w, "  - team %s\n", name)
+	}
+	for _, c := range plan.Users {
+		fmt.Fprintf(w, "  %s\n", describeUserChange(c))
+	}
+	for _, r := range plan.Reassign {
+		fmt.Fprintf(w, "  ~ review %s: %s -> %s within %s\n", r.PRID, r.ReviewerID, r.ReplacedBy, r.TeamName)
+	}
+	fmt.Fprintf(w, "\nPlan: %d teams to add, %d to remove, %d users to change, %d reviews to reassign.\n",
+		len(plan.AddTeams), len(plan.RemoveTeams), len(plan.Users), len(plan.Reassign))
+
+	if result.DryRun {
+		fmt.Fprintln(w, "Dry run, nothing was changed.")
+		return
+	}
+	fmt.Fprintln(w, "Applied.")
+}
+
+func describeUserChange(c entity.UserChange) string {
+	after := c.After
+	if c.Before == nil {
+		return fmt.Sprintf("+ user %s (%s) in %s%s", after.ID, after.Name, after.TeamName, inactiveSuffix(after))
+	}
+
+	before := *c.Before
+	var diff []string
+	if before.TeamName != after.TeamName {
+		diff = append(diff, fmt.Sprintf("team %s -> %s", before.TeamName, after.TeamName))
+	}
+	if before.Name != after.Name {
+		diff = append(diff, fmt.Sprintf("name %q -> %q", before.Name, after.Name))
+	}
+	if before.IsActive != after.IsActive {
+		diff = append(diff, fmt.Sprintf("active %t -> %t", before.IsActive, after.IsActive))
+	}
+	return fmt.Sprintf("~ user %s (%s): %s", after.ID, before.Name, strings.Join(diff, ", "))
+}
+
+func inactiveSuffix(u entity.User) string {
+	if u.IsActive {
+		return ""
+	}
+	return ", inactive"
+}
@@ -3,10 +3,20 @@ package apperror
 import "errors"
 
 var (
-	ErrNoCandidate = errors.New("no candidate available")
-	ErrPRExists    = errors.New("pr already exists")
-	ErrNotAssigned = errors.New("not assigned")
-	ErrNotFound    = errors.New("not found")
-	ErrPRMerged    = errors.New("pr merged")
-	ErrTeamExists  = errors.New("team already exists")
+	ErrNoCandidate     = errors.New("no candidate available")
+	ErrPRExists        = errors.New("pr already exists")
+	ErrReviewerExists  = errors.New("reviewer already assigned")
+	ErrNotAssigned     = errors.New("not assigned")
+	ErrNotFound        = errors.New("not found")
+	ErrPRMerged        = errors.New("pr merged")
+	ErrTeamExists      = errors.New("team already exists")
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	ErrInvalidSLA      = errors.New("invalid review sla")
+	ErrInvalidListing  = errors.New("invalid listing parameters")
+	ErrInvalidCapacity = errors.New("invalid review capacity")
+	ErrAllAtCapacity   = errors.New("all candidates at review capacity")
+	ErrReviewersFull   = errors.New("pr has the maximum number of reviewers")
+	ErrNotEligible     = errors.New("user can not review the pr")
+	ErrInvalidDecline  = errors.New("invalid decline reason")
+	ErrInvalidManifest = errors.New("invalid org manifest")
 )
@@ -0,0 +1,331 @@
+// Package config loads the server configuration. Every setting has a default
+// that a YAML file, an environment variable and a command line flag override,
+// in that order.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+
+	sqliteScheme = "sqlite://"
+
+	// EnvConfigFile names the YAML file when the --config flag is not set.
+	EnvConfigFile = "CONFIG_FILE"
+)
+
+type Config struct {
+	HTTP       HTTP       `yaml:"http"`
+	Log        Log        `yaml:"log"`
+	Storage    Storage    `yaml:"storage"`
+	Assignment Assignment `yaml:"assignment"`
+	Workers    Workers    `yaml:"workers"`
+	Tracing    Tracing    `yaml:"tracing"`
+
+	// PrintConfig asks to print the configuration and exit, it is set only by
+	// the --print-config flag.
+	PrintConfig bool `yaml:"-"`
+}
+
+type HTTP struct {
+	Addr              string        `yaml:"addr"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay is the time between failing readiness and shutting down.
+	DrainDelay       time.Duration `yaml:"drain_delay"`
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"`
+}
+
+type Log struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+type Storage struct {
+	// Type is postgres or memory. The postgres storage uses SQLite when the
+	// database URL has the sqlite:// scheme.
+	Type              string        `yaml:"type"`
+	DatabaseURL       string        `yaml:"database_url"`
+	MigrationsDir     string        `yaml:"migrations_dir"`
+	MaxConns          int32         `yaml:"max_conns"`
+	MinConns          int32         `yaml:"min_conns"`
+	PingTimeout       time.Duration `yaml:"ping_timeout"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period"`
+}
+
+type Assignment struct {
+	Mode           string        `yaml:"mode"`
+	FairnessWindow time.Duration `yaml:"fairness_window"`
+	CapacityPolicy string        `yaml:"capacity_policy"`
+	// Seed fixes the random choice of reviewers, nil draws a new seed for
+	// every selection.
+	Seed         *uint64 `yaml:"seed,omitempty"`
+	MaxReviewers int     `yaml:"max_reviewers"`
+	// EscalationMode is the action on reviews that exceeded the team SLA.
+	EscalationMode string `yaml:"escalation_mode"`
+	// TeamPoliciesFile is the YAML file with per-team overrides, reloaded on
+	// change and on SIGHUP. Empty disables the overrides.
+	TeamPoliciesFile string `yaml:"team_policies_file,omitempty"`
+}
+
+type Workers struct {
+	StatusChangeInterval time.Duration `yaml:"status_change_interval"`
+	SLAInterval          time.Duration `yaml:"sla_interval"`
+	LoadInterval         time.Duration `yaml:"load_interval"`
+}
+
+type Tracing struct {
+	Exporter string `yaml:"exporter"`
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() *Config {
+	return &Config{
+		HTTP: HTTP{
+			Addr:              ":8080",
+			ReadHeaderTimeout: 1 * time.Second,
+			ReadTimeout:       5 * time.Second,
+			WriteTimeout:      5 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+			ReadinessTimeout:  2 * time.Second,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: logging.FormatText,
+		},
+		Storage: Storage{
+			Type:              StoragePostgres,
+			MigrationsDir:     "./migrations",
+			MaxConns:          10,
+			MinConns:          1,
+			PingTimeout:       5 * time.Second,
+			HealthCheckPeriod: 10 * time.Second,
+		},
+		Assignment: Assignment{
+			Mode:           entity.SelectionModeRandom,
+			FairnessWindow: usecase.DefaultFairnessWindow,
+			CapacityPolicy: entity.CapacityPolicyUnderstaff,
+			MaxReviewers:   usecase.MaxReviewersCount,
+			EscalationMode: entity.EscalationModeNone,
+		},
+		Workers: Workers{
+			StatusChangeInterval: 30 * time.Second,
+			SLAInterval:          1 * time.Minute,
+			LoadInterval:         30 * time.Second,
+		},
+		Tracing: Tracing{
+			Exporter: tracing.ExporterNone,
+		},
+	}
+}
+
+// Load builds the configuration of the program from the defaults, the YAML
+// file, the environment and the args without the program name. It returns
+// flag.ErrHelp when the args ask for usage, which is written to output.
+func Load(name string, args []string, getenv func(string) string, output io.Writer) (*Config, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	cfg, err := LoadFlags(fs, args, getenv)
+	if err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	return cfg, nil
+}
+
+// LoadFlags is Load for subcommands: their own flags are defined on fs
+// beforehand and positional arguments are left in fs.Args().
+func LoadFlags(fs *flag.FlagSet, args []string, getenv func(string) string) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	configFile := fs.String("config", getenv(EnvConfigFile), "path to the YAML configuration file, env "+EnvConfigFile)
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	// flags are applied after the file and the environment
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		fs.Var(deferredValue{target: s.value, values: flagValues, name: s.flag}, s.flag,
+			fmt.Sprintf("%s, env %s", s.usage, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range settings {
+		if raw := getenv(s.env); raw != "" {
+			if err := s.value.Set(raw); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", s.env, raw, err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if raw, ok := flagValues[s.flag]; ok {
+			if err := s.value.Set(raw); err != nil {
+				return nil, fmt.Errorf("invalid flag -%s %q: %w", s.flag, raw, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err = dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.HTTP.Addr != "", "http.addr is required")
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"http.read_header_timeout", c.HTTP.ReadHeaderTimeout},
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+		{"http.readiness_timeout", c.HTTP.ReadinessTimeout},
+		{"storage.ping_timeout", c.Storage.PingTimeout},
+		{"storage.health_check_period", c.Storage.HealthCheckPeriod},
+		{"assignment.fairness_window", c.Assignment.FairnessWindow},
+		{"workers.status_change_interval", c.Workers.StatusChangeInterval},
+		{"workers.sla_interval", c.Workers.SLAInterval},
+		{"workers.load_interval", c.Workers.LoadInterval},
+	} {
+		check(d.value > 0, "%s must be a positive duration, got %s", d.name, d.value)
+	}
+	check(c.HTTP.DrainDelay >= 0, "http.drain_delay must not be negative")
+
+	_, levelErr := log.ParseLevel(c.Log.Level)
+	check(levelErr == nil, "log.level must be a logrus level like info or debug, got %q", c.Log.Level)
+	check(logging.ValidFormat(c.Log.Format), "log.format must be one of text, json, got %q", c.Log.Format)
+
+	switch c.Storage.Type {
+	case StoragePostgres:
+		check(c.Storage.DatabaseURL != "", "storage.database_url is required for the postgres storage")
+	case StorageMemory:
+	default:
+		check(false, "storage.type must be one of postgres, memory, got %q", c.Storage.Type)
+	}
+	check(c.Storage.MinConns > 0, "storage.min_conns must be positive")
+	check(c.Storage.MaxConns >= c.Storage.MinConns, "storage.max_conns must not be less than storage.min_conns")
+
+	check(usecase.ValidSelectionMode(c.Assignment.Mode),
+		"assignment.mode must be one of random, fairness, got %q", c.Assignment.Mode)
+	check(usecase.ValidCapacityPolicy(c.Assignment.CapacityPolicy),
+		"assignment.capacity_policy must be one of allow, understaff, fail, got %q", c.Assignment.CapacityPolicy)
+	check(usecase.ValidEscalationMode(c.Assignment.EscalationMode),
+		"assignment.escalation_mode must be one of none, reassign, add_reviewer, got %q", c.Assignment.EscalationMode)
+	check(c.Assignment.MaxReviewers > 0, "assignment.max_reviewers must be positive")
+
+	check(tracing.ValidExporter(c.Tracing.Exporter),
+		"tracing.exporter must be one of none, stdout, otlp, got %q", c.Tracing.Exporter)
+
+	return errors.Join(errs...)
+}
+
+// Print writes the configuration as YAML that Load accepts, with the
+// passwords of the database URL redacted.
+func (c *Config) Print(w io.Writer) error {
+	redacted := *c
+	redacted.Storage.DatabaseURL = redactDSN(c.Storage.DatabaseURL)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(redacted); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+const redactedPassword = "xxxxx"
+
+// dsnPasswords are the connection parameters holding a password, libpq
+// accepts them both in the URL query and in keyword/value strings.
+var dsnPasswords = []string{"password", "sslpassword"}
+
+// keywordPassword matches password parameters of keyword/value connection
+// strings like "host=db password='hunter 2'".
+var keywordPassword = regexp.MustCompile(`(?i)\b((?:ssl)?password\s*=\s*)('(?:[^'\\]|\\.)*'|\S*)`)
+
+// redactDSN hides the passwords of both the URL and the keyword/value forms
+// of the database connection string.
+func redactDSN(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	if !strings.Contains(raw, "://") {
+		return keywordPassword.ReplaceAllString(raw, "${1}"+redactedPassword)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		// do not echo a value that may hold a password
+		return "[REDACTED]"
+	}
+	query := u.Query()
+	redactQuery := false
+	for _, key := range dsnPasswords {
+		if query.Has(key) {
+			query.Set(key, redactedPassword)
+			redactQuery = true
+		}
+	}
+	if redactQuery {
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
+
+// SQLitePath returns the database file path when the database URL has the
+// sqlite:// scheme.
+func (s Storage) SQLitePath() (string, bool) {
+	return strings.CutPrefix(s.DatabaseURL, sqliteScheme)
+}
@@ -0,0 +1,186 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/config"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, `
+http:
+  addr: ":7000"
+  write_timeout: 20s
+storage:
+  type: memory
+  max_conns: 30
+assignment:
+  max_reviewers: 3
+`)
+
+	cfg, err := config.Load("server", []string{"--config", path, "--http.addr=:9000"}, env(map[string]string{
+		"HTTP_ADDR":         ":8000",
+		"DB_MAX_CONNS":      "40",
+		"ASSIGNMENT_MODE":   "fairness",
+		"HTTP_READ_TIMEOUT": "",
+	}), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.HTTP.Addr != ":9000" {
+		t.Fatalf("addr = %q, want the flag to win over env and file", cfg.HTTP.Addr)
+	}
+	if cfg.Storage.MaxConns != 40 {
+		t.Fatalf("max conns = %d, want env to win over the file", cfg.Storage.MaxConns)
+	}
+	if cfg.HTTP.WriteTimeout != 20*time.Second || cfg.Assignment.MaxReviewers != 3 {
+		t.Fatalf("write timeout = %s, max reviewers = %d, want the file values",
+			cfg.HTTP.WriteTimeout, cfg.Assignment.MaxReviewers)
+	}
+	if cfg.HTTP.ReadTimeout != config.Default().HTTP.ReadTimeout {
+		t.Fatalf("read timeout = %s, an empty env var must keep the default", cfg.HTTP.ReadTimeout)
+	}
+	if cfg.Assignment.Mode != "fairness" || cfg.Storage.Type != config.StorageMemory {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	path := writeFile(t, "storage:\n  type: memory\n")
+
+	cfg, err := config.Load("server", nil, env(map[string]string{config.EnvConfigFile: path}), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Storage.Type != config.StorageMemory {
+		t.Fatalf("storage = %q, want the one from %s", cfg.Storage.Type, config.EnvConfigFile)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		file string
+		want string
+	}{
+		{"missing database url", nil, nil, "", "storage.database_url is required"},
+		{"unknown mode", nil, map[string]string{"STORAGE": "memory", "ASSIGNMENT_MODE": "roundrobin"}, "",
+			"assignment.mode"},
+		{"bad duration", []string{"--http.read-timeout=soon"}, map[string]string{"STORAGE": "memory"}, "",
+			"invalid flag -http.read-timeout"},
+		{"bad env number", nil, map[string]string{"STORAGE": "memory", "DB_MAX_CONNS": "many"}, "", "DB_MAX_CONNS"},
+		{"pool bounds", nil, map[string]string{"STORAGE": "memory", "DB_MIN_CONNS": "5", "DB_MAX_CONNS": "2"}, "",
+			"storage.max_conns"},
+		{"unknown file key", nil, nil, "storage:\n  typo: memory\n", "field typo not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append(args, "--config", writeFile(t, tt.file))
+			}
+			_, err := config.Load("server", args, env(tt.env), io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	var out bytes.Buffer
+	_, err := config.Load("server", []string{"-h"}, env(nil), &out)
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("error = %v, want %v", err, flag.ErrHelp)
+	}
+	if !strings.Contains(out.String(), "env DATABASE_URL") {
+		t.Fatalf("usage does not name env vars:\n%s", out.String())
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg, err := config.Load("server", []string{"--print-config"}, env(map[string]string{
+		"DATABASE_URL":    "postgres://pr_user:pr_pass@db:5432/prdb",
+		"ASSIGNMENT_SEED": "7",
+	}), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.PrintConfig {
+		t.Fatal("PrintConfig is not set by --print-config")
+	}
+
+	var out bytes.Buffer
+	if err = cfg.Print(&out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "pr_pass") {
+		t.Fatalf("printed config leaks the password:\n%s", out.String())
+	}
+	if cfg.Storage.DatabaseURL != "postgres://pr_user:pr_pass@db:5432/prdb" {
+		t.Fatal("Print changed the config")
+	}
+
+	// the printed config is a valid config file apart from the redacted password
+	reloaded, err := config.Load("server", []string{"--config", writeFile(t, out.String())}, env(nil), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Assignment.Seed == nil || *reloaded.Assignment.Seed != 7 {
+		t.Fatalf("reloaded seed = %v, want 7", reloaded.Assignment.Seed)
+	}
+}
+
+func TestPrintRedactsDSNPasswords(t *testing.T) {
+	for name, dsn := range map[string]string{
+		"url query":     "postgres://app@db/prdb?sslmode=verify-full&password=hunter2&sslpassword=keypass",
+		"keyword/value": "host=db user=app password=hunter2 sslpassword='key pass' dbname=prdb",
+		"spaced":        "host=db password = 'hunter2' dbname=prdb",
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := config.Load("server", nil, env(map[string]string{"DATABASE_URL": dsn}), io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			if err = cfg.Print(&out); err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range []string{"hunter2", "keypass", "key pass"} {
+				if strings.Contains(out.String(), secret) {
+					t.Fatalf("printed config leaks %q:\n%s", secret, out.String())
+				}
+			}
+			if !strings.Contains(out.String(), "prdb") {
+				t.Fatalf("printed config lost the database name:\n%s", out.String())
+			}
+		})
+	}
+}
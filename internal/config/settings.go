@@ -0,0 +1,160 @@
+package config
+
+import (
+	"flag"
+	"strconv"
+	"time"
+)
+
+// setting binds a field of the configuration to its env var and flag.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	value flag.Value
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"http.addr", "HTTP_ADDR", "address to listen on", stringValue(&c.HTTP.Addr)},
+		{"http.read-header-timeout", "HTTP_READ_HEADER_TIMEOUT", "timeout of reading request headers",
+			durationValue(&c.HTTP.ReadHeaderTimeout)},
+		{"http.read-timeout", "HTTP_READ_TIMEOUT", "timeout of reading a request", durationValue(&c.HTTP.ReadTimeout)},
+		{"http.write-timeout", "HTTP_WRITE_TIMEOUT", "timeout of writing a response",
+			durationValue(&c.HTTP.WriteTimeout)},
+		{"http.shutdown-timeout", "HTTP_SHUTDOWN_TIMEOUT", "time to finish requests on shutdown",
+			durationValue(&c.HTTP.ShutdownTimeout)},
+		{"http.drain-delay", "SHUTDOWN_DRAIN_DELAY", "time between failing /readyz and shutting down",
+			durationValue(&c.HTTP.DrainDelay)},
+		{"http.readiness-timeout", "READINESS_TIMEOUT", "timeout of the /readyz checks",
+			durationValue(&c.HTTP.ReadinessTimeout)},
+
+		{"log.level", "LOG_LEVEL", "log level: debug, info, warn or error", stringValue(&c.Log.Level)},
+		{"log.format", "LOG_FORMAT", "log record format: text or json", stringValue(&c.Log.Format)},
+
+		{"storage.type", "STORAGE", "storage: postgres or memory", stringValue(&c.Storage.Type)},
+		{"storage.database-url", "DATABASE_URL", "postgres:// or sqlite:// database URL",
+			stringValue(&c.Storage.DatabaseURL)},
+		{"storage.migrations-dir", "MIGRATIONS_DIR", "directory of the migrations",
+			stringValue(&c.Storage.MigrationsDir)},
+		{"storage.max-conns", "DB_MAX_CONNS", "maximum size of the postgres pool", int32Value(&c.Storage.MaxConns)},
+		{"storage.min-conns", "DB_MIN_CONNS", "minimum size of the postgres pool", int32Value(&c.Storage.MinConns)},
+		{"storage.ping-timeout", "DB_PING_TIMEOUT", "timeout of the first database ping",
+			durationValue(&c.Storage.PingTimeout)},
+		{"storage.health-check-period", "DB_HEALTH_CHECK_PERIOD", "period of postgres pool health checks",
+			durationValue(&c.Storage.HealthCheckPeriod)},
+
+		{"assignment.mode", "ASSIGNMENT_MODE", "reviewer selection: random or fairness",
+			stringValue(&c.Assignment.Mode)},
+		{"assignment.fairness-window", "FAIRNESS_WINDOW", "sliding window of the fairness ledger",
+			durationValue(&c.Assignment.FairnessWindow)},
+		{"assignment.capacity-policy", "CAPACITY_POLICY", "when everyone is at capacity: allow, understaff or fail",
+			stringValue(&c.Assignment.CapacityPolicy)},
+		{"assignment.seed", "ASSIGNMENT_SEED", "fixed seed of the reviewer selection", seedValue(&c.Assignment.Seed)},
+		{"assignment.max-reviewers", "MAX_REVIEWERS", "number of reviewers assigned to a PR",
+			intValue(&c.Assignment.MaxReviewers)},
+		{"assignment.escalation-mode", "SLA_ESCALATION_MODE", "action on overdue reviews: none, reassign or add_reviewer",
+			stringValue(&c.Assignment.EscalationMode)},
+		{"assignment.team-policies-file", "TEAM_POLICIES_FILE", "YAML file with per-team assignment overrides",
+			stringValue(&c.Assignment.TeamPoliciesFile)},
+
+		{"workers.status-change-interval", "STATUS_CHANGE_POLL_INTERVAL", "poll interval of scheduled status changes",
+			durationValue(&c.Workers.StatusChangeInterval)},
+		{"workers.sla-interval", "SLA_POLL_INTERVAL", "poll interval of overdue reviews",
+			durationValue(&c.Workers.SLAInterval)},
+		{"workers.load-interval", "LOAD_POLL_INTERVAL", "refresh interval of the team load metrics",
+			durationValue(&c.Workers.LoadInterval)},
+
+		{"tracing.exporter", "TRACING_EXPORTER", "span exporter: none, stdout or otlp",
+			stringValue(&c.Tracing.Exporter)},
+	}
+}
+
+// value adapts a pointer to a field to flag.Value.
+type value[T any] struct {
+	p      *T
+	parse  func(string) (T, error)
+	format func(T) string
+}
+
+func (v value[T]) Set(raw string) error {
+	parsed, err := v.parse(raw)
+	if err != nil {
+		return err
+	}
+	*v.p = parsed
+	return nil
+}
+
+func (v value[T]) String() string {
+	if v.p == nil {
+		return ""
+	}
+	return v.format(*v.p)
+}
+
+func stringValue(p *string) flag.Value {
+	return value[string]{
+		p:      p,
+		parse:  func(s string) (string, error) { return s, nil },
+		format: func(s string) string { return s },
+	}
+}
+
+func durationValue(p *time.Duration) flag.Value {
+	return value[time.Duration]{p: p, parse: time.ParseDuration, format: time.Duration.String}
+}
+
+func intValue(p *int) flag.Value {
+	return value[int]{p: p, parse: strconv.Atoi, format: strconv.Itoa}
+}
+
+func int32Value(p *int32) flag.Value {
+	return value[int32]{
+		p: p,
+		parse: func(s string) (int32, error) {
+			n, err := strconv.ParseInt(s, 10, 32)
+			return int32(n), err
+		},
+		format: func(n int32) string { return strconv.FormatInt(int64(n), 10) },
+	}
+}
+
+func seedValue(p **uint64) flag.Value {
+	return value[*uint64]{
+		p: p,
+		parse: func(s string) (*uint64, error) {
+			n, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return &n, nil
+		},
+		format: func(n *uint64) string {
+			if n == nil {
+				return ""
+			}
+			return strconv.FormatUint(*n, 10)
+		},
+	}
+}
+
+// deferredValue records a flag so that it is applied after the file and the
+// environment, while the usage still shows the default of the target.
+type deferredValue struct {
+	target flag.Value
+	values map[string]string
+	name   string
+}
+
+func (v deferredValue) Set(raw string) error {
+	v.values[v.name] = raw
+	return nil
+}
+
+func (v deferredValue) String() string {
+	if v.target == nil {
+		return ""
+	}
+	return v.target.String()
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	ReviewerAuditAdded   = "ADDED"
+	ReviewerAuditRemoved = "REMOVED"
+)
+
+// ReviewerAuditEntry records a manual change of the reviewers of a PR.
+type ReviewerAuditEntry struct {
+	ID         int64     `db:"id"`
+	PRID       string    `db:"pr_id"`
+	ReviewerID string    `db:"reviewer_id"`
+	Action     string    `db:"action"`
+	Actor      string    `db:"actor"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func NewReviewerAuditEntry(prID, reviewerID, action, actor string) *ReviewerAuditEntry {
+	return &ReviewerAuditEntry{
+		PRID:       prID,
+		ReviewerID: reviewerID,
+		Action:     action,
+		Actor:      actor,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
@@ -0,0 +1,33 @@
+package entity
+
+const (
+	ExclusionAuthor          = "AUTHOR"
+	ExclusionInactive        = "INACTIVE"
+	ExclusionAlreadyAssigned = "ALREADY_ASSIGNED"
+	ExclusionOutOfOffice     = "OUT_OF_OFFICE"
+	ExclusionOffHours        = "OUTSIDE_WORKING_HOURS"
+	ExclusionAtCapacity      = "AT_CAPACITY"
+	ExclusionDeclined        = "DECLINED"
+	ExclusionRemoved         = "PREVIOUSLY_REMOVED"
+	ExclusionExcluded        = "EXCLUDED"
+)
+
+// Candidate is a team member considered for a review assignment. Members with
+// a non-empty ExclusionReason can not be picked.
+type Candidate struct {
+	User
+
+	ExclusionReason string
+}
+
+func (c Candidate) Eligible() bool {
+	return c.ExclusionReason == ""
+}
+
+// AssignmentSimulation is the outcome of the reviewer selection computed
+// without writing anything.
+type AssignmentSimulation struct {
+	TeamName   string
+	Reviewers  []string
+	Candidates []Candidate
+}
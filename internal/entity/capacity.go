@@ -0,0 +1,23 @@
+package entity
+
+// What happens when every candidate left for a review is at capacity.
+const (
+	CapacityPolicyAllow      = "allow"
+	CapacityPolicyUnderstaff = "understaff"
+	CapacityPolicyFail       = "fail"
+)
+
+// ReviewCapacity returns the limit of concurrent open reviews of the team
+// member, nil means no limit.
+func ReviewCapacity(team Team, user User) *int {
+	if user.MaxOpenReviews != nil {
+		return user.MaxOpenReviews
+	}
+	return team.MaxOpenReviews
+}
+
+// AtCapacity reports whether the member with open reviews can not take another one.
+func AtCapacity(team Team, user User, open int) bool {
+	limit := ReviewCapacity(team, user)
+	return limit != nil && open >= *limit
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	DeclineReasonConflictOfInterest = "CONFLICT_OF_INTEREST"
+	DeclineReasonLackingContext     = "LACKING_CONTEXT"
+	DeclineReasonNoTime             = "NO_TIME"
+	DeclineReasonOther              = "OTHER"
+)
+
+// DeclineReasons lists the accepted reason codes of a declined review.
+var DeclineReasons = []string{
+	DeclineReasonConflictOfInterest,
+	DeclineReasonLackingContext,
+	DeclineReasonNoTime,
+	DeclineReasonOther,
+}
+
+func ValidDeclineReason(reason string) bool {
+	return slices.Contains(DeclineReasons, reason)
+}
+
+// Decline records a reviewer refusing a review of a PR. The decliner is never
+// picked for that PR again.
+type Decline struct {
+	ID            int64     `db:"id"`
+	PRID          string    `db:"pr_id"`
+	ReviewerID    string    `db:"reviewer_id"`
+	Reason        string    `db:"reason"`
+	Comment       string    `db:"comment"`
+	NewReviewerID string    `db:"new_reviewer_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func NewDecline(prID, reviewerID, reason, comment, newReviewerID string) *Decline {
+	return &Decline{
+		PRID:          prID,
+		ReviewerID:    reviewerID,
+		Reason:        reason,
+		Comment:       comment,
+		NewReviewerID: newReviewerID,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
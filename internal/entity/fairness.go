@@ -0,0 +1,156 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	SelectionModeRandom   = "random"
+	SelectionModeFairness = "fairness"
+)
+
+// LedgerEntry is the assignment balance of one team member over the window.
+type LedgerEntry struct {
+	User
+
+	Assignments int
+	// Available is the part of the window the user was a member, active and not
+	// out of office.
+	Available time.Duration
+	// FairShare is the number of assignments the user should have got
+	// proportionally to Available.
+	FairShare float64
+}
+
+// Balance is positive when the user got more assignments than the fair share.
+func (e LedgerEntry) Balance() float64 {
+	return float64(e.Assignments) - e.FairShare
+}
+
+// FairnessLedger splits the assignments of a team over [From, To) between its
+// members pro rata to their availability.
+type FairnessLedger struct {
+	TeamName string
+	From     time.Time
+	To       time.Time
+	Total    int
+	Entries  []LedgerEntry
+}
+
+// NewFairnessLedger builds the ledger from assignment counts per user, the
+// out-of-office periods of the members overlapping the window and the changes of
+// their activity made inside it. The time a member was inactive is not counted
+// as available, a member without changes in the window keeps the current status.
+func NewFairnessLedger(
+	teamName string,
+	members []User,
+	counts map[string]int,
+	away []OutOfOffice,
+	history []StatusHistoryEntry,
+	from, to time.Time,
+) *FairnessLedger {
+	ledger := &FairnessLedger{TeamName: teamName, From: from, To: to}
+	offByUser := make(map[string][]period, len(away))
+	for _, o := range away {
+		offByUser[o.UserID] = append(offByUser[o.UserID], period{start: o.StartsAt, end: o.EndsAt})
+	}
+	historyByUser := make(map[string][]StatusHistoryEntry)
+	for _, h := range history {
+		historyByUser[h.UserID] = append(historyByUser[h.UserID], h)
+	}
+
+	var totalAvailable time.Duration
+	for _, m := range members {
+		e := LedgerEntry{User: m, Assignments: counts[m.ID]}
+		off := slices.Concat(offByUser[m.ID], inactivePeriods(m.IsActive, historyByUser[m.ID], from, to))
+		e.Available = available(later(from, m.CreatedAt), to, off)
+		ledger.Total += e.Assignments
+		totalAvailable += e.Available
+		ledger.Entries = append(ledger.Entries, e)
+	}
+
+	if totalAvailable > 0 {
+		for i := range ledger.Entries {
+			e := &ledger.Entries[i]
+			e.FairShare = float64(ledger.Total) * float64(e.Available) / float64(totalAvailable)
+		}
+	}
+
+	return ledger
+}
+
+// Entry returns the entry of the user.
+func (l *FairnessLedger) Entry(userID string) (LedgerEntry, bool) {
+	i := slices.IndexFunc(l.Entries, func(e LedgerEntry) bool { return e.ID == userID })
+	if i < 0 {
+		return LedgerEntry{}, false
+	}
+	return l.Entries[i], true
+}
+
+// period is a half-open interval [start, end).
+type period struct {
+	start, end time.Time
+}
+
+// inactivePeriods returns the parts of [from, to) the user was inactive in,
+// given the changes of the user's activity inside the window ordered by time.
+// The status before the first change is the opposite of it.
+func inactivePeriods(isActive bool, history []StatusHistoryEntry, from, to time.Time) []period {
+	if len(history) > 0 {
+		isActive = !history[0].IsActive
+	}
+
+	var periods []period
+	since := from
+	for _, h := range history {
+		if h.IsActive == isActive {
+			continue
+		}
+		if !isActive {
+			periods = append(periods, period{start: since, end: h.ChangedAt})
+		}
+		isActive, since = h.IsActive, h.ChangedAt
+	}
+	if !isActive {
+		periods = append(periods, period{start: since, end: to})
+	}
+
+	return periods
+}
+
+// available returns the length of [from, to) not covered by the periods, which
+// may overlap each other.
+func available(from, to time.Time, periods []period) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	slices.SortFunc(periods, func(a, b period) int { return a.start.Compare(b.start) })
+	free := to.Sub(from)
+	covered := from
+	for _, p := range periods {
+		start, end := later(p.start, covered), earlier(p.end, to)
+		if end.After(start) {
+			free -= end.Sub(start)
+			covered = end
+		}
+	}
+
+	return free
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,10 @@
+package entity
+
+// TeamLoad is a snapshot of the review load of a team.
+type TeamLoad struct {
+	TeamName string
+	// OpenPRs counts open PRs authored by members of the team.
+	OpenPRs int
+	// IdleReviewers counts active members without open reviews.
+	IdleReviewers int
+}
@@ -0,0 +1,54 @@
+package entity
+
+// OrgTeam is a team with the full list of its members, an entry of the
+// organization manifest.
+type OrgTeam struct {
+	Name    string
+	Members []User
+}
+
+// UserChange is a planned change of a user. Before is nil for a new user.
+type UserChange struct {
+	Before *User
+	After  User
+}
+
+// OpenReview is an assignment of a reviewer to an open PR.
+type OpenReview struct {
+	PRID       string
+	ReviewerID string
+	// TeamName is the team of the reviewer before the sync, the replacement is
+	// picked from it.
+	TeamName string
+}
+
+// Reassignment hands an open review of a user the sync deactivates over to
+// ReplacedBy.
+type Reassignment struct {
+	OpenReview
+	ReplacedBy string
+}
+
+// OrgPlan is the set of changes that brings the stored roster to a manifest.
+// Users missing from the manifest are deactivated rather than deleted, they
+// stay authors and reviewers of existing PRs.
+type OrgPlan struct {
+	AddTeams []string
+	// RemoveTeams are teams missing from the manifest with no members left
+	// after the sync.
+	RemoveTeams []string
+	Users       []UserChange
+	// Reassign are open reviews of users the plan deactivates with their
+	// replacements, written together with the roster changes.
+	Reassign []Reassignment
+}
+
+// Empty reports whether the stored roster already matches the manifest.
+func (p OrgPlan) Empty() bool {
+	return len(p.AddTeams) == 0 && len(p.RemoveTeams) == 0 && len(p.Users) == 0 && len(p.Reassign) == 0
+}
+
+type OrgSyncResult struct {
+	Plan   OrgPlan
+	DryRun bool
+}
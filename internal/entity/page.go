@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+const (
+	PRSortCreatedAt = "created_at"
+	PRSortTitle     = "title"
+
+	UserSortID   = "user_id"
+	UserSortName = "username"
+)
+
+// NoPageLimit as PageRequest.Limit asks for all items following the cursor.
+const NoPageLimit = -1
+
+// PageRequest asks for at most Limit items following the opaque Cursor returned
+// with the previous page. Zero Limit means the repository default.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+// Page is a slice of a listing. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+type Sort struct {
+	Field string
+	Order string
+}
+
+type PRFilter struct {
+	Status      string
+	AuthorID    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// TeamName matches PRs authored by members of the team.
+	TeamName      string
+	ReviewerID    string
+	TitleContains string
+	MergedFrom    *time.Time
+	MergedTo      *time.Time
+	// FewerReviewersThan matches PRs with less assigned reviewers than the value.
+	FewerReviewersThan *int
+}
+
+type PRListOptions struct {
+	Filter PRFilter
+	Sort   Sort
+	Page   PageRequest
+}
+
+func (s Sort) Desc() bool {
+	return s.Order == SortDesc
+}
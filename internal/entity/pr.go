@@ -16,6 +16,60 @@ type PR struct {
 	MergedAt  *time.Time `db:"merged_at"`
 }
 
+// PRWithReviewers is a PR together with ids of its assigned reviewers.
+type PRWithReviewers struct {
+	PR
+
+	Reviewers []string
+}
+
+// AssignedReviewer is a reviewer of a PR together with the assignment moment.
+type AssignedReviewer struct {
+	User
+
+	AssignedAt time.Time
+}
+
+// PRDetails is a PR with full information about its reviewers.
+type PRDetails struct {
+	PR
+
+	Reviewers []AssignedReviewer
+}
+
+// Age returns how long the PR exists at now.
+func (d PRDetails) Age(now time.Time) time.Duration {
+	return now.Sub(d.CreatedAt)
+}
+
+// TimeInReview returns how long the PR has been in review since the earliest
+// current assignment until merge or now. It is nil when nobody is assigned.
+func (d PRDetails) TimeInReview(now time.Time) *time.Duration {
+	if len(d.Reviewers) == 0 {
+		return nil
+	}
+	first := d.Reviewers[0].AssignedAt
+	for _, r := range d.Reviewers[1:] {
+		if r.AssignedAt.Before(first) {
+			first = r.AssignedAt
+		}
+	}
+	inReview := d.reviewEnd(now).Sub(first)
+	return &inReview
+}
+
+// ReviewerTimeInReview returns how long the reviewer has been assigned until merge or now.
+func (d PRDetails) ReviewerTimeInReview(r AssignedReviewer, now time.Time) time.Duration {
+	return d.reviewEnd(now).Sub(r.AssignedAt)
+}
+
+func (d PRDetails) reviewEnd(now time.Time) time.Time {
+	if d.MergedAt != nil {
+		return *d.MergedAt
+	}
+	return now
+}
+
 type PRReviewer struct {
 	PRID       string    `db:"pr_id"`
 	ReviewerID string    `db:"reviewer_id"`
@@ -32,3 +86,17 @@ func NewPR(id, title, authorID string) *PR {
 		MergedAt:  nil,
 	}
 }
+
+// ReassignOptions narrows the choice of the replacement reviewer. Empty fields
+// keep the default: a random eligible member of the old reviewer's team.
+type ReassignOptions struct {
+	// NewUserID is the replacement chosen by hand.
+	NewUserID string
+	// CandidateTeam is the team to pick the replacement from.
+	CandidateTeam string
+	// AllowRemoved lets reviewers previously taken off the PR be picked again.
+	AllowRemoved bool
+	// Exclude are users the random pick must skip, e.g. the ones an org sync
+	// deactivates or moves to another team.
+	Exclude []string
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const clockLayout = "15:04"
+
+type WorkSchedule struct {
+	UserID    string    `db:"user_id"`
+	Timezone  string    `db:"timezone"`
+	WorkStart string    `db:"work_start"`
+	WorkEnd   string    `db:"work_end"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type OutOfOffice struct {
+	ID        int64     `db:"id"`
+	UserID    string    `db:"user_id"`
+	StartsAt  time.Time `db:"starts_at"`
+	EndsAt    time.Time `db:"ends_at"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func NewWorkSchedule(userID, timezone, workStart, workEnd string) *WorkSchedule {
+	return &WorkSchedule{
+		UserID:    userID,
+		Timezone:  timezone,
+		WorkStart: workStart,
+		WorkEnd:   workEnd,
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+func NewOutOfOffice(userID string, startsAt, endsAt time.Time, reason string) *OutOfOffice {
+	return &OutOfOffice{
+		UserID:    userID,
+		StartsAt:  startsAt.UTC(),
+		EndsAt:    endsAt.UTC(),
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// ParseClock parses "HH:MM" into the offset from midnight.
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse(clockLayout, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock %q, expected HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Validate checks that the timezone is known and the working window is well-formed.
+func (s WorkSchedule) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	start, err := ParseClock(s.WorkStart)
+	if err != nil {
+		return err
+	}
+	end, err := ParseClock(s.WorkEnd)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return errors.New("work_start and work_end must differ")
+	}
+	return nil
+}
+
+// IsWorkingAt reports whether t falls into the working window in the user's
+// timezone. Windows with start after end span midnight (e.g. 22:00-06:00).
+// A malformed schedule never makes the user unavailable.
+func (s WorkSchedule) IsWorkingAt(t time.Time) bool {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return true
+	}
+	start, err := ParseClock(s.WorkStart)
+	if err != nil {
+		return true
+	}
+	end, err := ParseClock(s.WorkEnd)
+	if err != nil {
+		return true
+	}
+
+	local := t.In(loc)
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if start < end {
+		return sinceMidnight >= start && sinceMidnight < end
+	}
+	return sinceMidnight >= start || sinceMidnight < end
+}
+
+// CoversAt reports whether the out-of-office period is in effect at t.
+func (o OutOfOffice) CoversAt(t time.Time) bool {
+	return !t.Before(o.StartsAt) && t.Before(o.EndsAt)
+}
@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+const (
+	EscalationModeNone        = "none"
+	EscalationModeReassign    = "reassign"
+	EscalationModeAddReviewer = "add_reviewer"
+)
+
+const (
+	EscalationActionNone          = "NONE"
+	EscalationActionReassigned    = "REASSIGNED"
+	EscalationActionReviewerAdded = "REVIEWER_ADDED"
+)
+
+// OverdueAssignment is a review assignment on an open PR that exceeded the
+// SLA of the reviewer's team.
+type OverdueAssignment struct {
+	PRID       string        `db:"pr_id"`
+	ReviewerID string        `db:"reviewer_id"`
+	TeamName   string        `db:"team_name"`
+	AssignedAt time.Time     `db:"assigned_at"`
+	SLA        time.Duration `db:"review_sla_seconds"`
+}
+
+func (o OverdueAssignment) DueAt() time.Time {
+	return o.AssignedAt.Add(o.SLA)
+}
+
+type Escalation struct {
+	ID            int64     `db:"id"`
+	PRID          string    `db:"pr_id"`
+	ReviewerID    string    `db:"reviewer_id"`
+	TeamName      string    `db:"team_name"`
+	AssignedAt    time.Time `db:"assigned_at"`
+	DueAt         time.Time `db:"due_at"`
+	DetectedAt    time.Time `db:"detected_at"`
+	Action        string    `db:"action"`
+	NewReviewerID *string   `db:"new_reviewer_id"`
+}
+
+// ReviewStats aggregates review timings of PRs authored by a team. Time to
+// first review is not reported: review actions are not recorded by the service.
+type ReviewStats struct {
+	TeamName           string
+	SLA                *time.Duration
+	PRCount            int
+	MergedCount        int
+	AvgTimeToMerge     time.Duration
+	OpenAssignments    int
+	OverdueAssignments int
+	// Declines counts reviews declined by team members per reason code.
+	Declines map[string]int
+}
+
+func NewEscalation(o OverdueAssignment, detectedAt time.Time) *Escalation {
+	return &Escalation{
+		PRID:       o.PRID,
+		ReviewerID: o.ReviewerID,
+		TeamName:   o.TeamName,
+		AssignedAt: o.AssignedAt,
+		DueAt:      o.DueAt(),
+		DetectedAt: detectedAt.UTC(),
+		Action:     EscalationActionNone,
+	}
+}
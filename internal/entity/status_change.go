@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// StatusChange is a deferred update of the user's is_active flag.
+type StatusChange struct {
+	ID        int64      `db:"id"`
+	UserID    string     `db:"user_id"`
+	IsActive  bool       `db:"is_active"`
+	ApplyAt   time.Time  `db:"apply_at"`
+	AppliedAt *time.Time `db:"applied_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func NewStatusChange(userID string, isActive bool, applyAt time.Time) *StatusChange {
+	return &StatusChange{
+		UserID:    userID,
+		IsActive:  isActive,
+		ApplyAt:   applyAt.UTC(),
+		AppliedAt: nil,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// StatusHistoryEntry records the moment the user's is_active flag changed.
+type StatusHistoryEntry struct {
+	UserID    string    `db:"user_id"`
+	IsActive  bool      `db:"is_active"`
+	ChangedAt time.Time `db:"changed_at"`
+}
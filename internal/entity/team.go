@@ -8,11 +8,15 @@ type User struct {
 	TeamName  string    `db:"team_name"`
 	IsActive  bool      `db:"is_active"`
 	CreatedAt time.Time `db:"created_at"`
+	// MaxOpenReviews overrides the team limit of concurrent open reviews.
+	MaxOpenReviews *int `db:"max_open_reviews"`
 }
 
 type Team struct {
 	Name      string    `db:"name"`
 	CreatedAt time.Time `db:"created_at"`
+	// MaxOpenReviews limits concurrent open reviews of every member, nil means no limit.
+	MaxOpenReviews *int `db:"max_open_reviews"`
 }
 
 func NewUser(id, name, teamName string, isActive bool) *User {
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+type PullRequestDeclineResponse struct {
+	PR      PullRequest   `json:"pr"`
+	Decline ReviewDecline `json:"decline"`
+}
+
+func ReviewDeclineFromEntity(d entity.Decline) ReviewDecline {
+	return ReviewDecline{
+		Id:            d.ID,
+		PullRequestId: d.PRID,
+		ReviewerId:    d.ReviewerID,
+		Reason:        DeclineReason(d.Reason),
+		Comment:       d.Comment,
+		ReplacedBy:    d.NewReviewerID,
+		CreatedAt:     d.CreatedAt,
+	}
+}
+
+func (s *Server) PostPullRequestDecline(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to decline pull request review")
+	var body PostPullRequestDeclineJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.PullRequestId == "" || body.UserId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id and user_id are required")
+		return
+	}
+
+	pr, decline, err := s.PRUseCase.DeclineReview(r.Context(), body.PullRequestId, body.UserId,
+		string(body.Reason), derefString(body.Comment))
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	assigned, err := s.PRUseCase.GetAssignedReviewers(r.Context(), pr.ID)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := PullRequestDeclineResponse{
+		PR:      s.prToRepsponse(pr, assigned).PR,
+		Decline: ReviewDeclineFromEntity(*decline),
+	}
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to decline pull request review processed successfully")
+}
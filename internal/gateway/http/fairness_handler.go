@@ -0,0 +1,51 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+const day = 24 * time.Hour
+
+func FairnessLedgerFromEntity(l entity.FairnessLedger) FairnessLedger {
+	ledger := FairnessLedger{
+		TeamName:         l.TeamName,
+		From:             l.From,
+		To:               l.To,
+		TotalAssignments: l.Total,
+		Members:          make([]FairnessLedgerEntry, 0, len(l.Entries)),
+	}
+	for _, e := range l.Entries {
+		ledger.Members = append(ledger.Members, FairnessLedgerEntry{
+			UserId:        e.ID,
+			Username:      e.Name,
+			IsActive:      e.IsActive,
+			Assignments:   e.Assignments,
+			AvailableDays: float64(e.Available) / float64(day),
+			FairShare:     e.FairShare,
+			Balance:       e.Balance(),
+		})
+	}
+	return ledger
+}
+
+func (s *Server) GetTeamFairness(w nethttp.ResponseWriter, r *nethttp.Request, params GetTeamFairnessParams) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get team fairness ledger")
+	if params.TeamName == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
+		return
+	}
+
+	ledger, err := s.PRUseCase.GetFairnessLedger(r.Context(), params.TeamName)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, FairnessLedgerFromEntity(*ledger))
+	logging.FromContext(r.Context(), s.log).Debug("Request to get team fairness ledger processed successfully")
+}
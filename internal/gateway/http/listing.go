@@ -0,0 +1,39 @@
+package http
+
+import (
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func pageFromParams(limit *LimitQuery, cursor *CursorQuery) entity.PageRequest {
+	var page entity.PageRequest
+	if limit != nil {
+		page.Limit = *limit
+	}
+	if cursor != nil {
+		page.Cursor = *cursor
+	}
+	return page
+}
+
+func validSortOrder(order string) bool {
+	return order == "" || order == entity.SortAsc || order == entity.SortDesc
+}
+
+func validPRStatus(status string) bool {
+	return status == "" || status == entity.PRStatusOpen || status == entity.PRStatusMerged
+}
+
+func derefString[T ~string](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return string(*v)
+}
+
+// nextCursorPtr turns an empty cursor of the last page into an omitted field.
+func nextCursorPtr(cursor string) *string {
+	if cursor == "" {
+		return nil
+	}
+	return &cursor
+}
@@ -1,20 +1,48 @@
 // Package http provides primitives to interact with the openapi HTTP API.
 //
-// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.1 DO NOT EDIT.
 package http
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+// Defines values for AssignmentCandidateExclusionReason.
+const (
+	ALREADYASSIGNED     AssignmentCandidateExclusionReason = "ALREADY_ASSIGNED"
+	ATCAPACITY          AssignmentCandidateExclusionReason = "AT_CAPACITY"
+	AUTHOR              AssignmentCandidateExclusionReason = "AUTHOR"
+	DECLINED            AssignmentCandidateExclusionReason = "DECLINED"
+	EXCLUDED            AssignmentCandidateExclusionReason = "EXCLUDED"
+	INACTIVE            AssignmentCandidateExclusionReason = "INACTIVE"
+	OUTOFOFFICE         AssignmentCandidateExclusionReason = "OUT_OF_OFFICE"
+	OUTSIDEWORKINGHOURS AssignmentCandidateExclusionReason = "OUTSIDE_WORKING_HOURS"
+	PREVIOUSLYREMOVED   AssignmentCandidateExclusionReason = "PREVIOUSLY_REMOVED"
+)
+
+// Defines values for DeclineReason.
+const (
+	CONFLICTOFINTEREST DeclineReason = "CONFLICT_OF_INTEREST"
+	LACKINGCONTEXT     DeclineReason = "LACKING_CONTEXT"
+	NOTIME             DeclineReason = "NO_TIME"
+	OTHER              DeclineReason = "OTHER"
 )
 
 // Defines values for ErrorResponseErrorCode.
 const (
-	NOCANDIDATE ErrorResponseErrorCode = "NO_CANDIDATE"
-	NOTASSIGNED ErrorResponseErrorCode = "NOT_ASSIGNED"
-	NOTFOUND    ErrorResponseErrorCode = "NOT_FOUND"
-	PREXISTS    ErrorResponseErrorCode = "PR_EXISTS"
-	PRMERGED    ErrorResponseErrorCode = "PR_MERGED"
-	TEAMEXISTS  ErrorResponseErrorCode = "TEAM_EXISTS"
+	ALLATCAPACITY  ErrorResponseErrorCode = "ALL_AT_CAPACITY"
+	NOCANDIDATE    ErrorResponseErrorCode = "NO_CANDIDATE"
+	NOTASSIGNED    ErrorResponseErrorCode = "NOT_ASSIGNED"
+	NOTELIGIBLE    ErrorResponseErrorCode = "NOT_ELIGIBLE"
+	NOTFOUND       ErrorResponseErrorCode = "NOT_FOUND"
+	PREXISTS       ErrorResponseErrorCode = "PR_EXISTS"
+	PRMERGED       ErrorResponseErrorCode = "PR_MERGED"
+	REVIEWEREXISTS ErrorResponseErrorCode = "REVIEWER_EXISTS"
+	REVIEWERSFULL  ErrorResponseErrorCode = "REVIEWERS_FULL"
+	TEAMEXISTS     ErrorResponseErrorCode = "TEAM_EXISTS"
 )
 
 // Defines values for PullRequestStatus.
@@ -29,6 +57,129 @@ const (
 	PullRequestShortStatusOPEN   PullRequestShortStatus = "OPEN"
 )
 
+// Defines values for ReviewEscalationAction.
+const (
+	NONE          ReviewEscalationAction = "NONE"
+	REASSIGNED    ReviewEscalationAction = "REASSIGNED"
+	REVIEWERADDED ReviewEscalationAction = "REVIEWER_ADDED"
+)
+
+// Defines values for ReviewerAuditEntryAction.
+const (
+	ADDED   ReviewerAuditEntryAction = "ADDED"
+	REMOVED ReviewerAuditEntryAction = "REMOVED"
+)
+
+// Defines values for OrderQuery.
+const (
+	OrderQueryAsc  OrderQuery = "asc"
+	OrderQueryDesc OrderQuery = "desc"
+)
+
+// Defines values for PRSortByQuery.
+const (
+	PRSortByQueryCreatedAt PRSortByQuery = "created_at"
+	PRSortByQueryTitle     PRSortByQuery = "title"
+)
+
+// Defines values for PRStatusQuery.
+const (
+	PRStatusQueryMERGED PRStatusQuery = "MERGED"
+	PRStatusQueryOPEN   PRStatusQuery = "OPEN"
+)
+
+// Defines values for GetPullRequestListParamsStatus.
+const (
+	GetPullRequestListParamsStatusMERGED GetPullRequestListParamsStatus = "MERGED"
+	GetPullRequestListParamsStatusOPEN   GetPullRequestListParamsStatus = "OPEN"
+)
+
+// Defines values for GetPullRequestListParamsSortBy.
+const (
+	GetPullRequestListParamsSortByCreatedAt GetPullRequestListParamsSortBy = "created_at"
+	GetPullRequestListParamsSortByTitle     GetPullRequestListParamsSortBy = "title"
+)
+
+// Defines values for GetPullRequestListParamsOrder.
+const (
+	GetPullRequestListParamsOrderAsc  GetPullRequestListParamsOrder = "asc"
+	GetPullRequestListParamsOrderDesc GetPullRequestListParamsOrder = "desc"
+)
+
+// Defines values for GetTeamGetParamsSortBy.
+const (
+	UserId   GetTeamGetParamsSortBy = "user_id"
+	Username GetTeamGetParamsSortBy = "username"
+)
+
+// Defines values for GetTeamGetParamsOrder.
+const (
+	GetTeamGetParamsOrderAsc  GetTeamGetParamsOrder = "asc"
+	GetTeamGetParamsOrderDesc GetTeamGetParamsOrder = "desc"
+)
+
+// Defines values for GetUsersGetReviewParamsStatus.
+const (
+	MERGED GetUsersGetReviewParamsStatus = "MERGED"
+	OPEN   GetUsersGetReviewParamsStatus = "OPEN"
+)
+
+// Defines values for GetUsersGetReviewParamsSortBy.
+const (
+	CreatedAt GetUsersGetReviewParamsSortBy = "created_at"
+	Title     GetUsersGetReviewParamsSortBy = "title"
+)
+
+// Defines values for GetUsersGetReviewParamsOrder.
+const (
+	GetUsersGetReviewParamsOrderAsc  GetUsersGetReviewParamsOrder = "asc"
+	GetUsersGetReviewParamsOrderDesc GetUsersGetReviewParamsOrder = "desc"
+)
+
+// AssignedReviewer defines model for AssignedReviewer.
+type AssignedReviewer struct {
+	AssignedAt time.Time `json:"assigned_at"`
+	IsActive   bool      `json:"is_active"`
+	TeamName   string    `json:"team_name"`
+
+	// TimeInReviewSeconds Время с момента назначения до merge (или до текущего момента для открытого PR)
+	TimeInReviewSeconds int64  `json:"time_in_review_seconds"`
+	UserId              string `json:"user_id"`
+	Username            string `json:"username"`
+}
+
+// AssignmentCandidate defines model for AssignmentCandidate.
+type AssignmentCandidate struct {
+	// Eligible Может ли пользователь быть выбран ревьювером
+	Eligible bool `json:"eligible"`
+
+	// ExclusionReason Почему пользователь исключен из выбора (отсутствует у подходящих кандидатов). EXCLUDED - пользователь деактивируется или переводится в другую команду синхронизацией оргструктуры
+	ExclusionReason *AssignmentCandidateExclusionReason `json:"exclusion_reason,omitempty"`
+	IsActive        bool                                `json:"is_active"`
+	UserId          string                              `json:"user_id"`
+	Username        string                              `json:"username"`
+}
+
+// AssignmentCandidateExclusionReason Почему пользователь исключен из выбора (отсутствует у подходящих кандидатов). EXCLUDED - пользователь деактивируется или переводится в другую команду синхронизацией оргструктуры
+type AssignmentCandidateExclusionReason string
+
+// AssignmentSimulation defines model for AssignmentSimulation.
+type AssignmentSimulation struct {
+	Candidates []AssignmentCandidate `json:"candidates"`
+
+	// DryRun Всегда true, изменения не сохранены
+	DryRun bool `json:"dry_run"`
+
+	// Reviewers Ревьюверы, которые были бы назначены. Выбор случайный среди подходящих кандидатов
+	Reviewers []string `json:"reviewers"`
+
+	// TeamName Команда, из которой выбираются ревьюверы
+	TeamName string `json:"team_name"`
+}
+
+// DeclineReason Код причины отказа от ревью
+type DeclineReason string
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error struct {
@@ -40,6 +191,101 @@ type ErrorResponse struct {
 // ErrorResponseErrorCode defines model for ErrorResponse.Error.Code.
 type ErrorResponseErrorCode string
 
+// FairnessLedger defines model for FairnessLedger.
+type FairnessLedger struct {
+	// From Начало скользящего окна
+	From     time.Time             `json:"from"`
+	Members  []FairnessLedgerEntry `json:"members"`
+	TeamName string                `json:"team_name"`
+	To       time.Time             `json:"to"`
+
+	// TotalAssignments Назначения участников команды за окно
+	TotalAssignments int `json:"total_assignments"`
+}
+
+// FairnessLedgerEntry defines model for FairnessLedgerEntry.
+type FairnessLedgerEntry struct {
+	// Assignments Назначения пользователя ревьювером за окно
+	Assignments int `json:"assignments"`
+
+	// AvailableDays Дни окна, когда пользователь был в команде, был активен и не был в отпуске
+	AvailableDays float64 `json:"available_days"`
+
+	// Balance assignments - fair_share; отрицательное значение - недобор, положительное - перебор
+	Balance float64 `json:"balance"`
+
+	// FairShare Справедливая доля назначений команды пропорционально available_days
+	FairShare float64 `json:"fair_share"`
+	IsActive  bool    `json:"is_active"`
+	UserId    string  `json:"user_id"`
+	Username  string  `json:"username"`
+}
+
+// OrgManifest Полный состав организации - все команды со всеми участниками
+type OrgManifest struct {
+	Teams []OrgManifestTeam `json:"teams"`
+}
+
+// OrgManifestMember defines model for OrgManifestMember.
+type OrgManifestMember struct {
+	IsActive *bool  `json:"is_active,omitempty"`
+	UserId   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// OrgManifestTeam defines model for OrgManifestTeam.
+type OrgManifestTeam struct {
+	Members  []OrgManifestMember `json:"members"`
+	TeamName string              `json:"team_name"`
+}
+
+// OrgReassignment defines model for OrgReassignment.
+type OrgReassignment struct {
+	PullRequestId string `json:"pull_request_id"`
+
+	// ReplacedBy Новый ревьювер
+	ReplacedBy string `json:"replaced_by"`
+
+	// ReviewerId Деактивируемый ревьювер
+	ReviewerId string `json:"reviewer_id"`
+
+	// TeamName Команда, из которой выбрана замена
+	TeamName string `json:"team_name"`
+}
+
+// OrgSyncPlan defines model for OrgSyncPlan.
+type OrgSyncPlan struct {
+	AddTeams []string `json:"add_teams"`
+
+	// Reassign Открытые ревью деактивируемых пользователей и их новые ревьюверы
+	Reassign []OrgReassignment `json:"reassign"`
+
+	// RemoveTeams Команды, отсутствующие в манифесте, в которых не осталось участников
+	RemoveTeams []string        `json:"remove_teams"`
+	Users       []OrgUserChange `json:"users"`
+}
+
+// OrgSyncResult defines model for OrgSyncResult.
+type OrgSyncResult struct {
+	DryRun bool        `json:"dry_run"`
+	Plan   OrgSyncPlan `json:"plan"`
+}
+
+// OrgUserChange Пользователь после синхронизации и до нее (before отсутствует у новых пользователей)
+type OrgUserChange struct {
+	Before *User `json:"before,omitempty"`
+	User   User  `json:"user"`
+}
+
+// OutOfOffice defines model for OutOfOffice.
+type OutOfOffice struct {
+	EndsAt   time.Time `json:"ends_at"`
+	OooId    int64     `json:"ooo_id"`
+	Reason   string    `json:"reason"`
+	StartsAt time.Time `json:"starts_at"`
+	UserId   string    `json:"user_id"`
+}
+
 // PullRequest defines model for PullRequest.
 type PullRequest struct {
 	// AssignedReviewers user_id назначенных ревьюверов (0..2)
@@ -66,40 +312,284 @@ type PullRequestShort struct {
 // PullRequestShortStatus defines model for PullRequestShort.Status.
 type PullRequestShortStatus string
 
+// ReassignResponse ReassignResult после переназначения или AssignmentSimulation при dry_run
+type ReassignResponse struct {
+	union json.RawMessage
+}
+
+// ReassignResult defines model for ReassignResult.
+type ReassignResult struct {
+	Pr PullRequest `json:"pr"`
+
+	// ReplacedBy user_id нового ревьювера
+	ReplacedBy string `json:"replaced_by"`
+}
+
+// ReviewDecline defines model for ReviewDecline.
+type ReviewDecline struct {
+	// Comment Комментарий ревьювера, пустая строка, если не указан
+	Comment       string    `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+	Id            int64     `json:"id"`
+	PullRequestId string    `json:"pull_request_id"`
+
+	// Reason Код причины отказа от ревью
+	Reason DeclineReason `json:"reason"`
+
+	// ReplacedBy user_id ревьювера, назначенного вместо отказавшегося
+	ReplacedBy string `json:"replaced_by"`
+
+	// ReviewerId Ревьювер, отказавшийся от ревью
+	ReviewerId string `json:"reviewer_id"`
+}
+
+// ReviewEscalation defines model for ReviewEscalation.
+type ReviewEscalation struct {
+	// Action Автоматическое действие, выполненное при эскалации
+	Action     ReviewEscalationAction `json:"action"`
+	AssignedAt time.Time              `json:"assigned_at"`
+	DetectedAt time.Time              `json:"detected_at"`
+	DueAt      time.Time              `json:"due_at"`
+
+	// NewReviewerId user_id ревьювера, назначенного при эскалации
+	NewReviewerId *string `json:"new_reviewer_id"`
+	PullRequestId string  `json:"pull_request_id"`
+	ReviewerId    string  `json:"reviewer_id"`
+	TeamName      string  `json:"team_name"`
+}
+
+// ReviewEscalationAction Автоматическое действие, выполненное при эскалации
+type ReviewEscalationAction string
+
+// ReviewStats defines model for ReviewStats.
+type ReviewStats struct {
+	// AvgTimeToMergeSeconds Среднее время от создания PR до merge
+	AvgTimeToMergeSeconds int64 `json:"avg_time_to_merge_seconds"`
+
+	// Declines Отказы участников команды от ревью по кодам причин (/pullRequest/decline)
+	Declines    map[string]int `json:"declines"`
+	MergedCount int            `json:"merged_count"`
+
+	// OpenAssignments Назначения участников команды на открытые PR
+	OpenAssignments int `json:"open_assignments"`
+
+	// OverdueAssignments Назначения участников команды, превысившие SLA
+	OverdueAssignments int `json:"overdue_assignments"`
+
+	// PrCount Количество PR, созданных участниками команды
+	PrCount int `json:"pr_count"`
+
+	// ReviewSla SLA ревью команды (например, 24h)
+	ReviewSla *string `json:"review_sla"`
+	TeamName  string  `json:"team_name"`
+}
+
+// ReviewerAuditEntry defines model for ReviewerAuditEntry.
+type ReviewerAuditEntry struct {
+	Action ReviewerAuditEntryAction `json:"action"`
+
+	// Actor Кто выполнил изменение (из запроса), пустая строка, если не указан
+	Actor         string    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+	Id            int64     `json:"id"`
+	PullRequestId string    `json:"pull_request_id"`
+	ReviewerId    string    `json:"reviewer_id"`
+}
+
+// ReviewerAuditEntryAction defines model for ReviewerAuditEntry.Action.
+type ReviewerAuditEntryAction string
+
+// ReviewerChangeResponse defines model for ReviewerChangeResponse.
+type ReviewerChangeResponse struct {
+	Audit ReviewerAuditEntry `json:"audit"`
+	Pr    PullRequest        `json:"pr"`
+}
+
+// ScheduledStatusChange defines model for ScheduledStatusChange.
+type ScheduledStatusChange struct {
+	ApplyAt  time.Time `json:"apply_at"`
+	ChangeId int64     `json:"change_id"`
+	IsActive bool      `json:"is_active"`
+	UserId   string    `json:"user_id"`
+}
+
 // Team defines model for Team.
 type Team struct {
-	Members  []TeamMember `json:"members"`
-	TeamName string       `json:"team_name"`
+	// MaxOpenReviews Лимит открытых ревью на участника команды, null - без лимита (задается через /team/setMaxOpenReviews)
+	MaxOpenReviews *int         `json:"max_open_reviews"`
+	Members        []TeamMember `json:"members"`
+	TeamName       string       `json:"team_name"`
 }
 
 // TeamMember defines model for TeamMember.
 type TeamMember struct {
-	IsActive bool   `json:"is_active"`
-	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+	IsActive bool `json:"is_active"`
+
+	// MaxOpenReviews Собственный лимит открытых ревью пользователя, перекрывает лимит команды (задается через /users/setMaxOpenReviews)
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
 }
 
 // User defines model for User.
 type User struct {
-	IsActive bool   `json:"is_active"`
-	TeamName string `json:"team_name"`
+	IsActive bool `json:"is_active"`
+
+	// MaxOpenReviews Собственный лимит открытых ревью пользователя, null - действует лимит команды
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	TeamName       string `json:"team_name"`
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+}
+
+// WorkSchedule defines model for WorkSchedule.
+type WorkSchedule struct {
+	// Timezone Часовой пояс IANA (например, Europe/Moscow)
+	Timezone string `json:"timezone"`
 	UserId   string `json:"user_id"`
-	Username string `json:"username"`
+
+	// WorkEnd Конец рабочего дня в формате HH:MM (локальное время пользователя)
+	WorkEnd string `json:"work_end"`
+
+	// WorkStart Начало рабочего дня в формате HH:MM (локальное время пользователя)
+	WorkStart string `json:"work_start"`
 }
 
+// AuthorIdQuery defines model for AuthorIdQuery.
+type AuthorIdQuery = string
+
+// CreatedFromQuery defines model for CreatedFromQuery.
+type CreatedFromQuery = time.Time
+
+// CreatedToQuery defines model for CreatedToQuery.
+type CreatedToQuery = time.Time
+
+// CursorQuery defines model for CursorQuery.
+type CursorQuery = string
+
+// FewerReviewersThanQuery defines model for FewerReviewersThanQuery.
+type FewerReviewersThanQuery = int
+
+// LimitQuery defines model for LimitQuery.
+type LimitQuery = int
+
+// MergedFromQuery defines model for MergedFromQuery.
+type MergedFromQuery = time.Time
+
+// MergedToQuery defines model for MergedToQuery.
+type MergedToQuery = time.Time
+
+// OrderQuery defines model for OrderQuery.
+type OrderQuery string
+
+// PRSortByQuery defines model for PRSortByQuery.
+type PRSortByQuery string
+
+// PRStatusQuery defines model for PRStatusQuery.
+type PRStatusQuery string
+
+// ReviewerIdQuery defines model for ReviewerIdQuery.
+type ReviewerIdQuery = string
+
+// TeamNameFilterQuery defines model for TeamNameFilterQuery.
+type TeamNameFilterQuery = string
+
 // TeamNameQuery defines model for TeamNameQuery.
 type TeamNameQuery = string
 
+// TitleQuery defines model for TitleQuery.
+type TitleQuery = string
+
 // UserIdQuery defines model for UserIdQuery.
 type UserIdQuery = string
 
+// PostOrgSyncParams defines parameters for PostOrgSync.
+type PostOrgSyncParams struct {
+	// DryRun Только построить план, ничего не меняя
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
 // PostPullRequestCreateJSONBody defines parameters for PostPullRequestCreate.
 type PostPullRequestCreateJSONBody struct {
-	AuthorId        string `json:"author_id"`
+	AuthorId string `json:"author_id"`
+
+	// DryRun Только выполнить выбор ревьюверов и вернуть кандидатов, ничего не сохраняя
+	DryRun          *bool  `json:"dry_run,omitempty"`
 	PullRequestId   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 }
 
+// PostPullRequestDeclineJSONBody defines parameters for PostPullRequestDecline.
+type PostPullRequestDeclineJSONBody struct {
+	Comment       *string `json:"comment,omitempty"`
+	PullRequestId string  `json:"pull_request_id"`
+
+	// Reason Код причины отказа от ревью
+	Reason DeclineReason `json:"reason"`
+
+	// UserId Ревьювер, который отказывается от ревью
+	UserId string `json:"user_id"`
+}
+
+// GetPullRequestGetParams defines parameters for GetPullRequestGet.
+type GetPullRequestGetParams struct {
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// GetPullRequestListParams defines parameters for GetPullRequestList.
+type GetPullRequestListParams struct {
+	// TeamName PR, авторы которых состоят в команде
+	TeamName *TeamNameFilterQuery `form:"team_name,omitempty" json:"team_name,omitempty"`
+
+	// AuthorId Фильтр по автору PR
+	AuthorId *AuthorIdQuery `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// ReviewerId PR, на которые назначен ревьювер
+	ReviewerId *ReviewerIdQuery `form:"reviewer_id,omitempty" json:"reviewer_id,omitempty"`
+
+	// Status Фильтр по статусу PR
+	Status *GetPullRequestListParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Title Подстрока названия PR (без учёта регистра)
+	Title *TitleQuery `form:"title,omitempty" json:"title,omitempty"`
+
+	// CreatedFrom PR созданы не раньше этого момента
+	CreatedFrom *CreatedFromQuery `form:"created_from,omitempty" json:"created_from,omitempty"`
+
+	// CreatedTo PR созданы раньше этого момента
+	CreatedTo *CreatedToQuery `form:"created_to,omitempty" json:"created_to,omitempty"`
+
+	// MergedFrom PR смёржены не раньше этого момента
+	MergedFrom *MergedFromQuery `form:"merged_from,omitempty" json:"merged_from,omitempty"`
+
+	// MergedTo PR смёржены раньше этого момента
+	MergedTo *MergedToQuery `form:"merged_to,omitempty" json:"merged_to,omitempty"`
+
+	// FewerReviewersThan PR, у которых назначено меньше ревьюверов, чем указано
+	FewerReviewersThan *FewerReviewersThanQuery `form:"fewer_reviewers_than,omitempty" json:"fewer_reviewers_than,omitempty"`
+
+	// SortBy Поле сортировки PR
+	SortBy *GetPullRequestListParamsSortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// Order Направление сортировки
+	Order *GetPullRequestListParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Limit Максимальное количество элементов на странице. Без параметра /pullRequest/list отдает страницу из 50 PR, а /team/get и /users/getReview, как и до пагинации, отдают весь список после курсора
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из поля next_cursor предыдущего ответа
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetPullRequestListParamsStatus defines parameters for GetPullRequestList.
+type GetPullRequestListParamsStatus string
+
+// GetPullRequestListParamsSortBy defines parameters for GetPullRequestList.
+type GetPullRequestListParamsSortBy string
+
+// GetPullRequestListParamsOrder defines parameters for GetPullRequestList.
+type GetPullRequestListParamsOrder string
+
 // PostPullRequestMergeJSONBody defines parameters for PostPullRequestMerge.
 type PostPullRequestMergeJSONBody struct {
 	PullRequestId string `json:"pull_request_id"`
@@ -107,20 +597,154 @@ type PostPullRequestMergeJSONBody struct {
 
 // PostPullRequestReassignJSONBody defines parameters for PostPullRequestReassign.
 type PostPullRequestReassignJSONBody struct {
-	OldUserId     string `json:"old_user_id"`
-	PullRequestId string `json:"pull_request_id"`
+	// AllowRemoved Разрешить выбор ревьюверов, ранее снятых с этого PR (переназначением, отказом или вручную). По умолчанию они исключаются с причиной PREVIOUSLY_REMOVED
+	AllowRemoved *bool `json:"allow_removed,omitempty"`
+
+	// CandidateTeam Команда, из которой выбирается замена (по умолчанию команда заменяемого ревьювера)
+	CandidateTeam *string `json:"candidate_team,omitempty"`
+
+	// DryRun Только выполнить выбор замены и вернуть кандидатов, ничего не сохраняя. Пустой reviewers в ответе означает, что реальный вызов вернет NO_CANDIDATE
+	DryRun *bool `json:"dry_run,omitempty"`
+
+	// NewUserId Передать ревью конкретному пользователю вместо случайного выбора. Он должен быть активен, не быть автором или уже назначенным ревьювером, не достигать лимита открытых ревью и состоять в команде, из которой выбирается замена (иначе NOT_ELIGIBLE)
+	NewUserId     *string `json:"new_user_id,omitempty"`
+	OldUserId     string  `json:"old_user_id"`
+	PullRequestId string  `json:"pull_request_id"`
+}
+
+// PostPullRequestReviewersAddJSONBody defines parameters for PostPullRequestReviewersAdd.
+type PostPullRequestReviewersAddJSONBody struct {
+	// Actor Кто выполняет изменение, сохраняется в аудите
+	Actor         *string `json:"actor,omitempty"`
+	PullRequestId string  `json:"pull_request_id"`
+
+	// UserId Кого добавить. Если не указан, ревьювер выбирается из команды автора
+	UserId *string `json:"user_id,omitempty"`
+}
+
+// GetPullRequestReviewersAuditParams defines parameters for GetPullRequestReviewersAudit.
+type GetPullRequestReviewersAuditParams struct {
+	PullRequestId string `form:"pull_request_id" json:"pull_request_id"`
+}
+
+// PostPullRequestReviewersRemoveJSONBody defines parameters for PostPullRequestReviewersRemove.
+type PostPullRequestReviewersRemoveJSONBody struct {
+	// Actor Кто выполняет изменение, сохраняется в аудите
+	Actor         *string `json:"actor,omitempty"`
+	PullRequestId string  `json:"pull_request_id"`
+	UserId        string  `json:"user_id"`
+}
+
+// GetTeamFairnessParams defines parameters for GetTeamFairness.
+type GetTeamFairnessParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
 }
 
 // GetTeamGetParams defines parameters for GetTeamGet.
 type GetTeamGetParams struct {
 	// TeamName Уникальное имя команды
 	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+
+	// SortBy Поле сортировки участников
+	SortBy *GetTeamGetParamsSortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// Order Направление сортировки
+	Order *GetTeamGetParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Limit Максимальное количество элементов на странице. Без параметра /pullRequest/list отдает страницу из 50 PR, а /team/get и /users/getReview, как и до пагинации, отдают весь список после курсора
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из поля next_cursor предыдущего ответа
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetTeamGetParamsSortBy defines parameters for GetTeamGet.
+type GetTeamGetParamsSortBy string
+
+// GetTeamGetParamsOrder defines parameters for GetTeamGet.
+type GetTeamGetParamsOrder string
+
+// GetTeamReviewStatsParams defines parameters for GetTeamReviewStats.
+type GetTeamReviewStatsParams struct {
+	// TeamName Уникальное имя команды
+	TeamName TeamNameQuery `form:"team_name" json:"team_name"`
+}
+
+// PostTeamSetMaxOpenReviewsJSONBody defines parameters for PostTeamSetMaxOpenReviews.
+type PostTeamSetMaxOpenReviewsJSONBody struct {
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	TeamName       string `json:"team_name"`
+}
+
+// PostTeamSetReviewSlaJSONBody defines parameters for PostTeamSetReviewSla.
+type PostTeamSetReviewSlaJSONBody struct {
+	// ReviewSla Длительность в формате Go (например, 24h или 90m)
+	ReviewSla *string `json:"review_sla"`
+	TeamName  string  `json:"team_name"`
 }
 
 // GetUsersGetReviewParams defines parameters for GetUsersGetReview.
 type GetUsersGetReviewParams struct {
 	// UserId Идентификатор пользователя
 	UserId UserIdQuery `form:"user_id" json:"user_id"`
+
+	// Status Фильтр по статусу PR
+	Status *GetUsersGetReviewParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// AuthorId Фильтр по автору PR
+	AuthorId *AuthorIdQuery `form:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// CreatedFrom PR созданы не раньше этого момента
+	CreatedFrom *CreatedFromQuery `form:"created_from,omitempty" json:"created_from,omitempty"`
+
+	// CreatedTo PR созданы раньше этого момента
+	CreatedTo *CreatedToQuery `form:"created_to,omitempty" json:"created_to,omitempty"`
+
+	// SortBy Поле сортировки PR
+	SortBy *GetUsersGetReviewParamsSortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// Order Направление сортировки
+	Order *GetUsersGetReviewParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Limit Максимальное количество элементов на странице. Без параметра /pullRequest/list отдает страницу из 50 PR, а /team/get и /users/getReview, как и до пагинации, отдают весь список после курсора
+	Limit *LimitQuery `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Курсор следующей страницы из поля next_cursor предыдущего ответа
+	Cursor *CursorQuery `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetUsersGetReviewParamsStatus defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsStatus string
+
+// GetUsersGetReviewParamsSortBy defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsSortBy string
+
+// GetUsersGetReviewParamsOrder defines parameters for GetUsersGetReview.
+type GetUsersGetReviewParamsOrder string
+
+// PostUsersOooAddJSONBody defines parameters for PostUsersOooAdd.
+type PostUsersOooAddJSONBody struct {
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   *string   `json:"reason,omitempty"`
+	StartsAt time.Time `json:"starts_at"`
+	UserId   string    `json:"user_id"`
+}
+
+// PostUsersOooDeleteJSONBody defines parameters for PostUsersOooDelete.
+type PostUsersOooDeleteJSONBody struct {
+	OooId int64 `json:"ooo_id"`
+}
+
+// PostUsersScheduleDeleteJSONBody defines parameters for PostUsersScheduleDelete.
+type PostUsersScheduleDeleteJSONBody struct {
+	UserId string `json:"user_id"`
+}
+
+// GetUsersScheduleGetParams defines parameters for GetUsersScheduleGet.
+type GetUsersScheduleGetParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
 }
 
 // PostUsersSetIsActiveJSONBody defines parameters for PostUsersSetIsActive.
@@ -129,17 +753,142 @@ type PostUsersSetIsActiveJSONBody struct {
 	UserId   string `json:"user_id"`
 }
 
+// PostUsersSetMaxOpenReviewsJSONBody defines parameters for PostUsersSetMaxOpenReviews.
+type PostUsersSetMaxOpenReviewsJSONBody struct {
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+	UserId         string `json:"user_id"`
+}
+
+// PostUsersStatusCancelJSONBody defines parameters for PostUsersStatusCancel.
+type PostUsersStatusCancelJSONBody struct {
+	ChangeId int64 `json:"change_id"`
+}
+
+// PostUsersStatusScheduleJSONBody defines parameters for PostUsersStatusSchedule.
+type PostUsersStatusScheduleJSONBody struct {
+	ApplyAt  time.Time `json:"apply_at"`
+	IsActive bool      `json:"is_active"`
+	UserId   string    `json:"user_id"`
+}
+
+// GetUsersStatusScheduledParams defines parameters for GetUsersStatusScheduled.
+type GetUsersStatusScheduledParams struct {
+	// UserId Идентификатор пользователя
+	UserId UserIdQuery `form:"user_id" json:"user_id"`
+}
+
+// PostOrgSyncJSONRequestBody defines body for PostOrgSync for application/json ContentType.
+type PostOrgSyncJSONRequestBody = OrgManifest
+
 // PostPullRequestCreateJSONRequestBody defines body for PostPullRequestCreate for application/json ContentType.
 type PostPullRequestCreateJSONRequestBody PostPullRequestCreateJSONBody
 
+// PostPullRequestDeclineJSONRequestBody defines body for PostPullRequestDecline for application/json ContentType.
+type PostPullRequestDeclineJSONRequestBody PostPullRequestDeclineJSONBody
+
 // PostPullRequestMergeJSONRequestBody defines body for PostPullRequestMerge for application/json ContentType.
 type PostPullRequestMergeJSONRequestBody PostPullRequestMergeJSONBody
 
 // PostPullRequestReassignJSONRequestBody defines body for PostPullRequestReassign for application/json ContentType.
 type PostPullRequestReassignJSONRequestBody PostPullRequestReassignJSONBody
 
+// PostPullRequestReviewersAddJSONRequestBody defines body for PostPullRequestReviewersAdd for application/json ContentType.
+type PostPullRequestReviewersAddJSONRequestBody PostPullRequestReviewersAddJSONBody
+
+// PostPullRequestReviewersRemoveJSONRequestBody defines body for PostPullRequestReviewersRemove for application/json ContentType.
+type PostPullRequestReviewersRemoveJSONRequestBody PostPullRequestReviewersRemoveJSONBody
+
 // PostTeamAddJSONRequestBody defines body for PostTeamAdd for application/json ContentType.
 type PostTeamAddJSONRequestBody = Team
 
+// PostTeamSetMaxOpenReviewsJSONRequestBody defines body for PostTeamSetMaxOpenReviews for application/json ContentType.
+type PostTeamSetMaxOpenReviewsJSONRequestBody PostTeamSetMaxOpenReviewsJSONBody
+
+// PostTeamSetReviewSlaJSONRequestBody defines body for PostTeamSetReviewSla for application/json ContentType.
+type PostTeamSetReviewSlaJSONRequestBody PostTeamSetReviewSlaJSONBody
+
+// PostUsersOooAddJSONRequestBody defines body for PostUsersOooAdd for application/json ContentType.
+type PostUsersOooAddJSONRequestBody PostUsersOooAddJSONBody
+
+// PostUsersOooDeleteJSONRequestBody defines body for PostUsersOooDelete for application/json ContentType.
+type PostUsersOooDeleteJSONRequestBody PostUsersOooDeleteJSONBody
+
+// PostUsersScheduleDeleteJSONRequestBody defines body for PostUsersScheduleDelete for application/json ContentType.
+type PostUsersScheduleDeleteJSONRequestBody PostUsersScheduleDeleteJSONBody
+
+// PostUsersScheduleSetJSONRequestBody defines body for PostUsersScheduleSet for application/json ContentType.
+type PostUsersScheduleSetJSONRequestBody = WorkSchedule
+
 // PostUsersSetIsActiveJSONRequestBody defines body for PostUsersSetIsActive for application/json ContentType.
 type PostUsersSetIsActiveJSONRequestBody PostUsersSetIsActiveJSONBody
+
+// PostUsersSetMaxOpenReviewsJSONRequestBody defines body for PostUsersSetMaxOpenReviews for application/json ContentType.
+type PostUsersSetMaxOpenReviewsJSONRequestBody PostUsersSetMaxOpenReviewsJSONBody
+
+// PostUsersStatusCancelJSONRequestBody defines body for PostUsersStatusCancel for application/json ContentType.
+type PostUsersStatusCancelJSONRequestBody PostUsersStatusCancelJSONBody
+
+// PostUsersStatusScheduleJSONRequestBody defines body for PostUsersStatusSchedule for application/json ContentType.
+type PostUsersStatusScheduleJSONRequestBody PostUsersStatusScheduleJSONBody
+
+// AsReassignResult returns the union data inside the ReassignResponse as a ReassignResult
+func (t ReassignResponse) AsReassignResult() (ReassignResult, error) {
+	var body ReassignResult
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromReassignResult overwrites any union data inside the ReassignResponse as the provided ReassignResult
+func (t *ReassignResponse) FromReassignResult(v ReassignResult) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeReassignResult performs a merge with any union data inside the ReassignResponse, using the provided ReassignResult
+func (t *ReassignResponse) MergeReassignResult(v ReassignResult) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsAssignmentSimulation returns the union data inside the ReassignResponse as a AssignmentSimulation
+func (t ReassignResponse) AsAssignmentSimulation() (AssignmentSimulation, error) {
+	var body AssignmentSimulation
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromAssignmentSimulation overwrites any union data inside the ReassignResponse as the provided AssignmentSimulation
+func (t *ReassignResponse) FromAssignmentSimulation(v AssignmentSimulation) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeAssignmentSimulation performs a merge with any union data inside the ReassignResponse, using the provided AssignmentSimulation
+func (t *ReassignResponse) MergeAssignmentSimulation(v AssignmentSimulation) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t ReassignResponse) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *ReassignResponse) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
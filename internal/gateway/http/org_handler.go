@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+// OrgManifestToEntity converts the manifest, members are active unless
+// is_active is false.
+func OrgManifestToEntity(m OrgManifest) []entity.OrgTeam {
+	teams := make([]entity.OrgTeam, 0, len(m.Teams))
+	for _, t := range m.Teams {
+		team := entity.OrgTeam{Name: t.TeamName, Members: make([]entity.User, 0, len(t.Members))}
+		for _, u := range t.Members {
+			isActive := u.IsActive == nil || *u.IsActive
+			team.Members = append(team.Members, *entity.NewUser(u.UserId, u.Username, t.TeamName, isActive))
+		}
+		teams = append(teams, team)
+	}
+	return teams
+}
+
+// OrgManifestFromEntity converts the org back to a manifest, is_active is set
+// only for inactive members.
+func OrgManifestFromEntity(teams []entity.OrgTeam) OrgManifest {
+	m := OrgManifest{Teams: make([]OrgManifestTeam, 0, len(teams))}
+	for _, t := range teams {
+		team := OrgManifestTeam{TeamName: t.Name, Members: make([]OrgManifestMember, 0, len(t.Members))}
+		for _, u := range t.Members {
+			member := OrgManifestMember{UserId: u.ID, Username: u.Name}
+			if !u.IsActive {
+				member.IsActive = &u.IsActive
+			}
+			team.Members = append(team.Members, member)
+		}
+		m.Teams = append(m.Teams, team)
+	}
+	return m
+}
+
+func OrgSyncResultFromEntity(e entity.OrgSyncResult) OrgSyncResult {
+	plan := OrgSyncPlan{
+		AddTeams:    nonNil(e.Plan.AddTeams),
+		RemoveTeams: nonNil(e.Plan.RemoveTeams),
+		Users:       make([]OrgUserChange, 0, len(e.Plan.Users)),
+		Reassign:    make([]OrgReassignment, 0, len(e.Plan.Reassign)),
+	}
+	for _, c := range e.Plan.Users {
+		change := OrgUserChange{User: UserFromEntity(c.After)}
+		if c.Before != nil {
+			before := UserFromEntity(*c.Before)
+			change.Before = &before
+		}
+		plan.Users = append(plan.Users, change)
+	}
+	for _, r := range e.Plan.Reassign {
+		plan.Reassign = append(plan.Reassign, OrgReassignment{
+			PullRequestId: r.PRID,
+			ReviewerId:    r.ReviewerID,
+			TeamName:      r.TeamName,
+			ReplacedBy:    r.ReplacedBy,
+		})
+	}
+
+	return OrgSyncResult{DryRun: e.DryRun, Plan: plan}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func (s *Server) PostOrgSync(w nethttp.ResponseWriter, r *nethttp.Request, params PostOrgSyncParams) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to sync org")
+	var body OrgManifest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+	result, err := s.OrgUseCase.Sync(r.Context(), OrgManifestToEntity(body), dryRun)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, OrgSyncResultFromEntity(*result))
+	logging.FromContext(r.Context(), s.log).Debug("Request to sync org processed successfully")
+}
+
+func (s *Server) GetOrgExport(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to export org")
+	teams, err := s.OrgUseCase.Export(r.Context())
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, OrgManifestFromEntity(teams))
+	logging.FromContext(r.Context(), s.log).Debug("Request to export org processed successfully")
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	nethttp "net/http"
 	"strings"
+	"time"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -13,9 +15,16 @@ type PullRequestResponse struct {
 	PR PullRequest `json:"pr"`
 }
 
-type PullRequestReassignResponse struct {
-	PR         PullRequest `json:"pr"`
-	ReplacedBy string      `json:"replaced_by"`
+type PullRequestListResponse struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+	NextCursor   *string       `json:"next_cursor,omitempty"`
+}
+
+type PullRequestGetResponse struct {
+	PR                  PullRequest        `json:"pr"`
+	Reviewers           []AssignedReviewer `json:"reviewers"`
+	AgeSeconds          int64              `json:"age_seconds"`
+	TimeInReviewSeconds *int64             `json:"time_in_review_seconds,omitempty"`
 }
 
 func (s *Server) prToRepsponse(pr *entity.PR, assigned []string) PullRequestResponse {
@@ -45,17 +54,42 @@ func (s *Server) prToRepsponse(pr *entity.PR, assigned []string) PullRequestResp
 	}
 }
 
+func simulationToResponse(sim *entity.AssignmentSimulation) AssignmentSimulation {
+	candidates := make([]AssignmentCandidate, 0, len(sim.Candidates))
+	for _, c := range sim.Candidates {
+		candidate := AssignmentCandidate{
+			UserId:   c.ID,
+			Username: c.Name,
+			IsActive: c.IsActive,
+			Eligible: c.Eligible(),
+		}
+		if !c.Eligible() {
+			reason := AssignmentCandidateExclusionReason(c.ExclusionReason)
+			candidate.ExclusionReason = &reason
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return AssignmentSimulation{
+		DryRun:     true,
+		TeamName:   sim.TeamName,
+		Reviewers:  sim.Reviewers,
+		Candidates: candidates,
+	}
+}
+
 func (s *Server) PostPullRequestCreate(w nethttp.ResponseWriter, r *nethttp.Request) {
-	s.log.Info("Received request to create pull request")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to create pull request")
 	var body PostPullRequestCreateJSONBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
 		return
 	}
 
 	if body.AuthorId == "" || body.PullRequestId == "" || body.PullRequestName == "" {
 		s.writeError(
 			w,
+			r,
 			nethttp.StatusBadRequest,
 			NOTFOUND,
 			"author_id, pull_request_id and pull_request_name are required",
@@ -65,84 +99,210 @@ func (s *Server) PostPullRequestCreate(w nethttp.ResponseWriter, r *nethttp.Requ
 
 	pr := *entity.NewPR(body.PullRequestId, body.PullRequestName, body.AuthorId)
 
+	if body.DryRun != nil && *body.DryRun {
+		sim, err := s.PRUseCase.SimulateCreatePullRequest(r.Context(), pr)
+		if err != nil {
+			status, code := mapDomainError(err)
+			s.writeError(w, r, status, code, err.Error())
+			return
+		}
+		s.writeJSON(w, r, nethttp.StatusOK, simulationToResponse(sim))
+		logging.FromContext(r.Context(), s.log).Debug("Request to simulate pull request creation processed successfully")
+		return
+	}
+
 	assigned, err := s.PRUseCase.CreatePullRequest(r.Context(), pr)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
 	resp := s.prToRepsponse(&pr, assigned)
 
-	s.writeJSON(w, nethttp.StatusCreated, resp)
-	s.log.Info("Request to create pull request processed successfully")
+	s.writeJSON(w, r, nethttp.StatusCreated, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to create pull request processed successfully")
 }
 
 func (s *Server) PostPullRequestMerge(w nethttp.ResponseWriter, r *nethttp.Request) {
-	s.log.Info("Received request to merge pull request")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to merge pull request")
 	var body PostPullRequestMergeJSONBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
 		return
 	}
 
 	if body.PullRequestId == "" {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id is required")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id is required")
 		return
 	}
 
 	pr, err := s.PRUseCase.MergePullRequest(r.Context(), body.PullRequestId)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
 	assigned, err := s.PRUseCase.GetAssignedReviewers(r.Context(), body.PullRequestId)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
 	resp := s.prToRepsponse(pr, assigned)
-	s.writeJSON(w, nethttp.StatusOK, resp)
-	s.log.Info("Request to merge pull request processed successfully")
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to merge pull request processed successfully")
 }
 
 func (s *Server) PostPullRequestReassign(w nethttp.ResponseWriter, r *nethttp.Request) {
-	s.log.Info("Received request to reassign pull request reviewer")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to reassign pull request reviewer")
 	var body PostPullRequestReassignJSONBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
 		return
 	}
 
 	if body.PullRequestId == "" || body.OldUserId == "" {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id and old_user_id are required")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id and old_user_id are required")
+		return
+	}
+
+	opts := entity.ReassignOptions{
+		NewUserID:     derefString(body.NewUserId),
+		CandidateTeam: derefString(body.CandidateTeam),
+		AllowRemoved:  body.AllowRemoved != nil && *body.AllowRemoved,
+	}
+
+	if body.DryRun != nil && *body.DryRun {
+		sim, err := s.PRUseCase.SimulateReassignReviewer(r.Context(), body.PullRequestId, body.OldUserId, opts)
+		if err != nil {
+			status, code := mapDomainError(err)
+			s.writeError(w, r, status, code, err.Error())
+			return
+		}
+		s.writeJSON(w, r, nethttp.StatusOK, simulationToResponse(sim))
+		logging.FromContext(r.Context(), s.log).Debug("Request to simulate reviewer reassignment processed successfully")
 		return
 	}
 
-	newUserID, pr, err := s.PRUseCase.ReassignReviewer(r.Context(), body.PullRequestId, body.OldUserId)
+	newUserID, pr, err := s.PRUseCase.ReassignReviewer(r.Context(), body.PullRequestId, body.OldUserId, opts)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
 	assigned, err := s.PRUseCase.GetAssignedReviewers(r.Context(), body.PullRequestId)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
 	prResp := s.prToRepsponse(pr, assigned)
-	resp := PullRequestReassignResponse{
-		PR:         prResp.PR,
+	resp := ReassignResult{
+		Pr:         prResp.PR,
 		ReplacedBy: newUserID,
 	}
 
-	s.writeJSON(w, nethttp.StatusOK, resp)
-	s.log.Info("Request to reassign pull request reviewer processed successfully")
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to reassign pull request reviewer processed successfully")
+}
+
+func (s *Server) GetPullRequestGet(w nethttp.ResponseWriter, r *nethttp.Request, params GetPullRequestGetParams) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get pull request")
+	if params.PullRequestId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id is required")
+		return
+	}
+
+	details, err := s.PRUseCase.GetPullRequest(r.Context(), params.PullRequestId)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	assigned := make([]string, 0, len(details.Reviewers))
+	reviewers := make([]AssignedReviewer, 0, len(details.Reviewers))
+	for _, rv := range details.Reviewers {
+		assigned = append(assigned, rv.ID)
+		reviewers = append(reviewers, AssignedReviewer{
+			UserId:              rv.ID,
+			Username:            rv.Name,
+			TeamName:            rv.TeamName,
+			IsActive:            rv.IsActive,
+			AssignedAt:          rv.AssignedAt,
+			TimeInReviewSeconds: int64(details.ReviewerTimeInReview(rv, now).Seconds()),
+		})
+	}
+
+	resp := PullRequestGetResponse{
+		PR:         s.prToRepsponse(&details.PR, assigned).PR,
+		Reviewers:  reviewers,
+		AgeSeconds: int64(details.Age(now).Seconds()),
+	}
+	if inReview := details.TimeInReview(now); inReview != nil {
+		seconds := int64(inReview.Seconds())
+		resp.TimeInReviewSeconds = &seconds
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to get pull request processed successfully")
+}
+
+func (s *Server) GetPullRequestList(w nethttp.ResponseWriter, r *nethttp.Request, params GetPullRequestListParams) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to list pull requests")
+	opts := entity.PRListOptions{
+		Filter: entity.PRFilter{
+			Status:        derefString(params.Status),
+			AuthorID:      derefString(params.AuthorId),
+			CreatedFrom:   params.CreatedFrom,
+			CreatedTo:     params.CreatedTo,
+			TeamName:      derefString(params.TeamName),
+			ReviewerID:    derefString(params.ReviewerId),
+			TitleContains: derefString(params.Title),
+			MergedFrom:    params.MergedFrom,
+			MergedTo:      params.MergedTo,
+		},
+		Sort: entity.Sort{
+			Field: derefString(params.SortBy),
+			Order: derefString(params.Order),
+		},
+		Page: pageFromParams(params.Limit, params.Cursor),
+	}
+	if params.FewerReviewersThan != nil {
+		if *params.FewerReviewersThan < 1 {
+			s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "fewer_reviewers_than must be positive")
+			return
+		}
+		opts.Filter.FewerReviewersThan = params.FewerReviewersThan
+	}
+	if !validPRStatus(opts.Filter.Status) || !validSortOrder(opts.Sort.Order) {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "status must be OPEN or MERGED, order must be asc or desc")
+		return
+	}
+
+	page, err := s.PRUseCase.ListPullRequests(r.Context(), opts)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	out := make([]PullRequest, 0, len(page.Items))
+	for _, item := range page.Items {
+		out = append(out, s.prToRepsponse(&item.PR, item.Reviewers).PR)
+	}
+
+	resp := PullRequestListResponse{
+		PullRequests: out,
+		NextCursor:   nextCursorPtr(page.NextCursor),
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to list pull requests processed successfully")
 }
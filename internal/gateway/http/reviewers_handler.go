@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+type PullRequestReviewersAuditResponse struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Entries       []ReviewerAuditEntry `json:"entries"`
+}
+
+func ReviewerAuditEntryFromEntity(e entity.ReviewerAuditEntry) ReviewerAuditEntry {
+	return ReviewerAuditEntry{
+		Id:            e.ID,
+		PullRequestId: e.PRID,
+		ReviewerId:    e.ReviewerID,
+		Action:        ReviewerAuditEntryAction(e.Action),
+		Actor:         e.Actor,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+func (s *Server) PostPullRequestReviewersAdd(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to add pull request reviewer")
+	var body PostPullRequestReviewersAddJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.PullRequestId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id is required")
+		return
+	}
+
+	pr, entry, err := s.PRUseCase.AddReviewer(r.Context(), body.PullRequestId, derefString(body.UserId),
+		derefString(body.Actor))
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeReviewerChange(w, r, pr, entry)
+	logging.FromContext(r.Context(), s.log).Debug("Request to add pull request reviewer processed successfully")
+}
+
+func (s *Server) PostPullRequestReviewersRemove(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to remove pull request reviewer")
+	var body PostPullRequestReviewersRemoveJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.PullRequestId == "" || body.UserId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id and user_id are required")
+		return
+	}
+
+	pr, entry, err := s.PRUseCase.RemoveReviewer(r.Context(), body.PullRequestId, body.UserId,
+		derefString(body.Actor))
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeReviewerChange(w, r, pr, entry)
+	logging.FromContext(r.Context(), s.log).Debug("Request to remove pull request reviewer processed successfully")
+}
+
+func (s *Server) GetPullRequestReviewersAudit(
+	w nethttp.ResponseWriter,
+	r *nethttp.Request,
+	params GetPullRequestReviewersAuditParams,
+) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get pull request reviewer audit")
+	if params.PullRequestId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "pull_request_id is required")
+		return
+	}
+
+	entries, err := s.PRUseCase.ListReviewerAudit(r.Context(), params.PullRequestId)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := PullRequestReviewersAuditResponse{
+		PullRequestID: params.PullRequestId,
+		Entries:       make([]ReviewerAuditEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, ReviewerAuditEntryFromEntity(e))
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to get pull request reviewer audit processed successfully")
+}
+
+// writeReviewerChange responds with the PR after a manual reviewer change.
+func (s *Server) writeReviewerChange(
+	w nethttp.ResponseWriter,
+	r *nethttp.Request,
+	pr *entity.PR,
+	entry *entity.ReviewerAuditEntry,
+) {
+	assigned, err := s.PRUseCase.GetAssignedReviewers(r.Context(), pr.ID)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := ReviewerChangeResponse{
+		Pr:    s.prToRepsponse(pr, assigned).PR,
+		Audit: ReviewerAuditEntryFromEntity(*entry),
+	}
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+type UsersScheduleGetResponse struct {
+	UserID      string        `json:"user_id"`
+	Schedule    *WorkSchedule `json:"schedule,omitempty"`
+	OutOfOffice []OutOfOffice `json:"out_of_office"`
+}
+
+type UsersScheduleSetResponse struct {
+	Schedule WorkSchedule `json:"schedule"`
+}
+
+type UsersScheduleDeleteResponse struct {
+	UserID string `json:"user_id"`
+}
+
+type UsersOooAddResponse struct {
+	OutOfOffice OutOfOffice `json:"out_of_office"`
+}
+
+type UsersOooDeleteResponse struct {
+	OooID int64 `json:"ooo_id"`
+}
+
+func ScheduleFromEntity(e entity.WorkSchedule) WorkSchedule {
+	return WorkSchedule{
+		UserId:    e.UserID,
+		Timezone:  e.Timezone,
+		WorkStart: e.WorkStart,
+		WorkEnd:   e.WorkEnd,
+	}
+}
+
+func OutOfOfficeFromEntity(e entity.OutOfOffice) OutOfOffice {
+	return OutOfOffice{
+		OooId:    e.ID,
+		UserId:   e.UserID,
+		StartsAt: e.StartsAt,
+		EndsAt:   e.EndsAt,
+		Reason:   e.Reason,
+	}
+}
+
+func (s *Server) GetUsersScheduleGet(w nethttp.ResponseWriter, r *nethttp.Request, params GetUsersScheduleGetParams) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get user's schedule")
+	if params.UserId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id required")
+		return
+	}
+
+	schedule, periods, err := s.ScheduleUseCase.GetSchedule(r.Context(), params.UserId)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := UsersScheduleGetResponse{
+		UserID:      params.UserId,
+		OutOfOffice: make([]OutOfOffice, 0, len(periods)),
+	}
+	if schedule != nil {
+		sch := ScheduleFromEntity(*schedule)
+		resp.Schedule = &sch
+	}
+	for _, p := range periods {
+		resp.OutOfOffice = append(resp.OutOfOffice, OutOfOfficeFromEntity(p))
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to get user's schedule processed successfully")
+}
+
+func (s *Server) PostUsersScheduleSet(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to set user's schedule")
+	var body WorkSchedule
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.UserId == "" || body.Timezone == "" || body.WorkStart == "" || body.WorkEnd == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id, timezone, work_start and work_end are required")
+		return
+	}
+
+	schedule := *entity.NewWorkSchedule(body.UserId, body.Timezone, body.WorkStart, body.WorkEnd)
+
+	saved, err := s.ScheduleUseCase.SetSchedule(r.Context(), schedule)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := UsersScheduleSetResponse{Schedule: ScheduleFromEntity(*saved)}
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to set user's schedule processed successfully")
+}
+
+func (s *Server) PostUsersScheduleDelete(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to delete user's schedule")
+	var body PostUsersScheduleDeleteJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.UserId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id is required")
+		return
+	}
+
+	if err := s.ScheduleUseCase.DeleteSchedule(r.Context(), body.UserId); err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, UsersScheduleDeleteResponse{UserID: body.UserId})
+	logging.FromContext(r.Context(), s.log).Debug("Request to delete user's schedule processed successfully")
+}
+
+func (s *Server) PostUsersOooAdd(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to add out of office")
+	var body PostUsersOooAddJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.UserId == "" || body.StartsAt.IsZero() || body.EndsAt.IsZero() {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id, starts_at and ends_at are required")
+		return
+	}
+
+	var reason string
+	if body.Reason != nil {
+		reason = *body.Reason
+	}
+	period := *entity.NewOutOfOffice(body.UserId, body.StartsAt, body.EndsAt, reason)
+
+	created, err := s.ScheduleUseCase.AddOutOfOffice(r.Context(), period)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := UsersOooAddResponse{OutOfOffice: OutOfOfficeFromEntity(*created)}
+	s.writeJSON(w, r, nethttp.StatusCreated, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to add out of office processed successfully")
+}
+
+func (s *Server) PostUsersOooDelete(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to delete out of office")
+	var body PostUsersOooDeleteJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.OooId == 0 {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "ooo_id is required")
+		return
+	}
+
+	if err := s.ScheduleUseCase.DeleteOutOfOffice(r.Context(), body.OooId); err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, UsersOooDeleteResponse{OooID: body.OooId})
+	logging.FromContext(r.Context(), s.log).Debug("Request to delete out of office processed successfully")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	nethttp "net/http"
 
@@ -11,55 +12,128 @@ import (
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
 )
 
 type PRUseCase interface {
 	CreatePullRequest(ctx context.Context, pr entity.PR) (assignedIDs []string, err error)
+	SimulateCreatePullRequest(ctx context.Context, pr entity.PR) (*entity.AssignmentSimulation, error)
 	MergePullRequest(ctx context.Context, prID string) (*entity.PR, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (string, *entity.PR, error)
+	ReassignReviewer(
+		ctx context.Context,
+		prID, oldUserID string,
+		opts entity.ReassignOptions,
+	) (string, *entity.PR, error)
+	SimulateReassignReviewer(
+		ctx context.Context,
+		prID, oldUserID string,
+		opts entity.ReassignOptions,
+	) (*entity.AssignmentSimulation, error)
 	GetAssignedReviewers(ctx context.Context, prID string) (assignedIDs []string, err error)
+	GetPullRequest(ctx context.Context, prID string) (*entity.PRDetails, error)
+	ListPullRequests(ctx context.Context, opts entity.PRListOptions) (entity.Page[entity.PRWithReviewers], error)
+	GetFairnessLedger(ctx context.Context, teamName string) (*entity.FairnessLedger, error)
+	AddReviewer(ctx context.Context, prID, userID, actor string) (*entity.PR, *entity.ReviewerAuditEntry, error)
+	RemoveReviewer(ctx context.Context, prID, userID, actor string) (*entity.PR, *entity.ReviewerAuditEntry, error)
+	ListReviewerAudit(ctx context.Context, prID string) ([]entity.ReviewerAuditEntry, error)
+	DeclineReview(ctx context.Context, prID, reviewerID, reason, comment string) (*entity.PR, *entity.Decline, error)
 }
 
 type TeamUseCase interface {
 	AddTeam(ctx context.Context, team entity.Team, users []entity.User) error
-	GetTeam(ctx context.Context, name string) (*entity.Team, []entity.User, error)
+	GetTeam(
+		ctx context.Context,
+		name string,
+		sort entity.Sort,
+		page entity.PageRequest,
+	) (*entity.Team, entity.Page[entity.User], error)
+	SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) error
 }
 
 type UserUseCase interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error)
-	GetAssignedTo(ctx context.Context, userID string) ([]entity.PR, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*entity.User, error)
+	GetAssignedTo(ctx context.Context, userID string, opts entity.PRListOptions) (entity.Page[entity.PR], error)
+}
+
+type ScheduleUseCase interface {
+	SetSchedule(ctx context.Context, schedule entity.WorkSchedule) (*entity.WorkSchedule, error)
+	GetSchedule(ctx context.Context, userID string) (*entity.WorkSchedule, []entity.OutOfOffice, error)
+	DeleteSchedule(ctx context.Context, userID string) error
+	AddOutOfOffice(ctx context.Context, o entity.OutOfOffice) (*entity.OutOfOffice, error)
+	DeleteOutOfOffice(ctx context.Context, id int64) error
+}
+
+type StatusChangeUseCase interface {
+	ScheduleStatusChange(ctx context.Context, c entity.StatusChange) (*entity.StatusChange, error)
+	ListScheduledChanges(ctx context.Context, userID string) ([]entity.StatusChange, error)
+	CancelScheduledChange(ctx context.Context, id int64) error
+}
+
+type SLAUseCase interface {
+	SetTeamSLA(ctx context.Context, teamName string, sla *time.Duration) error
+	GetReviewStats(ctx context.Context, teamName string) (*entity.ReviewStats, error)
+	ListOverdue(ctx context.Context) ([]entity.Escalation, error)
+}
+
+type OrgUseCase interface {
+	Sync(ctx context.Context, manifest []entity.OrgTeam, dryRun bool) (*entity.OrgSyncResult, error)
+	Export(ctx context.Context) ([]entity.OrgTeam, error)
 }
 
 type Server struct {
-	PRUseCase   PRUseCase
-	TeamUseCase TeamUseCase
-	UserUseCase UserUseCase
-	log         *log.Logger
+	PRUseCase       PRUseCase
+	TeamUseCase     TeamUseCase
+	UserUseCase     UserUseCase
+	ScheduleUseCase ScheduleUseCase
+	StatusUseCase   StatusChangeUseCase
+	SLAUseCase      SLAUseCase
+	OrgUseCase      OrgUseCase
+	log             *log.Logger
 }
 
-func NewServer(pr PRUseCase, team TeamUseCase, user UserUseCase, logger *log.Logger) *Server {
+func NewServer(
+	pr PRUseCase,
+	team TeamUseCase,
+	user UserUseCase,
+	schedule ScheduleUseCase,
+	status StatusChangeUseCase,
+	sla SLAUseCase,
+	org OrgUseCase,
+	logger *log.Logger,
+) *Server {
 	return &Server{
-		PRUseCase:   pr,
-		TeamUseCase: team,
-		UserUseCase: user,
-		log:         logger,
+		PRUseCase:       pr,
+		TeamUseCase:     team,
+		UserUseCase:     user,
+		ScheduleUseCase: schedule,
+		StatusUseCase:   status,
+		SLAUseCase:      sla,
+		OrgUseCase:      org,
+		log:             logger,
 	}
 }
 
-func (s *Server) writeJSON(w nethttp.ResponseWriter, status int, v any) {
+func (s *Server) writeJSON(w nethttp.ResponseWriter, r *nethttp.Request, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(v); err != nil {
-		s.log.WithError(err).Error("Failed to write JSON response")
+		logging.FromContext(r.Context(), s.log).WithError(err).Error("Failed to write JSON response")
 	}
 }
 
-func (s *Server) writeError(w nethttp.ResponseWriter, status int, code ErrorResponseErrorCode, message string) {
+func (s *Server) writeError(
+	w nethttp.ResponseWriter,
+	r *nethttp.Request,
+	status int,
+	code ErrorResponseErrorCode,
+	message string,
+) {
 	resp := ErrorResponse{}
 	resp.Error.Code = code
 	resp.Error.Message = message
 
-	logEntry := s.log.WithFields(log.Fields{
+	logEntry := logging.FromContext(r.Context(), s.log).WithFields(log.Fields{
 		"status":  status,
 		"code":    code,
 		"message": message,
@@ -70,7 +144,7 @@ func (s *Server) writeError(w nethttp.ResponseWriter, status int, code ErrorResp
 		logEntry.Info("Writing error response")
 	}
 
-	s.writeJSON(w, status, resp)
+	s.writeJSON(w, r, status, resp)
 }
 
 func mapDomainError(err error) (int, ErrorResponseErrorCode) {
@@ -80,6 +154,18 @@ func mapDomainError(err error) (int, ErrorResponseErrorCode) {
 	if errors.Is(err, apperror.ErrPRExists) {
 		return nethttp.StatusConflict, PREXISTS
 	}
+	if errors.Is(err, apperror.ErrReviewerExists) {
+		return nethttp.StatusConflict, REVIEWEREXISTS
+	}
+	if errors.Is(err, apperror.ErrAllAtCapacity) {
+		return nethttp.StatusConflict, ALLATCAPACITY
+	}
+	if errors.Is(err, apperror.ErrReviewersFull) {
+		return nethttp.StatusConflict, REVIEWERSFULL
+	}
+	if errors.Is(err, apperror.ErrNotEligible) {
+		return nethttp.StatusConflict, NOTELIGIBLE
+	}
 	if errors.Is(err, apperror.ErrNotAssigned) {
 		return nethttp.StatusBadRequest, NOTASSIGNED
 	}
@@ -92,5 +178,10 @@ func mapDomainError(err error) (int, ErrorResponseErrorCode) {
 	if errors.Is(err, apperror.ErrTeamExists) {
 		return nethttp.StatusBadRequest, TEAMEXISTS
 	}
+	if errors.Is(err, apperror.ErrInvalidSchedule) || errors.Is(err, apperror.ErrInvalidSLA) ||
+		errors.Is(err, apperror.ErrInvalidListing) || errors.Is(err, apperror.ErrInvalidCapacity) ||
+		errors.Is(err, apperror.ErrInvalidDecline) || errors.Is(err, apperror.ErrInvalidManifest) {
+		return nethttp.StatusBadRequest, NOTFOUND
+	}
 	return nethttp.StatusInternalServerError, NOTFOUND
 }
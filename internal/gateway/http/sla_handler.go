@@ -0,0 +1,124 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+type TeamSetReviewSLAResponse struct {
+	TeamName  string  `json:"team_name"`
+	ReviewSLA *string `json:"review_sla"`
+}
+
+type PullRequestOverdueResponse struct {
+	Escalations []ReviewEscalation `json:"escalations"`
+}
+
+func EscalationFromEntity(e entity.Escalation) ReviewEscalation {
+	return ReviewEscalation{
+		PullRequestId: e.PRID,
+		ReviewerId:    e.ReviewerID,
+		TeamName:      e.TeamName,
+		AssignedAt:    e.AssignedAt,
+		DueAt:         e.DueAt,
+		DetectedAt:    e.DetectedAt,
+		Action:        ReviewEscalationAction(e.Action),
+		NewReviewerId: e.NewReviewerID,
+	}
+}
+
+func ReviewStatsFromEntity(e entity.ReviewStats) ReviewStats {
+	stats := ReviewStats{
+		TeamName:              e.TeamName,
+		PrCount:               e.PRCount,
+		MergedCount:           e.MergedCount,
+		AvgTimeToMergeSeconds: int64(e.AvgTimeToMerge.Seconds()),
+		OpenAssignments:       e.OpenAssignments,
+		OverdueAssignments:    e.OverdueAssignments,
+		Declines:              e.Declines,
+	}
+	if e.SLA != nil {
+		sla := e.SLA.String()
+		stats.ReviewSla = &sla
+	}
+	return stats
+}
+
+func (s *Server) PostTeamSetReviewSla(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to set team review SLA")
+	var body PostTeamSetReviewSlaJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.TeamName == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
+		return
+	}
+
+	var sla *time.Duration
+	if body.ReviewSla != nil && *body.ReviewSla != "" {
+		d, err := time.ParseDuration(*body.ReviewSla)
+		if err != nil {
+			s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "review_sla must be a duration like 24h")
+			return
+		}
+		sla = &d
+	}
+
+	if err := s.SLAUseCase.SetTeamSLA(r.Context(), body.TeamName, sla); err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := TeamSetReviewSLAResponse{TeamName: body.TeamName}
+	if sla != nil {
+		str := sla.String()
+		resp.ReviewSLA = &str
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to set team review SLA processed successfully")
+}
+
+func (s *Server) GetTeamReviewStats(w nethttp.ResponseWriter, r *nethttp.Request, params GetTeamReviewStatsParams) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get team review stats")
+	if params.TeamName == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
+		return
+	}
+
+	stats, err := s.SLAUseCase.GetReviewStats(r.Context(), params.TeamName)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, ReviewStatsFromEntity(*stats))
+	logging.FromContext(r.Context(), s.log).Debug("Request to get team review stats processed successfully")
+}
+
+func (s *Server) GetPullRequestOverdue(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to list overdue reviews")
+	escalations, err := s.SLAUseCase.ListOverdue(r.Context())
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := PullRequestOverdueResponse{Escalations: make([]ReviewEscalation, 0, len(escalations))}
+	for _, e := range escalations {
+		resp.Escalations = append(resp.Escalations, EscalationFromEntity(e))
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to list overdue reviews processed successfully")
+}
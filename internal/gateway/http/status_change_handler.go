@@ -0,0 +1,111 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+type UsersStatusScheduleResponse struct {
+	ScheduledChange ScheduledStatusChange `json:"scheduled_change"`
+}
+
+type UsersStatusScheduledResponse struct {
+	UserID           string                  `json:"user_id"`
+	ScheduledChanges []ScheduledStatusChange `json:"scheduled_changes"`
+}
+
+type UsersStatusCancelResponse struct {
+	ChangeID int64 `json:"change_id"`
+}
+
+func StatusChangeFromEntity(e entity.StatusChange) ScheduledStatusChange {
+	return ScheduledStatusChange{
+		ChangeId: e.ID,
+		UserId:   e.UserID,
+		IsActive: e.IsActive,
+		ApplyAt:  e.ApplyAt,
+	}
+}
+
+func (s *Server) PostUsersStatusSchedule(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to schedule user status change")
+	var body PostUsersStatusScheduleJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.UserId == "" || body.ApplyAt.IsZero() {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id and apply_at are required")
+		return
+	}
+
+	change := *entity.NewStatusChange(body.UserId, body.IsActive, body.ApplyAt)
+
+	created, err := s.StatusUseCase.ScheduleStatusChange(r.Context(), change)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := UsersStatusScheduleResponse{ScheduledChange: StatusChangeFromEntity(*created)}
+	s.writeJSON(w, r, nethttp.StatusCreated, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to schedule user status change processed successfully")
+}
+
+func (s *Server) GetUsersStatusScheduled(
+	w nethttp.ResponseWriter,
+	r *nethttp.Request,
+	params GetUsersStatusScheduledParams,
+) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to list scheduled user status changes")
+	if params.UserId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id required")
+		return
+	}
+
+	changes, err := s.StatusUseCase.ListScheduledChanges(r.Context(), params.UserId)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := UsersStatusScheduledResponse{
+		UserID:           params.UserId,
+		ScheduledChanges: make([]ScheduledStatusChange, 0, len(changes)),
+	}
+	for _, c := range changes {
+		resp.ScheduledChanges = append(resp.ScheduledChanges, StatusChangeFromEntity(c))
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to list scheduled user status changes processed successfully")
+}
+
+func (s *Server) PostUsersStatusCancel(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to cancel scheduled user status change")
+	var body PostUsersStatusCancelJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.ChangeId == 0 {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "change_id is required")
+		return
+	}
+
+	if err := s.StatusUseCase.CancelScheduledChange(r.Context(), body.ChangeId); err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, UsersStatusCancelResponse{ChangeID: body.ChangeId})
+	logging.FromContext(r.Context(), s.log).Debug("Request to cancel scheduled user status change processed successfully")
+}
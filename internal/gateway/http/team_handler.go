@@ -5,37 +5,51 @@ import (
 	nethttp "net/http"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
 )
 
 type TeamAddResponse struct {
 	Team Team `json:"team"`
 }
 
+type TeamSetMaxOpenReviewsResponse struct {
+	TeamName       string `json:"team_name"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type TeamGetResponse struct {
+	Team
+
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
 func TeamFromEntity(e entity.Team, members []entity.User) Team {
 	team := Team{
-		TeamName: e.Name,
-		Members:  make([]TeamMember, 0, len(members)),
+		TeamName:       e.Name,
+		MaxOpenReviews: e.MaxOpenReviews,
+		Members:        make([]TeamMember, 0, len(members)),
 	}
 	for _, m := range members {
 		team.Members = append(team.Members, TeamMember{
-			UserId:   m.ID,
-			Username: m.Name,
-			IsActive: m.IsActive,
+			UserId:         m.ID,
+			Username:       m.Name,
+			IsActive:       m.IsActive,
+			MaxOpenReviews: m.MaxOpenReviews,
 		})
 	}
 	return team
 }
 
 func (s *Server) PostTeamAdd(w nethttp.ResponseWriter, r *nethttp.Request) {
-	s.log.Info("Received request to add team")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to add team")
 	var body Team
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
 		return
 	}
 
 	if body.TeamName == "" {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
 		return
 	}
 
@@ -44,7 +58,7 @@ func (s *Server) PostTeamAdd(w nethttp.ResponseWriter, r *nethttp.Request) {
 	users := make([]entity.User, 0, len(body.Members))
 	for _, m := range body.Members {
 		if m.UserId == "" {
-			s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "user_id is required for each member")
+			s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id is required for each member")
 			return
 		}
 		user := *entity.NewUser(m.UserId, m.Username, body.TeamName, m.IsActive)
@@ -53,31 +67,72 @@ func (s *Server) PostTeamAdd(w nethttp.ResponseWriter, r *nethttp.Request) {
 
 	if err := s.TeamUseCase.AddTeam(r.Context(), team, users); err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
 	resp := TeamAddResponse{Team: TeamFromEntity(team, users)}
-	s.writeJSON(w, nethttp.StatusCreated, resp)
-	s.log.Info("Request to add team processed successfully")
+	s.writeJSON(w, r, nethttp.StatusCreated, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to add team processed successfully")
 }
 
 func (s *Server) GetTeamGet(w nethttp.ResponseWriter, r *nethttp.Request, params GetTeamGetParams) {
-	s.log.Info("Received request to get team")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get team")
 	if params.TeamName == "" {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
+		return
+	}
+
+	sort := entity.Sort{
+		Field: derefString(params.SortBy),
+		Order: derefString(params.Order),
+	}
+	if !validSortOrder(sort.Order) {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "order must be asc or desc")
 		return
 	}
 
-	team, members, err := s.TeamUseCase.GetTeam(r.Context(), params.TeamName)
+	team, members, err := s.TeamUseCase.GetTeam(
+		r.Context(),
+		params.TeamName,
+		sort,
+		pageFromParams(params.Limit, params.Cursor),
+	)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := TeamGetResponse{
+		Team:       TeamFromEntity(*team, members.Items),
+		NextCursor: nextCursorPtr(members.NextCursor),
+	}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to get team processed successfully")
+}
+
+func (s *Server) PostTeamSetMaxOpenReviews(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to set team review capacity")
+	var body PostTeamSetMaxOpenReviewsJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.TeamName == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "team_name is required")
 		return
 	}
 
-	resp := TeamFromEntity(*team, members)
+	if err := s.TeamUseCase.SetMaxOpenReviews(r.Context(), body.TeamName, body.MaxOpenReviews); err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
 
-	s.writeJSON(w, nethttp.StatusOK, resp)
-	s.log.Info("Request to get team processed successfully")
+	resp := TeamSetMaxOpenReviewsResponse{TeamName: body.TeamName, MaxOpenReviews: body.MaxOpenReviews}
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to set team review capacity processed successfully")
 }
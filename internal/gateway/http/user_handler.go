@@ -6,34 +6,64 @@ import (
 	"strings"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
 	log "github.com/sirupsen/logrus"
 )
 
-type UsersSetIsActiveResponse struct {
+type UserResponse struct {
 	User User `json:"user"`
 }
 
 type UsersGetReviewResponse struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
+	NextCursor   *string            `json:"next_cursor,omitempty"`
+}
+
+func UserFromEntity(u entity.User) User {
+	return User{
+		UserId:         u.ID,
+		Username:       u.Name,
+		TeamName:       u.TeamName,
+		IsActive:       u.IsActive,
+		MaxOpenReviews: u.MaxOpenReviews,
+	}
 }
 
 func (s *Server) GetUsersGetReview(w nethttp.ResponseWriter, r *nethttp.Request, params GetUsersGetReviewParams) {
-	s.log.Info("Received request to get user's assigned PRs")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to get user's assigned PRs")
 	if params.UserId == "" {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "user_id required")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id required")
+		return
+	}
+
+	opts := entity.PRListOptions{
+		Filter: entity.PRFilter{
+			Status:      derefString(params.Status),
+			AuthorID:    derefString(params.AuthorId),
+			CreatedFrom: params.CreatedFrom,
+			CreatedTo:   params.CreatedTo,
+		},
+		Sort: entity.Sort{
+			Field: derefString(params.SortBy),
+			Order: derefString(params.Order),
+		},
+		Page: pageFromParams(params.Limit, params.Cursor),
+	}
+	if !validPRStatus(opts.Filter.Status) || !validSortOrder(opts.Sort.Order) {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "status must be OPEN or MERGED, order must be asc or desc")
 		return
 	}
 
-	prs, err := s.UserUseCase.GetAssignedTo(r.Context(), params.UserId)
+	page, err := s.UserUseCase.GetAssignedTo(r.Context(), params.UserId, opts)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
-	out := make([]PullRequestShort, 0, len(prs))
-	for _, p := range prs {
+	out := make([]PullRequestShort, 0, len(page.Items))
+	for _, p := range page.Items {
 		var status PullRequestShortStatus
 		switch strings.ToUpper(p.Status) {
 		case entity.PRStatusOpen:
@@ -41,7 +71,7 @@ func (s *Server) GetUsersGetReview(w nethttp.ResponseWriter, r *nethttp.Request,
 		case entity.PRStatusMerged:
 			status = PullRequestShortStatusMERGED
 		default:
-			s.log.WithFields(log.Fields{
+			logging.FromContext(r.Context(), s.log).WithFields(log.Fields{
 				"pr_id":  p.ID,
 				"status": p.Status,
 			}).Warn("Unknown pull request status")
@@ -58,39 +88,61 @@ func (s *Server) GetUsersGetReview(w nethttp.ResponseWriter, r *nethttp.Request,
 	resp := UsersGetReviewResponse{
 		UserID:       params.UserId,
 		PullRequests: out,
+		NextCursor:   nextCursorPtr(page.NextCursor),
 	}
 
-	s.writeJSON(w, nethttp.StatusOK, resp)
-	s.log.Info("Request to get user's assigned PRs processed successfully")
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to get user's assigned PRs processed successfully")
 }
 
 func (s *Server) PostUsersSetIsActive(w nethttp.ResponseWriter, r *nethttp.Request) {
-	s.log.Info("Received request to set user active status")
+	logging.FromContext(r.Context(), s.log).Debug("Received request to set user active status")
 	var body PostUsersSetIsActiveJSONBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
 		return
 	}
 
 	if body.UserId == "" {
-		s.writeError(w, nethttp.StatusBadRequest, NOTFOUND, "user_id is required")
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id is required")
 		return
 	}
 
 	u, err := s.UserUseCase.SetIsActive(r.Context(), body.UserId, body.IsActive)
 	if err != nil {
 		status, code := mapDomainError(err)
-		s.writeError(w, status, code, err.Error())
+		s.writeError(w, r, status, code, err.Error())
+		return
+	}
+
+	resp := UserResponse{User: UserFromEntity(*u)}
+
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to set user active status processed successfully")
+}
+
+func (s *Server) PostUsersSetMaxOpenReviews(w nethttp.ResponseWriter, r *nethttp.Request) {
+	logging.FromContext(r.Context(), s.log).Debug("Received request to set user review capacity")
+	var body PostUsersSetMaxOpenReviewsJSONBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "invalid JSON body")
+		return
+	}
+
+	if body.UserId == "" {
+		s.writeError(w, r, nethttp.StatusBadRequest, NOTFOUND, "user_id is required")
+		return
+	}
+
+	u, err := s.UserUseCase.SetMaxOpenReviews(r.Context(), body.UserId, body.MaxOpenReviews)
+	if err != nil {
+		status, code := mapDomainError(err)
+		s.writeError(w, r, status, code, err.Error())
 		return
 	}
 
-	resp := UsersSetIsActiveResponse{User: User{
-		UserId:   u.ID,
-		Username: u.Name,
-		TeamName: u.TeamName,
-		IsActive: u.IsActive,
-	}}
+	resp := UserResponse{User: UserFromEntity(*u)}
 
-	s.writeJSON(w, nethttp.StatusOK, resp)
-	s.log.Info("Request to set user active status processed successfully")
+	s.writeJSON(w, r, nethttp.StatusOK, resp)
+	logging.FromContext(r.Context(), s.log).Debug("Request to set user review capacity processed successfully")
 }
@@ -0,0 +1,96 @@
+// Package health serves the liveness and readiness probes of the service.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+	statusDraining    = "draining"
+)
+
+// Check returns an error when a dependency of the service is not usable.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the readiness checks. It is not ready once draining has begun.
+type Checker struct {
+	checks   []namedCheck
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// New returns a checker that bounds all checks of a probe by timeout.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a readiness check, it must be called before serving probes.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Drain makes readiness fail so that traffic moves to other instances before
+// the server shuts down.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+type response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// LiveHandler reports that the process is running.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, response{Status: statusOK})
+	})
+}
+
+// ReadyHandler runs every check and answers 503 if any of them fails or the
+// instance is draining.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.draining.Load() {
+			writeJSON(w, http.StatusServiceUnavailable, response{Status: statusDraining})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), c.timeout)
+		defer cancel()
+
+		resp := response{Status: statusOK, Checks: make(map[string]string, len(c.checks))}
+		for _, nc := range c.checks {
+			if err := nc.check(ctx); err != nil {
+				resp.Status = statusUnavailable
+				resp.Checks[nc.name] = err.Error()
+				continue
+			}
+			resp.Checks[nc.name] = statusOK
+		}
+
+		code := http.StatusOK
+		if resp.Status != statusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, resp)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,70 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/health"
+)
+
+type probe struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func get(t *testing.T, h http.Handler) (int, probe) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var p probe
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, p
+}
+
+func TestReadiness(t *testing.T) {
+	var dbErr error
+	checker := health.New(time.Second)
+	checker.Add("database", func(context.Context) error { return dbErr })
+	checker.Add("migrations", func(context.Context) error { return nil })
+
+	if code, p := get(t, checker.ReadyHandler()); code != http.StatusOK || p.Status != "ok" {
+		t.Fatalf("ready = %d %+v, want 200", code, p)
+	}
+
+	dbErr = errors.New("connection refused")
+	code, p := get(t, checker.ReadyHandler())
+	if code != http.StatusServiceUnavailable || p.Checks["database"] != "connection refused" {
+		t.Fatalf("ready with the database down = %d %+v, want 503 with the error", code, p)
+	}
+	if p.Checks["migrations"] != "ok" {
+		t.Fatalf("migrations check = %q, want ok", p.Checks["migrations"])
+	}
+
+	dbErr = nil
+	checker.Drain()
+	if code, p = get(t, checker.ReadyHandler()); code != http.StatusServiceUnavailable || p.Status != "draining" {
+		t.Fatalf("ready while draining = %d %+v, want 503", code, p)
+	}
+	if code, _ = get(t, checker.LiveHandler()); code != http.StatusOK {
+		t.Fatalf("live while draining = %d, want 200", code)
+	}
+}
+
+func TestReadinessCheckTimeout(t *testing.T) {
+	checker := health.New(10 * time.Millisecond)
+	checker.Add("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if code, p := get(t, checker.ReadyHandler()); code != http.StatusServiceUnavailable || p.Checks["database"] == "" {
+		t.Fatalf("ready with a hanging check = %d %+v, want 503", code, p)
+	}
+}
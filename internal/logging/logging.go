@@ -0,0 +1,82 @@
+// Package logging configures logrus and scopes loggers to requests: every
+// record logged with the context of a request carries its request id.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+const FieldRequestID = "request_id"
+
+func ValidFormat(format string) bool {
+	return format == FormatText || format == FormatJSON
+}
+
+// New returns a logger writing records in the format with sensitive fields
+// redacted.
+func New(out io.Writer, format, level string) (*log.Logger, error) {
+	logger := log.New()
+	logger.SetOutput(out)
+	switch format {
+	case "", FormatText:
+		logger.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	case FormatJSON:
+		logger.SetFormatter(&log.JSONFormatter{})
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	if level != "" {
+		lvl, err := log.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		logger.SetLevel(lvl)
+	}
+	logger.AddHook(RedactHook{})
+	return logger, nil
+}
+
+type ctxKey struct{}
+
+// NewContext returns ctx carrying the logger entry, e.g. one with the request
+// id field.
+func NewContext(ctx context.Context, entry *log.Entry) context.Context {
+	return context.WithValue(ctx, ctxKey{}, entry)
+}
+
+var discard = func() *log.Logger {
+	l := log.New()
+	l.SetOutput(io.Discard)
+	return l
+}()
+
+// FromContext returns the logger scoped to ctx. Without one it falls back to
+// the given logger, a nil fallback discards the records.
+func FromContext(ctx context.Context, fallback *log.Logger) *log.Entry {
+	if entry, ok := ctx.Value(ctxKey{}).(*log.Entry); ok {
+		return entry.WithContext(ctx)
+	}
+	if fallback == nil {
+		fallback = discard
+	}
+	return log.NewEntry(fallback).WithContext(ctx)
+}
+
+// RequestID returns the request id of ctx set by the RequestID middleware.
+func RequestID(ctx context.Context) string {
+	if entry, ok := ctx.Value(ctxKey{}).(*log.Entry); ok {
+		if id, ok := entry.Data[FieldRequestID].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("record %q is not JSON: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestMiddlewareScopesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := chi.NewRouter()
+	router.Use(logging.Middleware(logger, "/healthz"))
+	router.Get("/team/{name}", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context(), nil).Info("Getting team")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/team/backend", nil)
+	req.Header.Set(logging.HeaderRequestID, "req-42")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(logging.HeaderRequestID); got != "req-42" {
+		t.Fatalf("response request id = %q, want the one from the request", got)
+	}
+	recs := records(t, &buf)
+	if len(recs) != 2 {
+		t.Fatalf("got %d records, want the handler and the access record", len(recs))
+	}
+	for _, r := range recs {
+		if r[logging.FieldRequestID] != "req-42" {
+			t.Fatalf("record %v has no request id", r)
+		}
+	}
+	access := recs[1]
+	if access["route"] != "/team/{name}" || access["status"] != float64(http.StatusNotFound) {
+		t.Fatalf("access record = %v", access)
+	}
+	if _, ok := access["latency_ms"]; !ok {
+		t.Fatalf("access record %v has no latency", access)
+	}
+}
+
+func TestMiddlewareGeneratesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := logging.Middleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	for _, id := range []string{"", "bad id\nwith a line break", strings.Repeat("x", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logging.HeaderRequestID, id)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		got := rec.Header().Get(logging.HeaderRequestID)
+		if got == "" || got == id {
+			t.Fatalf("request id %q: response id = %q, want a generated one", id, got)
+		}
+	}
+}
+
+func TestRedactHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, logging.FormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.WithField("Authorization", "Bearer abc").
+		WithField("database_url", "postgres://pr_user:pr_pass@db:5432/prdb").
+		WithError(errors.New("dial postgres://pr_user:pr_pass@db:5432/prdb: refused")).
+		WithField("userID", "u1").
+		Info("connecting")
+
+	out := buf.String()
+	if strings.Contains(out, "abc") || strings.Contains(out, "pr_pass") {
+		t.Fatalf("record leaks a secret: %s", out)
+	}
+	rec := records(t, &buf)[0]
+	if rec["userID"] != "u1" {
+		t.Fatalf("userID = %v, plain fields must stay as is", rec["userID"])
+	}
+	if !strings.Contains(rec["error"].(string), "postgres://pr_user:[REDACTED]@db") {
+		t.Fatalf("error = %v, want the password redacted", rec["error"])
+	}
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// Middleware scopes a logger with the request id to the request context and
+// writes an access log record once the request is served. The id is taken
+// from the X-Request-ID header or generated, and echoed in the response.
+// Requests to the quiet paths, e.g. probes, are logged at debug level.
+func Middleware(logger *log.Logger, quietPaths ...string) func(http.Handler) http.Handler {
+	quiet := make(map[string]bool, len(quietPaths))
+	for _, p := range quietPaths {
+		quiet[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := r.Header.Get(HeaderRequestID)
+			if !validRequestID(id) {
+				id = rand.Text()
+			}
+			w.Header().Set(HeaderRequestID, id)
+
+			entry := logger.WithField(FieldRequestID, id)
+			ctx := NewContext(r.Context(), entry)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := ""
+			if rctx := chi.RouteContext(ctx); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+			access := entry.WithContext(ctx).WithFields(log.Fields{
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"route":      route,
+				"status":     status,
+				"bytes":      ww.BytesWritten(),
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"remote":     r.RemoteAddr,
+			})
+			switch {
+			case quiet[r.URL.Path]:
+				access.Debug("Request served")
+			case status >= http.StatusInternalServerError:
+				access.Error("Request served")
+			default:
+				access.Info("Request served")
+			}
+		})
+	}
+}
+
+// validRequestID accepts ids of printable ASCII without spaces, so a client
+// cannot inject line breaks or huge values into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
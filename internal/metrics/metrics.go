@@ -0,0 +1,146 @@
+// Package metrics exposes Prometheus metrics of the service: HTTP requests per
+// OpenAPI operation, database pool stats and domain counters and gauges.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+const namespace = "pr_reviewer"
+
+// Metrics owns a registry with every metric of the service. It implements
+// usecase.PRMetrics and usecase.LoadMetrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requestDuration *prometheus.HistogramVec
+	assignments     prometheus.Counter
+	reassignments   prometheus.Counter
+	noCandidate     *prometheus.CounterVec
+	merges          prometheus.Counter
+	openPRs         *prometheus.GaugeVec
+	idleReviewers   *prometheus.GaugeVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by OpenAPI operation and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "code"}),
+		assignments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "assignments_total",
+			Help:      "Reviewers assigned to PRs on creation or added later.",
+		}),
+		reassignments: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reassignments_total",
+			Help:      "Reviewers replaced by another reviewer.",
+		}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "no_candidate_total",
+			Help:      "Operations that found no reviewer to assign.",
+		}, []string{"operation"}),
+		merges: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "merges_total",
+			Help:      "PRs merged.",
+		}),
+		openPRs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "team_open_prs",
+			Help:      "Open PRs authored by members of the team.",
+		}, []string{"team"}),
+		idleReviewers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "team_idle_reviewers",
+			Help:      "Active members of the team without open reviews.",
+		}, []string{"team"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requestDuration,
+		m.assignments,
+		m.reassignments,
+		m.noCandidate,
+		m.merges,
+		m.openPRs,
+		m.idleReviewers,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware observes requests of a generated operation handler. The route is
+// the path template of the operation, so it does not grow with query values.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.requestDuration.WithLabelValues(r.Method, route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// RegisterDB exports connection stats of a database/sql pool.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+func (m *Metrics) ReviewersAssigned(n int) {
+	m.assignments.Add(float64(n))
+}
+
+func (m *Metrics) ReviewerReassigned() {
+	m.reassignments.Inc()
+}
+
+func (m *Metrics) NoCandidate(operation string) {
+	m.noCandidate.WithLabelValues(operation).Inc()
+}
+
+func (m *Metrics) PRMerged() {
+	m.merges.Inc()
+}
+
+// SetTeamLoad replaces the per-team gauges with the snapshot.
+func (m *Metrics) SetTeamLoad(loads []entity.TeamLoad) {
+	m.openPRs.Reset()
+	m.idleReviewers.Reset()
+	for _, l := range loads {
+		m.openPRs.WithLabelValues(l.TeamName).Set(float64(l.OpenPRs))
+		m.idleReviewers.WithLabelValues(l.TeamName).Set(float64(l.IdleReviewers))
+	}
+}
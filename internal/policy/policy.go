@@ -0,0 +1,157 @@
+// Package policy loads team selection policies from a YAML file and reloads
+// them when the file changes or on demand, e.g. on SIGHUP.
+//
+//	teams:
+//	  backend:
+//	    mode: fairness
+//	    max_reviewers: 3
+//	    capacity_policy: allow
+//	    fairness_window: 168h
+package policy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+// debounce merges the bursts of events editors produce on a single save.
+const debounce = 200 * time.Millisecond
+
+type file struct {
+	Teams map[string]teamPolicy `yaml:"teams"`
+}
+
+type teamPolicy struct {
+	Mode           string        `yaml:"mode"`
+	MaxReviewers   int           `yaml:"max_reviewers"`
+	CapacityPolicy string        `yaml:"capacity_policy"`
+	FairnessWindow time.Duration `yaml:"fairness_window"`
+}
+
+// Load parses the policies file, unknown keys are rejected.
+func Load(path string) (map[string]usecase.TeamPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read team policies: %w", err)
+	}
+
+	var f file
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err = dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse team policies %s: %w", path, err)
+	}
+
+	teams := make(map[string]usecase.TeamPolicy, len(f.Teams))
+	for name, tp := range f.Teams {
+		teams[name] = usecase.TeamPolicy{
+			Mode:           tp.Mode,
+			MaxReviewers:   tp.MaxReviewers,
+			OverCapacity:   tp.CapacityPolicy,
+			FairnessWindow: tp.FairnessWindow,
+		}
+	}
+	return teams, nil
+}
+
+// Reloader keeps the team policies in sync with the file.
+type Reloader struct {
+	path     string
+	policies *usecase.TeamPolicies
+	log      *log.Logger
+
+	// mu serializes reloads from the watcher and SIGHUP, so policies read from
+	// an older version of the file never replace newer ones.
+	mu sync.Mutex
+}
+
+// NewReloader loads the file into policies. A broken file fails the start, a
+// later broken version only logs an error.
+func NewReloader(path string, policies *usecase.TeamPolicies, logger *log.Logger) (*Reloader, error) {
+	r := &Reloader{path: path, policies: policies, log: logger}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	teams, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+	return r.policies.Replace(teams)
+}
+
+// Reload applies the current file. An invalid file is logged and the policies
+// in use are kept.
+func (r *Reloader) Reload() error {
+	entry := r.log.WithFields(log.Fields{"component": "policy", "path": r.path})
+	if err := r.reload(); err != nil {
+		entry.WithError(err).Error("Rejected team policies, keeping the current ones")
+		return err
+	}
+	entry.Info("Reloaded team policies")
+	return nil
+}
+
+// Watch reloads the policies on changes of the file until ctx is done. The
+// directory is watched, so atomic saves by rename and Kubernetes ConfigMap
+// symlink swaps are seen as well.
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err = watcher.Add(filepath.Dir(r.path)); err != nil {
+		return fmt.Errorf("watch %s: %w", filepath.Dir(r.path), err)
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	name := filepath.Base(r.path)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// ConfigMaps swap the ..data symlink instead of writing the file
+			if base := filepath.Base(ev.Name); base != name && base != "..data" {
+				continue
+			}
+			if ev.Has(fsnotify.Write) || ev.Has(fsnotify.Create) || ev.Has(fsnotify.Rename) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			r.log.WithError(err).WithField("component", "policy").Warn("File watcher error")
+		case <-timer.C:
+			_ = r.Reload()
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package policy_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/policy"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	// write next to the file and rename, as editors and config managers do
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newReloader(t *testing.T, content string) (*policy.Reloader, *usecase.TeamPolicies, string) {
+	t.Helper()
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	path := filepath.Join(t.TempDir(), "teams.yml")
+	writeFile(t, path, content)
+	teams := &usecase.TeamPolicies{}
+	reloader, err := policy.NewReloader(path, teams, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reloader, teams, path
+}
+
+func TestLoad(t *testing.T) {
+	_, teams, _ := newReloader(t, `
+teams:
+  backend:
+    mode: fairness
+    max_reviewers: 3
+    capacity_policy: allow
+    fairness_window: 168h
+`)
+
+	got, ok := teams.Get("backend")
+	want := usecase.TeamPolicy{Mode: "fairness", MaxReviewers: 3, OverCapacity: "allow", FairnessWindow: 168 * time.Hour}
+	if !ok || got != want {
+		t.Fatalf("backend policy = %+v, want %+v", got, want)
+	}
+	if _, ok = teams.Get("frontend"); ok {
+		t.Fatal("frontend has a policy that is not in the file")
+	}
+}
+
+func TestLoadRejectsInvalidFile(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":  "teams:\n  backend:\n    reviewers: 3\n",
+		"unknown mode": "teams:\n  backend:\n    mode: roundrobin\n",
+		"negative":     "teams:\n  backend:\n    max_reviewers: -1\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "teams.yml")
+			writeFile(t, path, content)
+			if _, err := policy.NewReloader(path, &usecase.TeamPolicies{}, log.New()); err == nil {
+				t.Fatal("invalid file was accepted")
+			}
+		})
+	}
+}
+
+func TestReloadKeepsPoliciesOnError(t *testing.T) {
+	reloader, teams, path := newReloader(t, "teams:\n  backend:\n    max_reviewers: 3\n")
+
+	writeFile(t, path, "teams:\n  backend:\n    max_reviewers: 3\n  frontend:\n    mode: roundrobin\n")
+	if err := reloader.Reload(); err == nil {
+		t.Fatal("invalid file was accepted")
+	}
+	if got, _ := teams.Get("backend"); got.MaxReviewers != 3 {
+		t.Fatalf("backend policy = %+v, want the previous one", got)
+	}
+
+	writeFile(t, path, "teams:\n  backend:\n    max_reviewers: 1\n")
+	if err := reloader.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := teams.Get("backend"); got.MaxReviewers != 1 {
+		t.Fatalf("backend policy = %+v, want the reloaded one", got)
+	}
+}
+
+func TestConcurrentReloadsKeepTheLatestFile(t *testing.T) {
+	reloader, teams, path := newReloader(t, "teams:\n  backend:\n    max_reviewers: 1\n")
+
+	// each change is followed by a reload racing with the next changes
+	var wg sync.WaitGroup
+	for i := 2; i <= 200; i++ {
+		writeFile(t, path, fmt.Sprintf("teams:\n  backend:\n    max_reviewers: %d\n", i))
+		wg.Go(func() { _ = reloader.Reload() })
+	}
+	wg.Wait()
+
+	if got, _ := teams.Get("backend"); got.MaxReviewers != 200 {
+		t.Fatalf("backend policy = %+v, want the last version of the file", got)
+	}
+}
+
+func TestWatchReloadsOnChange(t *testing.T) {
+	reloader, teams, path := newReloader(t, "teams:\n  backend:\n    max_reviewers: 3\n")
+
+	done := make(chan error, 1)
+	go func() { done <- reloader.Watch(t.Context()) }()
+	// give the watcher time to register before the change
+	time.Sleep(50 * time.Millisecond)
+
+	writeFile(t, path, "teams:\n  backend:\n    max_reviewers: 4\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got, _ := teams.Get("backend"); got.MaxReviewers == 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("policies were not reloaded after the file changed")
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("watch stopped: %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package prctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/client"
+)
+
+func (c *CLI) teamAdd(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	team := client.Team{TeamName: fs.Arg(0), Members: make([]client.TeamMember, 0, fs.NArg()-1)}
+	for _, arg := range fs.Args()[1:] {
+		id, name, ok := strings.Cut(arg, "=")
+		if !ok || id == "" || name == "" {
+			fmt.Fprintf(c.Stderr, "member %q is not USER_ID=USERNAME\n", arg)
+			return ErrUsage
+		}
+		team.Members = append(team.Members, client.TeamMember{UserId: id, Username: name, IsActive: true})
+	}
+
+	resp, err := c.api.PostTeamAddWithResponse(ctx, team)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+	return c.printTeam(*resp.JSON201.Team)
+}
+
+func (c *CLI) teamGet(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	var team client.Team
+	params := client.GetTeamGetParams{TeamName: fs.Arg(0)}
+	for {
+		resp, err := c.api.GetTeamGetWithResponse(ctx, &params)
+		if err != nil {
+			return err
+		}
+		if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+			return err
+		}
+		page := resp.JSON200
+		team.TeamName, team.MaxOpenReviews = page.TeamName, page.MaxOpenReviews
+		team.Members = append(team.Members, page.Members...)
+		if page.NextCursor == nil {
+			break
+		}
+		params.Cursor = page.NextCursor
+	}
+	return c.printTeam(team)
+}
+
+func (c *CLI) printTeam(team client.Team) error {
+	return c.print(team, formatTable, func(w io.Writer) {
+		row(w, "USER_ID", "USERNAME", "ACTIVE", "MAX_OPEN_REVIEWS")
+		for _, m := range team.Members {
+			row(w, m.UserId, m.Username, m.IsActive, intOrDash(m.MaxOpenReviews))
+		}
+	})
+}
+
+func (c *CLI) userActivate(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	return c.setIsActive(ctx, fs, args, true)
+}
+
+func (c *CLI) userDeactivate(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	return c.setIsActive(ctx, fs, args, false)
+}
+
+// setIsActive updates the users one by one and stops at the first error.
+func (c *CLI) setIsActive(ctx context.Context, fs *flag.FlagSet, args []string, isActive bool) error {
+	if err := parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	users := make([]client.User, 0, fs.NArg())
+	for _, id := range fs.Args() {
+		resp, err := c.api.PostUsersSetIsActiveWithResponse(ctx,
+			client.PostUsersSetIsActiveJSONRequestBody{UserId: id, IsActive: isActive})
+		if err != nil {
+			return err
+		}
+		if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+			return fmt.Errorf("user %s: %w", id, err)
+		}
+		users = append(users, *resp.JSON200.User)
+	}
+
+	return c.print(users, formatTable, func(w io.Writer) {
+		row(w, "USER_ID", "USERNAME", "TEAM", "ACTIVE")
+		for _, u := range users {
+			row(w, u.UserId, u.Username, u.TeamName, u.IsActive)
+		}
+	})
+}
+
+func (c *CLI) prCreate(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	title := fs.String("title", "", "pull request title")
+	author := fs.String("author", "", "author user id")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if *title == "" || *author == "" {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	resp, err := c.api.PostPullRequestCreateWithResponse(ctx, client.PostPullRequestCreateJSONRequestBody{
+		PullRequestId:   fs.Arg(0),
+		PullRequestName: *title,
+		AuthorId:        *author,
+	})
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+	return c.printPRs(resp.JSON201.Pr, *resp.JSON201.Pr)
+}
+
+func (c *CLI) prMerge(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	resp, err := c.api.PostPullRequestMergeWithResponse(ctx,
+		client.PostPullRequestMergeJSONRequestBody{PullRequestId: fs.Arg(0)})
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+	return c.printPRs(resp.JSON200.Pr, *resp.JSON200.Pr)
+}
+
+func (c *CLI) prReassign(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	to := fs.String("to", "", "hand the review to this user instead of a random one")
+	team := fs.String("team", "", "pick the replacement from this team instead of the reviewer's one")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	body := client.PostPullRequestReassignJSONRequestBody{PullRequestId: fs.Arg(0), OldUserId: fs.Arg(1)}
+	if *to != "" {
+		body.NewUserId = to
+	}
+	if *team != "" {
+		body.CandidateTeam = team
+	}
+	resp, err := c.api.PostPullRequestReassignWithResponse(ctx, body)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+
+	// without dry_run the response is always a ReassignResult
+	result, err := resp.JSON200.AsReassignResult()
+	if err != nil {
+		return err
+	}
+	return c.print(result, formatTable, func(w io.Writer) {
+		row(w, "ID", "TITLE", "AUTHOR", "STATUS", "REVIEWERS", "REPLACED", "REPLACED_BY")
+		pr := result.Pr
+		row(w, pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status, reviewers(pr), fs.Arg(1),
+			result.ReplacedBy)
+	})
+}
+
+func (c *CLI) prList(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	team := fs.String("team", "", "pull requests of the team members")
+	author := fs.String("author", "", "pull requests of the author")
+	reviewer := fs.String("reviewer", "", "pull requests assigned to the reviewer")
+	status := fs.String("status", "", "OPEN or MERGED")
+	limit := fs.Int("limit", 0, "stop after this many pull requests, 0 lists all")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var params client.GetPullRequestListParams
+	if *team != "" {
+		params.TeamName = team
+	}
+	if *author != "" {
+		params.AuthorId = author
+	}
+	if *reviewer != "" {
+		params.ReviewerId = reviewer
+	}
+	if *status != "" {
+		s := client.GetPullRequestListParamsStatus(strings.ToUpper(*status))
+		params.Status = &s
+	}
+
+	prs := make([]client.PullRequest, 0)
+	for *limit <= 0 || len(prs) < *limit {
+		resp, err := c.api.GetPullRequestListWithResponse(ctx, &params)
+		if err != nil {
+			return err
+		}
+		if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+			return err
+		}
+		prs = append(prs, resp.JSON200.PullRequests...)
+		if resp.JSON200.NextCursor == nil {
+			break
+		}
+		params.Cursor = resp.JSON200.NextCursor
+	}
+	if *limit > 0 && len(prs) > *limit {
+		prs = prs[:*limit]
+	}
+	return c.printPRs(prs, prs...)
+}
+
+// printPRs prints v in the structured formats and the pull requests as a table.
+func (c *CLI) printPRs(v any, prs ...client.PullRequest) error {
+	return c.print(v, formatTable, func(w io.Writer) {
+		row(w, "ID", "TITLE", "AUTHOR", "STATUS", "REVIEWERS")
+		for _, pr := range prs {
+			row(w, pr.PullRequestId, pr.PullRequestName, pr.AuthorId, pr.Status, reviewers(pr))
+		}
+	})
+}
+
+func reviewers(pr client.PullRequest) string {
+	return orDash(strings.Join(pr.AssignedReviewers, ","))
+}
+
+func (c *CLI) stats(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	resp, err := c.api.GetTeamReviewStatsWithResponse(ctx, &client.GetTeamReviewStatsParams{TeamName: fs.Arg(0)})
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+
+	stats := resp.JSON200
+	return c.print(stats, formatTable, func(w io.Writer) {
+		sla := "-"
+		if stats.ReviewSla != nil {
+			sla = *stats.ReviewSla
+		}
+		row(w, "TEAM", stats.TeamName)
+		row(w, "PULL_REQUESTS", stats.PrCount)
+		row(w, "MERGED", stats.MergedCount)
+		row(w, "OPEN_ASSIGNMENTS", stats.OpenAssignments)
+		row(w, "OVERDUE_ASSIGNMENTS", stats.OverdueAssignments)
+		row(w, "AVG_TIME_TO_MERGE", time.Duration(stats.AvgTimeToMergeSeconds)*time.Second)
+		row(w, "REVIEW_SLA", sla)
+		reasons := make([]string, 0, len(stats.Declines))
+		for reason := range stats.Declines {
+			reasons = append(reasons, reason)
+		}
+		slices.Sort(reasons)
+		for _, reason := range reasons {
+			row(w, "DECLINES_"+reason, stats.Declines[reason])
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package prctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/client"
+)
+
+// export prints the org manifest, YAML unless --output is set, so that it
+// can be edited and passed to import.
+func (c *CLI) export(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	resp, err := c.api.GetOrgExportWithResponse(ctx)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+
+	manifest := resp.JSON200
+	return c.print(manifest, formatYAML, func(w io.Writer) {
+		row(w, "TEAM", "USER_ID", "USERNAME", "ACTIVE")
+		for _, t := range manifest.Teams {
+			for _, m := range t.Members {
+				row(w, t.TeamName, m.UserId, m.Username, m.IsActive == nil || *m.IsActive)
+			}
+		}
+	})
+}
+
+// importOrg syncs the org with a YAML or JSON manifest, "-" reads stdin.
+func (c *CLI) importOrg(ctx context.Context, fs *flag.FlagSet, args []string) error {
+	dryRun := fs.Bool("dry-run", false, "print the plan without applying it")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	manifest, err := c.readManifest(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	resp, err := c.api.PostOrgSyncWithResponse(ctx, &client.PostOrgSyncParams{DryRun: dryRun}, *manifest)
+	if err != nil {
+		return err
+	}
+	if err = checkResponse(resp.StatusCode(), resp.Body); err != nil {
+		return err
+	}
+
+	result := resp.JSON200
+	return c.print(result, formatTable, func(w io.Writer) {
+		writeSyncResult(w, result)
+	})
+}
+
+func (c *CLI) readManifest(path string) (*client.OrgManifest, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(c.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is decoded through JSON to apply the field names of the API schema
+	var raw any
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var m client.OrgManifest
+	if err = dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// writeSyncResult prints the plan the way `server sync` does: + for new
+// entries, - for removed ones and ~ for changes.
+func writeSyncResult(w io.Writer, result *client.OrgSyncResult) {
+	plan := result.Plan
+	if len(plan.AddTeams)+len(plan.RemoveTeams)+len(plan.Users)+len(plan.Reassign) == 0 {
+		fmt.Fprintln(w, "No changes, the server matches the manifest.")
+		return
+	}
+
+	for _, name := range plan.AddTeams {
+		fmt.Fprintf(w, "  + team %s\n", name)
+	}
+	for _, name := range plan.RemoveTeams {
+		fmt.Fprintf(w, "  - team %s\n", name)
+	}
+	for _, c := range plan.Users {
+		fmt.Fprintf(w, "  %s\n", describeUserChange(c))
+	}
+	for _, r := range plan.Reassign {
+		fmt.Fprintf(w, "  ~ review %s: %s -> %s within %s\n", r.PullRequestId, r.ReviewerId, r.ReplacedBy, r.TeamName)
+	}
+	fmt.Fprintf(w, "\nPlan: %d teams to add, %d to remove, %d users to change, %d reviews to reassign.\n",
+		len(plan.AddTeams), len(plan.RemoveTeams), len(plan.Users), len(plan.Reassign))
+
+	if result.DryRun {
+		fmt.Fprintln(w, "Dry run, nothing was changed.")
+		return
+	}
+	fmt.Fprintln(w, "Applied.")
+}
+
+func describeUserChange(c client.OrgUserChange) string {
+	after := c.User
+	if c.Before == nil {
+		inactive := ""
+		if !after.IsActive {
+			inactive = ", inactive"
+		}
+		return fmt.Sprintf("+ user %s (%s) in %s%s", after.UserId, after.Username, after.TeamName, inactive)
+	}
+
+	before := *c.Before
+	var diff []string
+	if before.TeamName != after.TeamName {
+		diff = append(diff, fmt.Sprintf("team %s -> %s", before.TeamName, after.TeamName))
+	}
+	if before.Username != after.Username {
+		diff = append(diff, fmt.Sprintf("name %q -> %q", before.Username, after.Username))
+	}
+	if before.IsActive != after.IsActive {
+		diff = append(diff, fmt.Sprintf("active %t -> %t", before.IsActive, after.IsActive))
+	}
+	return fmt.Sprintf("~ user %s (%s): %s", after.UserId, before.Username, strings.Join(diff, ", "))
+}
@@ -0,0 +1,178 @@
+// Package prctl implements the prctl command line client of the service API.
+package prctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/client"
+)
+
+const (
+	defaultServer  = "http://localhost:8080"
+	defaultTimeout = 30 * time.Second
+)
+
+// ErrUsage is returned for unknown commands and wrong arguments, the usage
+// is already printed to Stderr.
+var ErrUsage = errors.New("invalid usage")
+
+// APIError is an error response of the server.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("server responded with %d: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// checkResponse turns a non-2xx response into an APIError.
+func checkResponse(status int, body []byte) error {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	var resp client.ErrorResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error.Code == "" {
+		return &APIError{Status: status, Message: strings.TrimSpace(string(body))}
+	}
+	return &APIError{Status: status, Code: string(resp.Error.Code), Message: resp.Error.Message}
+}
+
+// CLI holds the environment of a prctl run.
+type CLI struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Getenv func(string) string
+
+	api    *client.ClientWithResponses
+	output string
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(c *CLI, ctx context.Context, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"team add", "team add TEAM [USER_ID=USERNAME ...]", (*CLI).teamAdd},
+	{"team get", "team get TEAM", (*CLI).teamGet},
+	{"user activate", "user activate USER_ID ...", (*CLI).userActivate},
+	{"user deactivate", "user deactivate USER_ID ...", (*CLI).userDeactivate},
+	{"pr create", "pr create --title TITLE --author USER_ID PR_ID", (*CLI).prCreate},
+	{"pr merge", "pr merge PR_ID", (*CLI).prMerge},
+	{"pr reassign", "pr reassign [--to USER_ID] [--team TEAM] PR_ID OLD_REVIEWER_ID", (*CLI).prReassign},
+	{"pr list", "pr list [--team TEAM] [--author USER_ID] [--reviewer USER_ID] [--status STATUS] [--limit N]",
+		(*CLI).prList},
+	{"stats", "stats TEAM", (*CLI).stats},
+	{"export", "export", (*CLI).export},
+	{"import", "import [--dry-run] MANIFEST", (*CLI).importOrg},
+}
+
+// Run executes prctl [global flags] COMMAND [flags] [args].
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("prctl", flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	server := fs.String("server", c.env("PRCTL_SERVER", defaultServer), "service URL (PRCTL_SERVER)")
+	fs.StringVar(&c.output, "output", "", "output format: table, json or yaml")
+	fs.StringVar(&c.output, "o", "", "shorthand for --output")
+	timeout := fs.Duration("timeout", defaultTimeout, "request timeout")
+	fs.Usage = c.usage(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return ErrUsage
+	}
+	switch c.output {
+	case "", formatTable, formatJSON, formatYAML:
+	default:
+		fmt.Fprintf(c.Stderr, "unknown output format %q\n", c.output)
+		return ErrUsage
+	}
+
+	cmd, rest := findCommand(fs.Args())
+	if cmd == nil {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	api, err := client.NewClientWithResponses(strings.TrimSuffix(*server, "/"),
+		client.WithHTTPClient(&http.Client{Timeout: *timeout}))
+	if err != nil {
+		return err
+	}
+	c.api = api
+
+	cmdFlags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cmdFlags.SetOutput(c.Stderr)
+	cmdFlags.Usage = func() {
+		fmt.Fprintf(c.Stderr, "Usage: prctl %s\n", cmd.usage)
+		cmdFlags.PrintDefaults()
+	}
+	return cmd.run(c, ctx, cmdFlags, rest)
+}
+
+func (c *CLI) env(key, fallback string) string {
+	if c.Getenv != nil {
+		if v := c.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return fallback
+}
+
+func (c *CLI) usage(fs *flag.FlagSet) func() {
+	return func() {
+		fmt.Fprintln(c.Stderr, "Usage: prctl [flags] COMMAND [args]")
+		fmt.Fprintln(c.Stderr, "\nCommands:")
+		for _, cmd := range commands {
+			fmt.Fprintf(c.Stderr, "  %s\n", cmd.usage)
+		}
+		fmt.Fprintln(c.Stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+}
+
+// findCommand matches one or two leading words against the command names.
+func findCommand(args []string) (*command, []string) {
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == commands[i].name {
+			return &commands[i], args[len(words):]
+		}
+	}
+	return nil, nil
+}
+
+// parse parses the flags of a command and checks the number of positional
+// arguments, maxArgs < 0 means any number above minArgs.
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return ErrUsage
+	}
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fs.Usage()
+		return ErrUsage
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package prctl_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	gwhttp "github.com/Xausdorf/pr-reviewer-assignment/internal/gateway/http"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/prctl"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/client"
+)
+
+// newServer serves the API on the in-memory storage.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepository(store)
+	prUseCase := usecase.NewPRUseCase(memory.NewPRRepository(store), userRepo, memory.NewTeamRepository(store),
+		memory.NewScheduleRepository(store), memory.NewReviewerAuditRepository(store),
+		memory.NewDeclineRepository(store), usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(1)}, nil, logger)
+	userUseCase := usecase.NewUserUseCase(userRepo, logger)
+	server := gwhttp.NewServer(
+		prUseCase,
+		usecase.NewTeamUseCase(memory.NewTeamRepository(store), logger),
+		userUseCase,
+		usecase.NewScheduleUseCase(memory.NewScheduleRepository(store), userRepo, logger),
+		usecase.NewStatusChangeUseCase(memory.NewStatusChangeRepository(store), userRepo, userUseCase, logger),
+		usecase.NewSLAUseCase(memory.NewSLARepository(store), prUseCase, entity.EscalationModeNone, logger),
+		usecase.NewOrgUseCase(memory.NewOrgRepository(store), userRepo, prUseCase, logger),
+		logger,
+	)
+
+	srv := httptest.NewServer(gwhttp.Handler(server))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// run executes prctl against srv and returns its stdout.
+func run(t *testing.T, srv *httptest.Server, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	cli := &prctl.CLI{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Getenv: func(key string) string {
+			if key == "PRCTL_SERVER" {
+				return srv.URL
+			}
+			return ""
+		},
+	}
+	err := cli.Run(t.Context(), args)
+	return stdout.String(), err
+}
+
+func mustRun(t *testing.T, srv *httptest.Server, args ...string) string {
+	t.Helper()
+
+	out, err := run(t, srv, "", args...)
+	if err != nil {
+		t.Fatalf("prctl %s: %v", strings.Join(args, " "), err)
+	}
+	return out
+}
+
+// tableRow returns the cells of the table row starting with first.
+func tableRow(out, first string) []string {
+	for _, line := range strings.Split(out, "\n") {
+		if cells := strings.Fields(line); len(cells) > 0 && cells[0] == first {
+			return cells
+		}
+	}
+	return []string{""}
+}
+
+func decodeJSON[T any](t *testing.T, out string) T {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	return v
+}
+
+// decodeYAML decodes YAML through JSON, the client types have JSON tags only.
+func decodeYAML[T any](t *testing.T, out string) T {
+	t.Helper()
+
+	var raw any
+	if err := yaml.Unmarshal([]byte(out), &raw); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decodeJSON[T](t, string(data))
+}
+
+func addBackend(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	mustRun(t, srv, "team", "add", "backend", "u1=Alice", "u2=Bob", "u3=Carol", "u4=Dave")
+}
+
+func TestTeamAndUsers(t *testing.T) {
+	srv := newServer(t)
+
+	out := mustRun(t, srv, "team", "add", "backend", "u1=Alice", "u2=Bob")
+	if got := tableRow(out, "u2"); !slices.Equal(got, []string{"u2", "Bob", "true", "-"}) {
+		t.Fatalf("team add table:\n%s", out)
+	}
+
+	out = mustRun(t, srv, "user", "deactivate", "u2")
+	if got := tableRow(out, "u2"); !slices.Equal(got, []string{"u2", "Bob", "backend", "false"}) {
+		t.Fatalf("user deactivate table:\n%s", out)
+	}
+
+	team := decodeJSON[client.Team](t, mustRun(t, srv, "-o", "json", "team", "get", "backend"))
+	if team.TeamName != "backend" || len(team.Members) != 2 || team.Members[1].IsActive {
+		t.Fatalf("team = %+v, want u2 inactive", team)
+	}
+
+	users := decodeJSON[[]client.User](t, mustRun(t, srv, "--output", "json", "user", "activate", "u1", "u2"))
+	if len(users) != 2 || !users[0].IsActive || !users[1].IsActive {
+		t.Fatalf("users = %+v, want both active", users)
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	srv := newServer(t)
+	addBackend(t, srv)
+
+	pr := decodeJSON[client.PullRequest](t,
+		mustRun(t, srv, "-o", "json", "pr", "create", "--title", "Add search", "--author", "u1", "pr-1"))
+	if pr.Status != "OPEN" || len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("created pr = %+v, want 2 reviewers", pr)
+	}
+
+	old := pr.AssignedReviewers[0]
+	var free string
+	for _, id := range []string{"u2", "u3", "u4"} {
+		if !slices.Contains(pr.AssignedReviewers, id) {
+			free = id
+		}
+	}
+	out := mustRun(t, srv, "pr", "reassign", "--to", free, "pr-1", old)
+	if got := tableRow(out, "pr-1"); len(got) != 8 || got[6] != old || got[7] != free {
+		t.Fatalf("reassign table:\n%s", out)
+	}
+
+	mustRun(t, srv, "pr", "create", "--title", "Fix login", "--author", "u2", "pr-2")
+	mustRun(t, srv, "pr", "merge", "pr-1")
+
+	out = mustRun(t, srv, "-o", "yaml", "pr", "list", "--status", "merged")
+	merged := decodeYAML[[]client.PullRequest](t, out)
+	if len(merged) != 1 || merged[0].PullRequestId != "pr-1" || !slices.Contains(merged[0].AssignedReviewers, free) {
+		t.Fatalf("merged = %+v, want pr-1 reviewed by %s", merged, free)
+	}
+	if !strings.Contains(out, "pull_request_id: pr-1") {
+		t.Fatalf("yaml keys do not follow the API schema:\n%s", out)
+	}
+
+	all := decodeJSON[[]client.PullRequest](t, mustRun(t, srv, "-o", "json", "pr", "list", "--team", "backend"))
+	if len(all) != 2 {
+		t.Fatalf("listed %d pull requests, want 2", len(all))
+	}
+	limited := decodeJSON[[]client.PullRequest](t, mustRun(t, srv, "-o", "json", "pr", "list", "--limit", "1"))
+	if len(limited) != 1 {
+		t.Fatalf("listed %d pull requests with --limit 1", len(limited))
+	}
+
+	out = mustRun(t, srv, "stats", "backend")
+	if tableRow(out, "PULL_REQUESTS")[1] != "2" || tableRow(out, "MERGED")[1] != "1" {
+		t.Fatalf("stats table:\n%s", out)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	srv := newServer(t)
+	addBackend(t, srv)
+
+	exported := mustRun(t, srv, "export")
+	manifest := decodeYAML[client.OrgManifest](t, exported)
+	if len(manifest.Teams) != 1 || len(manifest.Teams[0].Members) != 4 || manifest.Teams[0].Members[3].UserId != "u4" {
+		t.Fatalf("exported manifest = %+v", manifest)
+	}
+
+	// u4 moves to a new team, the rest stays
+	edited := `teams:
+  - team_name: backend
+    members:
+      - { user_id: u1, username: Alice }
+      - { user_id: u2, username: Bob }
+      - { user_id: u3, username: Carol }
+  - team_name: platform
+    members:
+      - { user_id: u4, username: Dave }
+`
+	path := filepath.Join(t.TempDir(), "org.yml")
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := mustRun(t, srv, "import", "--dry-run", path)
+	for _, line := range []string{"+ team platform", "~ user u4 (Dave): team backend -> platform", "Dry run"} {
+		if !strings.Contains(out, line) {
+			t.Fatalf("dry run plan does not contain %q:\n%s", line, out)
+		}
+	}
+
+	result := decodeJSON[client.OrgSyncResult](t, mustRun(t, srv, "-o", "json", "import", path))
+	if result.DryRun || len(result.Plan.Users) != 1 {
+		t.Fatalf("sync result = %+v", result)
+	}
+
+	// the export reads back as the applied manifest and stdin is accepted
+	exported = mustRun(t, srv, "export")
+	manifest = decodeYAML[client.OrgManifest](t, exported)
+	if len(manifest.Teams) != 2 || manifest.Teams[1].TeamName != "platform" ||
+		len(manifest.Teams[1].Members) != 1 || manifest.Teams[1].Members[0].UserId != "u4" {
+		t.Fatalf("exported manifest after import = %+v", manifest)
+	}
+	out, err := run(t, srv, exported, "import", "-")
+	if err != nil || !strings.Contains(out, "No changes") {
+		t.Fatalf("second import = %q, %v", out, err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := newServer(t)
+
+	_, err := run(t, srv, "", "team", "get", "missing")
+	var apiErr *prctl.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound || apiErr.Code != "NOT_FOUND" {
+		t.Fatalf("error = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	srv := newServer(t)
+
+	for name, args := range map[string][]string{
+		"unknown command":  {"teams"},
+		"missing argument": {"pr", "merge"},
+		"missing flag":     {"pr", "create", "pr-1"},
+		"bad member":       {"team", "add", "backend", "u1"},
+		"bad output":       {"-o", "xml", "team", "get", "backend"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := run(t, srv, "", args...); !errors.Is(err, prctl.ErrUsage) {
+				t.Fatalf("error = %v, want %v", err, prctl.ErrUsage)
+			}
+		})
+	}
+}
+
+func TestClientDecodesReassignDryRun(t *testing.T) {
+	srv := newServer(t)
+	addBackend(t, srv)
+	pr := decodeJSON[client.PullRequest](t,
+		mustRun(t, srv, "-o", "json", "pr", "create", "--title", "Add search", "--author", "u1", "pr-1"))
+
+	api, err := client.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dryRun := true
+	resp, err := api.PostPullRequestReassignWithResponse(t.Context(), client.PostPullRequestReassignJSONRequestBody{
+		PullRequestId: "pr-1",
+		OldUserId:     pr.AssignedReviewers[0],
+		DryRun:        &dryRun,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JSON200 == nil {
+		t.Fatalf("status %d: %s", resp.StatusCode(), resp.Body)
+	}
+	sim, err := resp.JSON200.AsAssignmentSimulation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sim.DryRun || sim.TeamName != "backend" || len(sim.Candidates) != 4 || len(sim.Reviewers) != 1 {
+		t.Fatalf("simulation = %+v", sim)
+	}
+}
+
+func TestTeamGetWithoutLimitReturnsAllMembers(t *testing.T) {
+	srv := newServer(t)
+	api, err := client.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// more members than a default page of 50 holds
+	team := client.Team{TeamName: "big"}
+	for i := range 60 {
+		team.Members = append(team.Members, client.TeamMember{
+			UserId: fmt.Sprintf("u%02d", i), Username: fmt.Sprintf("User %d", i), IsActive: true,
+		})
+	}
+	if _, err = api.PostTeamAddWithResponse(t.Context(), team); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := api.GetTeamGetWithResponse(t.Context(), &client.GetTeamGetParams{TeamName: "big"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JSON200 == nil {
+		t.Fatalf("status %d: %s", resp.StatusCode(), resp.Body)
+	}
+	if len(resp.JSON200.Members) != 60 || resp.JSON200.NextCursor != nil {
+		t.Fatalf("got %d members, next cursor %v, want all 60 without a cursor",
+			len(resp.JSON200.Members), resp.JSON200.NextCursor)
+	}
+
+	limit := 20
+	resp, err = api.GetTeamGetWithResponse(t.Context(), &client.GetTeamGetParams{TeamName: "big", Limit: &limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.JSON200.Members) != 20 || resp.JSON200.NextCursor == nil {
+		t.Fatalf("got %d members with limit 20, want a page of 20 with a cursor", len(resp.JSON200.Members))
+	}
+}
+
+func TestImportFailsWithoutReplacement(t *testing.T) {
+	srv := newServer(t)
+	addBackend(t, srv)
+	mustRun(t, srv, "pr", "create", "--title", "Add search", "--author", "u1", "pr-1")
+
+	// only the author stays active, nobody can take over the reviews
+	manifest := "teams:\n  - team_name: backend\n    members:\n      - { user_id: u1, username: Alice }\n"
+	if _, err := run(t, srv, manifest, "import", "-"); err == nil || !strings.Contains(err.Error(), "NO_CANDIDATE") {
+		t.Fatalf("error = %v, want NO_CANDIDATE", err)
+	}
+
+	out := mustRun(t, srv, "-o", "json", "export")
+	exported := decodeJSON[client.OrgManifest](t, out)
+	if len(exported.Teams) != 1 || len(exported.Teams[0].Members) != 4 {
+		t.Fatalf("exported org = %+v, want the roster unchanged", exported)
+	}
+	for _, m := range exported.Teams[0].Members {
+		if m.IsActive != nil && !*m.IsActive {
+			t.Fatalf("%s is deactivated by a failed import", m.UserId)
+		}
+	}
+}
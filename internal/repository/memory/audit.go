@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type ReviewerAuditRepository struct {
+	store *Store
+}
+
+func NewReviewerAuditRepository(store *Store) *ReviewerAuditRepository {
+	return &ReviewerAuditRepository{store: store}
+}
+
+func (r *ReviewerAuditRepository) Record(
+	_ context.Context,
+	e entity.ReviewerAuditEntry,
+) (*entity.ReviewerAuditEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.prs[e.PRID]; !ok {
+		return nil, apperror.ErrNotFound
+	}
+	if _, ok := r.store.users[e.ReviewerID]; !ok {
+		return nil, apperror.ErrNotFound
+	}
+	e.ID = r.store.nextID()
+	r.store.reviewerAudit[e.ID] = e
+
+	return &e, nil
+}
+
+func (r *ReviewerAuditRepository) ListForPR(_ context.Context, prID string) ([]entity.ReviewerAuditEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entries := make([]entity.ReviewerAuditEntry, 0)
+	for _, e := range r.store.reviewerAudit {
+		if e.PRID == prID {
+			entries = append(entries, e)
+		}
+	}
+	slices.SortFunc(entries, func(a, b entity.ReviewerAuditEntry) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return entries, nil
+}
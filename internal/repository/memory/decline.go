@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type DeclineRepository struct {
+	store *Store
+}
+
+func NewDeclineRepository(store *Store) *DeclineRepository {
+	return &DeclineRepository{store: store}
+}
+
+// Record hands the declined review over to d.NewReviewerID and stores the
+// decline. The PR must be open and reviewed by d.ReviewerID.
+func (r *DeclineRepository) Record(_ context.Context, d entity.Decline) (*entity.Decline, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkReplace(d.PRID, d.ReviewerID, d.NewReviewerID); err != nil {
+		return nil, err
+	}
+	r.store.replaceReviewer(d.PRID, d.ReviewerID, d.NewReviewerID)
+	d.ID = r.store.nextID()
+	r.store.declines[d.ID] = d
+
+	return &d, nil
+}
+
+// ListForPR returns declines of the PR, oldest first.
+func (r *DeclineRepository) ListForPR(_ context.Context, prID string) ([]entity.Decline, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	declines := make([]entity.Decline, 0)
+	for _, d := range r.store.declines {
+		if d.PRID == prID {
+			declines = append(declines, d)
+		}
+	}
+	slices.SortFunc(declines, func(a, b entity.Decline) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return declines, nil
+}
@@ -0,0 +1,25 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/repotest"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(*testing.T) repotest.Repositories {
+		store := memory.NewStore()
+		return repotest.Repositories{
+			PR:           memory.NewPRRepository(store),
+			Team:         memory.NewTeamRepository(store),
+			User:         memory.NewUserRepository(store),
+			Schedule:     memory.NewScheduleRepository(store),
+			StatusChange: memory.NewStatusChangeRepository(store),
+			SLA:          memory.NewSLARepository(store),
+			Audit:        memory.NewReviewerAuditRepository(store),
+			Decline:      memory.NewDeclineRepository(store),
+			Org:          memory.NewOrgRepository(store),
+		}
+	})
+}
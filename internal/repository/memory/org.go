@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type OrgRepository struct {
+	store *Store
+}
+
+func NewOrgRepository(store *Store) *OrgRepository {
+	return &OrgRepository{store: store}
+}
+
+// ListOrg returns teams ordered by name with members ordered by id.
+func (r *OrgRepository) ListOrg(_ context.Context) ([]entity.OrgTeam, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	teams := make([]entity.OrgTeam, 0, len(r.store.teams))
+	for name := range r.store.teams {
+		members := r.store.teamMembers(name)
+		slices.SortFunc(members, func(a, b entity.User) int { return strings.Compare(a.ID, b.ID) })
+		teams = append(teams, entity.OrgTeam{Name: name, Members: members})
+	}
+	slices.SortFunc(teams, func(a, b entity.OrgTeam) int { return strings.Compare(a.Name, b.Name) })
+
+	return teams, nil
+}
+
+// ApplyOrgPlan checks the plan before the first change, so a failure leaves
+// the store as it was. Existing users keep their creation time and review
+// capacity. A reassigned PR must still be open and reviewed by the old
+// reviewer.
+func (r *OrgRepository) ApplyOrgPlan(_ context.Context, plan entity.OrgPlan) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, name := range plan.AddTeams {
+		if _, ok := r.store.teams[name]; ok {
+			return apperror.ErrTeamExists
+		}
+	}
+
+	for _, re := range plan.Reassign {
+		if err := r.store.checkReplace(re.PRID, re.ReviewerID, re.ReplacedBy); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range plan.AddTeams {
+		r.store.teams[name] = &teamRow{team: *entity.NewTeam(name)}
+	}
+	for _, c := range plan.Users {
+		u := c.After
+		u.MaxOpenReviews = nil
+		if existing, ok := r.store.users[u.ID]; ok {
+			u.CreatedAt = existing.CreatedAt
+			u.MaxOpenReviews = existing.MaxOpenReviews
+		}
+		r.store.putUser(u)
+	}
+	for _, re := range plan.Reassign {
+		r.store.replaceReviewer(re.PRID, re.ReviewerID, re.ReplacedBy)
+	}
+	for _, name := range plan.RemoveTeams {
+		delete(r.store.teams, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// cursor has the same shape as the Postgres one: the value of the sort field
+// and the id of the last item of a page.
+type cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// sortKey describes keyset ordering of items by a field with an id tie-breaker.
+type sortKey[T any] struct {
+	desc bool
+	// value returns the sort field, either a string or a time.Time.
+	value func(T) any
+	// keyOf returns the cursor representation of the sort field and the id.
+	keyOf func(T) (string, string)
+	// parse converts the cursor value back to the field type.
+	parse func(string) (any, error)
+}
+
+func textKey[T any](desc bool, value func(T) string, id func(T) string) sortKey[T] {
+	return sortKey[T]{
+		desc:  desc,
+		value: func(item T) any { return value(item) },
+		keyOf: func(item T) (string, string) { return value(item), id(item) },
+		parse: func(s string) (any, error) { return s, nil },
+	}
+}
+
+func timeKey[T any](desc bool, value func(T) time.Time, id func(T) string) sortKey[T] {
+	return sortKey[T]{
+		desc:  desc,
+		value: func(item T) any { return value(item) },
+		keyOf: func(item T) (string, string) { return value(item).UTC().Format(time.RFC3339Nano), id(item) },
+		parse: func(s string) (any, error) { return time.Parse(time.RFC3339Nano, s) },
+	}
+}
+
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case time.Time:
+		bv, _ := b.(time.Time)
+		return av.Compare(bv)
+	case string:
+		bv, _ := b.(string)
+		return strings.Compare(av, bv)
+	default:
+		return 0
+	}
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	return min(limit, maxPageLimit)
+}
+
+// paginate sorts the items by the key and returns the page following the cursor.
+func paginate[T any](items []T, key sortKey[T], page entity.PageRequest) (entity.Page[T], error) {
+	// compareKey compares the item with the (value, id) position in page order.
+	compareKey := func(value any, id string, item T) int {
+		_, itemID := key.keyOf(item)
+		c := compareValues(key.value(item), value)
+		if c == 0 {
+			c = strings.Compare(itemID, id)
+		}
+		if key.desc {
+			c = -c
+		}
+		return c
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b T) int {
+		_, bID := key.keyOf(b)
+		return compareKey(key.value(b), bID, a)
+	})
+
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return entity.Page[T]{}, err
+		}
+		value, err := key.parse(c.Value)
+		if err != nil {
+			return entity.Page[T]{}, fmt.Errorf("%w: malformed cursor", apperror.ErrInvalidListing)
+		}
+		// skip items up to and including the cursor
+		start := 0
+		for start < len(sorted) && compareKey(value, c.ID, sorted[start]) <= 0 {
+			start++
+		}
+		sorted = sorted[start:]
+	}
+
+	limit := pageLimit(page.Limit)
+	if page.Limit == entity.NoPageLimit || len(sorted) <= limit {
+		return entity.Page[T]{Items: sorted}, nil
+	}
+
+	sorted = sorted[:limit]
+	value, id := key.keyOf(sorted[len(sorted)-1])
+	return entity.Page[T]{Items: sorted, NextCursor: encodeCursor(value, id)}, nil
+}
+
+func encodeCursor(value, id string) string {
+	raw, _ := json.Marshal(cursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", apperror.ErrInvalidListing)
+	}
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", apperror.ErrInvalidListing)
+	}
+	return c, nil
+}
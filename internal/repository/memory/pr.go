@@ -0,0 +1,334 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type PRRepository struct {
+	store *Store
+}
+
+func NewPRRepository(store *Store) *PRRepository {
+	return &PRRepository{store: store}
+}
+
+func (r *PRRepository) Create(_ context.Context, pr entity.PR) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.prs[pr.ID]; ok {
+		return apperror.ErrPRExists
+	}
+	if _, ok := r.store.users[pr.AuthorID]; !ok {
+		return apperror.ErrNotFound
+	}
+	r.store.prs[pr.ID] = pr
+
+	return nil
+}
+
+func (r *PRRepository) GetByID(_ context.Context, id string) (*entity.PR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	pr, ok := r.store.prs[id]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &pr, nil
+}
+
+// UpdateStatus sets merged_at when an open PR becomes merged, like the trigger
+// of the Postgres schema does.
+func (r *PRRepository) UpdateStatus(_ context.Context, id, status string) (*entity.PR, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	pr, ok := r.store.prs[id]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+	if status == entity.PRStatusMerged && pr.Status == entity.PRStatusOpen {
+		mergedAt := now()
+		pr.MergedAt = &mergedAt
+	}
+	pr.Status = status
+	r.store.prs[id] = pr
+
+	return &pr, nil
+}
+
+// AddReviewer assigns the user to the PR.
+func (r *PRRepository) AddReviewer(_ context.Context, prID, reviewerID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.addReviewer(prID, reviewerID)
+}
+
+// AddReviewerWithLimit assigns the user to the PR unless it already has
+// maxReviewers reviewers.
+func (r *PRRepository) AddReviewerWithLimit(_ context.Context, prID, reviewerID string, maxReviewers int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.prs[prID]; !ok {
+		return apperror.ErrNotFound
+	}
+	if len(r.store.reviewers[prID]) >= maxReviewers {
+		return apperror.ErrReviewersFull
+	}
+
+	return r.store.addReviewer(prID, reviewerID)
+}
+
+// addReviewer assigns the user to the PR. Must be called with the lock held.
+func (s *Store) addReviewer(prID, reviewerID string) error {
+	if _, ok := s.prs[prID]; !ok {
+		return apperror.ErrNotFound
+	}
+	if _, ok := s.users[reviewerID]; !ok {
+		return apperror.ErrNotFound
+	}
+	if s.isReviewer(prID, reviewerID) {
+		return apperror.ErrReviewerExists
+	}
+
+	s.reviewers[prID] = append(s.reviewers[prID], entity.PRReviewer{
+		PRID:       prID,
+		ReviewerID: reviewerID,
+		AssignedAt: now(),
+	})
+
+	return nil
+}
+
+func (r *PRRepository) RemoveReviewer(_ context.Context, prID, reviewerID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.reviewers[prID] = slices.DeleteFunc(r.store.reviewers[prID], func(rv entity.PRReviewer) bool {
+		return rv.ReviewerID == reviewerID
+	})
+
+	return nil
+}
+
+// MarkReviewerRemoved remembers that the reviewer was taken off the PR, so the
+// reviewer is not picked for it again.
+func (r *PRRepository) MarkReviewerRemoved(_ context.Context, prID, reviewerID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.prs[prID]; !ok {
+		return apperror.ErrNotFound
+	}
+	if _, ok := r.store.users[reviewerID]; !ok {
+		return apperror.ErrNotFound
+	}
+	if r.store.removedReviewers[prID] == nil {
+		r.store.removedReviewers[prID] = make(map[string]time.Time)
+	}
+	r.store.removedReviewers[prID][reviewerID] = now()
+
+	return nil
+}
+
+// ListRemovedReviewers returns ids of reviewers ever taken off the PR.
+func (r *PRRepository) ListRemovedReviewers(_ context.Context, prID string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	removedIDs := slices.Sorted(maps.Keys(r.store.removedReviewers[prID]))
+	if removedIDs == nil {
+		removedIDs = make([]string, 0)
+	}
+
+	return removedIDs, nil
+}
+
+func (r *PRRepository) DeleteByID(_ context.Context, prID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deletePR(prID)
+
+	return nil
+}
+
+func (r *PRRepository) GetAssignedReviewers(_ context.Context, prID string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	assignedIDs := make([]string, 0, len(r.store.reviewers[prID]))
+	for _, rv := range r.store.reviewers[prID] {
+		assignedIDs = append(assignedIDs, rv.ReviewerID)
+	}
+
+	return assignedIDs, nil
+}
+
+func (r *PRRepository) List(_ context.Context, opts entity.PRListOptions) (entity.Page[entity.PR], error) {
+	key, err := prSortKey(opts.Sort)
+	if err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prs := make([]entity.PR, 0)
+	for _, pr := range r.store.prs {
+		if r.store.matchPR(pr, opts.Filter) {
+			prs = append(prs, pr)
+		}
+	}
+
+	return paginate(prs, key, opts.Page)
+}
+
+func (r *PRRepository) GetReviewersForPRs(_ context.Context, prIDs []string) (map[string][]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reviewers := make(map[string][]string, len(prIDs))
+	for _, prID := range prIDs {
+		for _, rv := range r.store.reviewers[prID] {
+			reviewers[prID] = append(reviewers[prID], rv.ReviewerID)
+		}
+	}
+
+	return reviewers, nil
+}
+
+func (r *PRRepository) GetReviewerDetails(_ context.Context, prID string) ([]entity.AssignedReviewer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reviewers := make([]entity.AssignedReviewer, 0, len(r.store.reviewers[prID]))
+	for _, rv := range r.store.reviewers[prID] {
+		reviewers = append(reviewers, entity.AssignedReviewer{
+			User:       r.store.users[rv.ReviewerID],
+			AssignedAt: rv.AssignedAt,
+		})
+	}
+	slices.SortFunc(reviewers, func(a, b entity.AssignedReviewer) int {
+		if c := a.AssignedAt.Compare(b.AssignedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return reviewers, nil
+}
+
+// CountAssignments returns the number of assignments made to members of the team
+// since the given moment, keyed by reviewer id. Members without assignments are omitted.
+func (r *PRRepository) CountAssignments(_ context.Context, teamName string, since time.Time) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, reviewers := range r.store.reviewers {
+		for _, rv := range reviewers {
+			if r.store.users[rv.ReviewerID].TeamName == teamName && !rv.AssignedAt.Before(since) {
+				counts[rv.ReviewerID]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *PRRepository) CountOpenReviews(_ context.Context, teamName string) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for prID, reviewers := range r.store.reviewers {
+		if r.store.prs[prID].Status != entity.PRStatusOpen {
+			continue
+		}
+		for _, rv := range reviewers {
+			if r.store.users[rv.ReviewerID].TeamName == teamName {
+				counts[rv.ReviewerID]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func prSortKey(sort entity.Sort) (sortKey[entity.PR], error) {
+	id := func(pr entity.PR) string { return pr.ID }
+	switch sort.Field {
+	case "", entity.PRSortCreatedAt:
+		return timeKey(sort.Desc(), func(pr entity.PR) time.Time { return pr.CreatedAt }, id), nil
+	case entity.PRSortTitle:
+		return textKey(sort.Desc(), func(pr entity.PR) string { return pr.Title }, id), nil
+	default:
+		return sortKey[entity.PR]{}, fmt.Errorf("%w: unknown sort field %q", apperror.ErrInvalidListing, sort.Field)
+	}
+}
+
+// matchPR reports whether the PR satisfies the filter. Must be called with the lock held.
+func (s *Store) matchPR(pr entity.PR, filter entity.PRFilter) bool {
+	switch {
+	case filter.Status != "" && pr.Status != filter.Status,
+		filter.AuthorID != "" && pr.AuthorID != filter.AuthorID,
+		filter.CreatedFrom != nil && pr.CreatedAt.Before(*filter.CreatedFrom),
+		filter.CreatedTo != nil && !pr.CreatedAt.Before(*filter.CreatedTo),
+		filter.TeamName != "" && s.users[pr.AuthorID].TeamName != filter.TeamName,
+		filter.ReviewerID != "" && !s.isReviewer(pr.ID, filter.ReviewerID),
+		filter.TitleContains != "" &&
+			!strings.Contains(strings.ToLower(pr.Title), strings.ToLower(filter.TitleContains)),
+		filter.MergedFrom != nil && (pr.MergedAt == nil || pr.MergedAt.Before(*filter.MergedFrom)),
+		filter.MergedTo != nil && (pr.MergedAt == nil || !pr.MergedAt.Before(*filter.MergedTo)),
+		filter.FewerReviewersThan != nil && len(s.reviewers[pr.ID]) >= *filter.FewerReviewersThan:
+		return false
+	default:
+		return true
+	}
+}
+
+// checkReplace reports why the review of the PR can not be handed over from
+// oldID to newID. Must be called with the lock held.
+func (s *Store) checkReplace(prID, oldID, newID string) error {
+	if s.prs[prID].Status != entity.PRStatusOpen || !s.isReviewer(prID, oldID) {
+		return fmt.Errorf("review of %s by %s: %w", prID, oldID, apperror.ErrNotAssigned)
+	}
+	if s.isReviewer(prID, newID) {
+		return fmt.Errorf("review of %s by %s: %w", prID, newID, apperror.ErrReviewerExists)
+	}
+	if _, ok := s.users[newID]; !ok {
+		return apperror.ErrNotFound
+	}
+	return nil
+}
+
+// replaceReviewer hands the review of the PR over from oldID to newID and
+// remembers oldID as removed. Must be called with the lock held after
+// checkReplace.
+func (s *Store) replaceReviewer(prID, oldID, newID string) {
+	s.reviewers[prID] = slices.DeleteFunc(s.reviewers[prID], func(rv entity.PRReviewer) bool {
+		return rv.ReviewerID == oldID
+	})
+	s.reviewers[prID] = append(s.reviewers[prID], entity.PRReviewer{
+		PRID:       prID,
+		ReviewerID: newID,
+		AssignedAt: now(),
+	})
+	if s.removedReviewers[prID] == nil {
+		s.removedReviewers[prID] = make(map[string]time.Time)
+	}
+	s.removedReviewers[prID][oldID] = now()
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type ScheduleRepository struct {
+	store *Store
+}
+
+func NewScheduleRepository(store *Store) *ScheduleRepository {
+	return &ScheduleRepository{store: store}
+}
+
+func (r *ScheduleRepository) UpsertSchedule(_ context.Context, s entity.WorkSchedule) (*entity.WorkSchedule, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[s.UserID]; !ok {
+		return nil, apperror.ErrNotFound
+	}
+	r.store.schedules[s.UserID] = s
+
+	return &s, nil
+}
+
+func (r *ScheduleRepository) GetSchedule(_ context.Context, userID string) (*entity.WorkSchedule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	s, ok := r.store.schedules[userID]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &s, nil
+}
+
+func (r *ScheduleRepository) DeleteSchedule(_ context.Context, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.schedules[userID]; !ok {
+		return apperror.ErrNotFound
+	}
+	delete(r.store.schedules, userID)
+
+	return nil
+}
+
+func (r *ScheduleRepository) ListTeamSchedules(_ context.Context, teamName string) ([]entity.WorkSchedule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	schedules := make([]entity.WorkSchedule, 0)
+	for userID, s := range r.store.schedules {
+		if r.store.users[userID].TeamName == teamName {
+			schedules = append(schedules, s)
+		}
+	}
+
+	return schedules, nil
+}
+
+func (r *ScheduleRepository) AddOutOfOffice(_ context.Context, o entity.OutOfOffice) (*entity.OutOfOffice, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[o.UserID]; !ok {
+		return nil, apperror.ErrNotFound
+	}
+	o.ID = r.store.nextID()
+	r.store.outOfOffice[o.ID] = o
+
+	return &o, nil
+}
+
+func (r *ScheduleRepository) DeleteOutOfOffice(_ context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.outOfOffice[id]; !ok {
+		return apperror.ErrNotFound
+	}
+	delete(r.store.outOfOffice, id)
+
+	return nil
+}
+
+// ListOutOfOffice returns periods of the user that have not ended by now.
+func (r *ScheduleRepository) ListOutOfOffice(
+	_ context.Context,
+	userID string,
+	now time.Time,
+) ([]entity.OutOfOffice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	periods := make([]entity.OutOfOffice, 0)
+	for _, o := range r.store.outOfOffice {
+		if o.UserID == userID && o.EndsAt.After(now) {
+			periods = append(periods, o)
+		}
+	}
+	slices.SortFunc(periods, func(a, b entity.OutOfOffice) int { return a.StartsAt.Compare(b.StartsAt) })
+
+	return periods, nil
+}
+
+// ListTeamOutOfOffice returns periods of the team members that are in effect at the given moment.
+func (r *ScheduleRepository) ListTeamOutOfOffice(
+	_ context.Context,
+	teamName string,
+	at time.Time,
+) ([]entity.OutOfOffice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	periods := make([]entity.OutOfOffice, 0)
+	for _, o := range r.store.outOfOffice {
+		if r.store.users[o.UserID].TeamName == teamName && o.CoversAt(at) {
+			periods = append(periods, o)
+		}
+	}
+
+	return periods, nil
+}
+
+// ListTeamOutOfOfficeBetween returns periods of the team members that overlap [from, to).
+func (r *ScheduleRepository) ListTeamOutOfOfficeBetween(
+	_ context.Context,
+	teamName string,
+	from, to time.Time,
+) ([]entity.OutOfOffice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	periods := make([]entity.OutOfOffice, 0)
+	for _, o := range r.store.outOfOffice {
+		if r.store.users[o.UserID].TeamName == teamName && o.StartsAt.Before(to) && o.EndsAt.After(from) {
+			periods = append(periods, o)
+		}
+	}
+	slices.SortFunc(periods, func(a, b entity.OutOfOffice) int {
+		if c := a.StartsAt.Compare(b.StartsAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return periods, nil
+}
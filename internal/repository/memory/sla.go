@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type SLARepository struct {
+	store *Store
+}
+
+func NewSLARepository(store *Store) *SLARepository {
+	return &SLARepository{store: store}
+}
+
+// SetTeamSLA sets the review SLA of the team, nil removes it.
+func (r *SLARepository) SetTeamSLA(_ context.Context, teamName string, sla *time.Duration) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.teams[teamName]
+	if !ok {
+		return apperror.ErrNotFound
+	}
+	if sla != nil {
+		// stored with second precision like review_sla_seconds
+		seconds := time.Duration(sla.Seconds()) * time.Second
+		sla = &seconds
+	}
+	row.sla = sla
+
+	return nil
+}
+
+func (r *SLARepository) GetTeamSLA(_ context.Context, teamName string) (*time.Duration, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.teams[teamName]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+	if row.sla == nil {
+		return nil, nil //nolint:nilnil // no SLA configured is not an error
+	}
+
+	sla := *row.sla
+	return &sla, nil
+}
+
+// ListOverdueAssignments returns assignments on open PRs that exceeded the SLA of
+// the reviewer's team at the given moment and were not escalated yet.
+func (r *SLARepository) ListOverdueAssignments(_ context.Context, now time.Time) ([]entity.OverdueAssignment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	overdue := make([]entity.OverdueAssignment, 0)
+	for prID, reviewers := range r.store.reviewers {
+		if r.store.prs[prID].Status != entity.PRStatusOpen {
+			continue
+		}
+		for _, rv := range reviewers {
+			teamName := r.store.users[rv.ReviewerID].TeamName
+			sla := r.store.teamSLA(teamName)
+			if sla == nil || rv.AssignedAt.Add(*sla).After(now) || r.store.isEscalated(rv) {
+				continue
+			}
+			overdue = append(overdue, entity.OverdueAssignment{
+				PRID:       prID,
+				ReviewerID: rv.ReviewerID,
+				TeamName:   teamName,
+				AssignedAt: rv.AssignedAt,
+				SLA:        *sla,
+			})
+		}
+	}
+	slices.SortFunc(overdue, func(a, b entity.OverdueAssignment) int { return a.AssignedAt.Compare(b.AssignedAt) })
+
+	return overdue, nil
+}
+
+// RecordEscalation stores the escalation and returns false when the same
+// assignment was already escalated.
+func (r *SLARepository) RecordEscalation(_ context.Context, e entity.Escalation) (*entity.Escalation, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.prs[e.PRID]; !ok {
+		return nil, false, apperror.ErrNotFound
+	}
+	if r.store.isEscalated(entity.PRReviewer{PRID: e.PRID, ReviewerID: e.ReviewerID, AssignedAt: e.AssignedAt}) {
+		return nil, false, nil
+	}
+	e.ID = r.store.nextID()
+	r.store.escalations[e.ID] = e
+
+	return &e, true, nil
+}
+
+func (r *SLARepository) UpdateEscalationAction(_ context.Context, id int64, action, newReviewerID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	e, ok := r.store.escalations[id]
+	if !ok {
+		return nil
+	}
+	e.Action = action
+	e.NewReviewerID = &newReviewerID
+	r.store.escalations[id] = e
+
+	return nil
+}
+
+// ListOpenEscalations returns escalations of PRs that are still open.
+func (r *SLARepository) ListOpenEscalations(_ context.Context) ([]entity.Escalation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	escalations := make([]entity.Escalation, 0)
+	for _, e := range r.store.escalations {
+		if r.store.prs[e.PRID].Status == entity.PRStatusOpen {
+			escalations = append(escalations, e)
+		}
+	}
+	slices.SortFunc(escalations, func(a, b entity.Escalation) int {
+		if c := a.DueAt.Compare(b.DueAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return escalations, nil
+}
+
+// GetReviewStats aggregates timings of PRs authored by members of the team.
+func (r *SLARepository) GetReviewStats(
+	_ context.Context,
+	teamName string,
+	now time.Time,
+) (*entity.ReviewStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.teams[teamName]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+
+	stats := entity.ReviewStats{TeamName: teamName}
+	if row.sla != nil {
+		sla := *row.sla
+		stats.SLA = &sla
+	}
+
+	var mergeTotal time.Duration
+	for _, pr := range r.store.prs {
+		if r.store.users[pr.AuthorID].TeamName != teamName {
+			continue
+		}
+		stats.PRCount++
+		if pr.MergedAt != nil {
+			stats.MergedCount++
+			mergeTotal += pr.MergedAt.Sub(pr.CreatedAt)
+		}
+	}
+	if stats.MergedCount > 0 {
+		stats.AvgTimeToMerge = mergeTotal / time.Duration(stats.MergedCount)
+	}
+
+	for prID, reviewers := range r.store.reviewers {
+		if r.store.prs[prID].Status != entity.PRStatusOpen {
+			continue
+		}
+		for _, rv := range reviewers {
+			if r.store.users[rv.ReviewerID].TeamName != teamName {
+				continue
+			}
+			stats.OpenAssignments++
+			if row.sla != nil && !rv.AssignedAt.Add(*row.sla).After(now) {
+				stats.OverdueAssignments++
+			}
+		}
+	}
+
+	stats.Declines = make(map[string]int)
+	for _, d := range r.store.declines {
+		if r.store.users[d.ReviewerID].TeamName == teamName {
+			stats.Declines[d.Reason]++
+		}
+	}
+
+	return &stats, nil
+}
+
+// teamSLA returns the SLA of the team or nil. Must be called with the lock held.
+func (s *Store) teamSLA(teamName string) *time.Duration {
+	if row, ok := s.teams[teamName]; ok {
+		return row.sla
+	}
+	return nil
+}
+
+// isEscalated reports whether the assignment was already escalated. Must be called with the lock held.
+func (s *Store) isEscalated(rv entity.PRReviewer) bool {
+	for _, e := range s.escalations {
+		if e.PRID == rv.PRID && e.ReviewerID == rv.ReviewerID && e.AssignedAt.Equal(rv.AssignedAt) {
+			return true
+		}
+	}
+	return false
+}
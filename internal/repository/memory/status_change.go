@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type StatusChangeRepository struct {
+	store *Store
+}
+
+func NewStatusChangeRepository(store *Store) *StatusChangeRepository {
+	return &StatusChangeRepository{store: store}
+}
+
+func (r *StatusChangeRepository) Create(_ context.Context, c entity.StatusChange) (*entity.StatusChange, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[c.UserID]; !ok {
+		return nil, apperror.ErrNotFound
+	}
+	c.ID = r.store.nextID()
+	c.AppliedAt = nil
+	r.store.statusChanges[c.ID] = c
+
+	return &c, nil
+}
+
+// ListPending returns not yet applied changes of the user ordered by apply time.
+func (r *StatusChangeRepository) ListPending(_ context.Context, userID string) ([]entity.StatusChange, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	changes := make([]entity.StatusChange, 0)
+	for _, c := range r.store.statusChanges {
+		if c.UserID == userID && c.AppliedAt == nil {
+			changes = append(changes, c)
+		}
+	}
+	slices.SortFunc(changes, compareStatusChanges)
+
+	return changes, nil
+}
+
+// Cancel deletes a pending change. Already applied changes and changes being
+// applied right now are reported as not found.
+func (r *StatusChangeRepository) Cancel(_ context.Context, id int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c, ok := r.store.statusChanges[id]
+	_, claimed := r.store.claimedChanges[id]
+	if !ok || claimed || c.AppliedAt != nil {
+		return apperror.ErrNotFound
+	}
+	delete(r.store.statusChanges, id)
+
+	return nil
+}
+
+// ApplyDue claims up to limit changes due at now and passes each of them to apply.
+// The lock is released while apply runs, since it usually goes through other
+// repositories of the same store; claimed changes are skipped by concurrent
+// calls. A change is marked as applied only when apply succeeds.
+func (r *StatusChangeRepository) ApplyDue(
+	ctx context.Context,
+	now time.Time,
+	limit uint64,
+	apply func(ctx context.Context, c entity.StatusChange) error,
+) (int, error) {
+	r.store.mu.Lock()
+	due := make([]entity.StatusChange, 0)
+	for _, c := range r.store.statusChanges {
+		if _, claimed := r.store.claimedChanges[c.ID]; claimed || c.AppliedAt != nil || c.ApplyAt.After(now) {
+			continue
+		}
+		due = append(due, c)
+	}
+	slices.SortFunc(due, compareStatusChanges)
+	if uint64(len(due)) > limit {
+		due = due[:limit]
+	}
+	for _, c := range due {
+		r.store.claimedChanges[c.ID] = struct{}{}
+	}
+	r.store.mu.Unlock()
+
+	applied := make([]int64, 0, len(due))
+	var applyErr error
+	for _, c := range due {
+		if err := apply(ctx, c); err != nil {
+			applyErr = errors.Join(applyErr, fmt.Errorf("status change %d: %w", c.ID, err))
+			continue
+		}
+		applied = append(applied, c.ID)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, c := range due {
+		delete(r.store.claimedChanges, c.ID)
+	}
+	for _, id := range applied {
+		if c, ok := r.store.statusChanges[id]; ok {
+			appliedAt := now
+			c.AppliedAt = &appliedAt
+			r.store.statusChanges[id] = c
+		}
+	}
+
+	return len(applied), applyErr
+}
+
+func compareStatusChanges(a, b entity.StatusChange) int {
+	if c := a.ApplyAt.Compare(b.ApplyAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
@@ -0,0 +1,116 @@
+// Package memory implements the repositories on top of in-process maps. It is
+// meant for tests and local runs without a database: data lives as long as the
+// process does.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type teamRow struct {
+	team entity.Team
+	sla  *time.Duration
+}
+
+// Store holds the data shared by all memory repositories. A single lock guards
+// every table, which keeps multi-table operations consistent the same way a
+// transaction does in Postgres.
+type Store struct {
+	mu sync.RWMutex
+
+	teams     map[string]*teamRow
+	users     map[string]entity.User
+	prs       map[string]entity.PR
+	reviewers map[string][]entity.PRReviewer
+	// removedReviewers maps a PR to reviewers taken off it and when.
+	removedReviewers map[string]map[string]time.Time
+
+	schedules   map[string]entity.WorkSchedule
+	outOfOffice map[int64]entity.OutOfOffice
+
+	statusChanges map[int64]entity.StatusChange
+	statusHistory []entity.StatusHistoryEntry
+	// claimedChanges are being applied by ApplyDue, like rows locked with SKIP LOCKED.
+	claimedChanges map[int64]struct{}
+
+	escalations   map[int64]entity.Escalation
+	reviewerAudit map[int64]entity.ReviewerAuditEntry
+	declines      map[int64]entity.Decline
+
+	lastID int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		teams:            make(map[string]*teamRow),
+		users:            make(map[string]entity.User),
+		prs:              make(map[string]entity.PR),
+		reviewers:        make(map[string][]entity.PRReviewer),
+		removedReviewers: make(map[string]map[string]time.Time),
+		schedules:        make(map[string]entity.WorkSchedule),
+		outOfOffice:      make(map[int64]entity.OutOfOffice),
+		statusChanges:    make(map[int64]entity.StatusChange),
+		claimedChanges:   make(map[int64]struct{}),
+		escalations:      make(map[int64]entity.Escalation),
+		reviewerAudit:    make(map[int64]entity.ReviewerAuditEntry),
+		declines:         make(map[int64]entity.Decline),
+	}
+}
+
+// nextID returns a new identifier for serial tables. Must be called with the lock held.
+func (s *Store) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+// putUser stores the user and records a change of is_active of an existing
+// user, like the trigger on users does. Must be called with the lock held.
+func (s *Store) putUser(u entity.User) {
+	if existing, ok := s.users[u.ID]; ok && existing.IsActive != u.IsActive {
+		s.statusHistory = append(s.statusHistory, entity.StatusHistoryEntry{
+			UserID:    u.ID,
+			IsActive:  u.IsActive,
+			ChangedAt: time.Now().UTC(),
+		})
+	}
+	s.users[u.ID] = u
+}
+
+// deletePR removes the PR with everything referencing it. Must be called with the lock held.
+func (s *Store) deletePR(prID string) {
+	delete(s.prs, prID)
+	delete(s.reviewers, prID)
+	delete(s.removedReviewers, prID)
+	for id, e := range s.escalations {
+		if e.PRID == prID {
+			delete(s.escalations, id)
+		}
+	}
+	for id, e := range s.reviewerAudit {
+		if e.PRID == prID {
+			delete(s.reviewerAudit, id)
+		}
+	}
+	for id, d := range s.declines {
+		if d.PRID == prID {
+			delete(s.declines, id)
+		}
+	}
+}
+
+// isReviewer reports whether the user is assigned to the PR. Must be called with the lock held.
+func (s *Store) isReviewer(prID, userID string) bool {
+	for _, r := range s.reviewers[prID] {
+		if r.ReviewerID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type TeamRepository struct {
+	store *Store
+}
+
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{store: store}
+}
+
+// CreateTeam creates the team and moves existing users into it, updating their
+// names and activity like the upsert of the Postgres implementation. Review
+// capacities are not taken from the input and existing ones are kept.
+func (r *TeamRepository) CreateTeam(_ context.Context, team entity.Team, members []entity.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.teams[team.Name]; ok {
+		return apperror.ErrTeamExists
+	}
+	team.MaxOpenReviews = nil
+	r.store.teams[team.Name] = &teamRow{team: team}
+
+	for _, m := range members {
+		m.TeamName = team.Name
+		m.MaxOpenReviews = nil
+		if existing, ok := r.store.users[m.ID]; ok {
+			m.CreatedAt = existing.CreatedAt
+			m.MaxOpenReviews = existing.MaxOpenReviews
+		}
+		r.store.putUser(m)
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) GetTeam(_ context.Context, name string) (*entity.Team, []entity.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.teams[name]
+	if !ok {
+		return nil, nil, apperror.ErrNotFound
+	}
+
+	users := r.store.teamMembers(name)
+	slices.SortFunc(users, func(a, b entity.User) int { return strings.Compare(a.ID, b.ID) })
+
+	team := row.team
+	return &team, users, nil
+}
+
+func (r *TeamRepository) ListMembers(
+	_ context.Context,
+	name string,
+	sort entity.Sort,
+	page entity.PageRequest,
+) (*entity.Team, entity.Page[entity.User], error) {
+	id := func(u entity.User) string { return u.ID }
+	var key sortKey[entity.User]
+	switch sort.Field {
+	case "", entity.UserSortID:
+		key = textKey(sort.Desc(), id, id)
+	case entity.UserSortName:
+		key = textKey(sort.Desc(), func(u entity.User) string { return u.Name }, id)
+	default:
+		return nil, entity.Page[entity.User]{}, fmt.Errorf(
+			"%w: unknown sort field %q", apperror.ErrInvalidListing, sort.Field)
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.teams[name]
+	if !ok {
+		return nil, entity.Page[entity.User]{}, apperror.ErrNotFound
+	}
+
+	users, err := paginate(r.store.teamMembers(name), key, page)
+	if err != nil {
+		return nil, entity.Page[entity.User]{}, err
+	}
+
+	team := row.team
+	return &team, users, nil
+}
+
+func (r *TeamRepository) SetMaxOpenReviews(_ context.Context, teamName string, limit *int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.teams[teamName]
+	if !ok {
+		return apperror.ErrNotFound
+	}
+	row.team.MaxOpenReviews = limit
+
+	return nil
+}
+
+// ListLoad returns the review load of every team ordered by name.
+func (r *TeamRepository) ListLoad(_ context.Context) ([]entity.TeamLoad, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byTeam := make(map[string]*entity.TeamLoad, len(r.store.teams))
+	for name := range r.store.teams {
+		byTeam[name] = &entity.TeamLoad{TeamName: name}
+	}
+	for _, pr := range r.store.prs {
+		if l, ok := byTeam[r.store.users[pr.AuthorID].TeamName]; ok && pr.Status == entity.PRStatusOpen {
+			l.OpenPRs++
+		}
+	}
+	busy := make(map[string]bool)
+	for prID, reviewers := range r.store.reviewers {
+		if r.store.prs[prID].Status != entity.PRStatusOpen {
+			continue
+		}
+		for _, rv := range reviewers {
+			busy[rv.ReviewerID] = true
+		}
+	}
+	for _, u := range r.store.users {
+		if l, ok := byTeam[u.TeamName]; ok && u.IsActive && !busy[u.ID] {
+			l.IdleReviewers++
+		}
+	}
+
+	loads := make([]entity.TeamLoad, 0, len(byTeam))
+	for _, l := range byTeam {
+		loads = append(loads, *l)
+	}
+	slices.SortFunc(loads, func(a, b entity.TeamLoad) int { return strings.Compare(a.TeamName, b.TeamName) })
+
+	return loads, nil
+}
+
+func (r *TeamRepository) GetTeamForUser(_ context.Context, userID string) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u, ok := r.store.users[userID]
+	if !ok {
+		return "", apperror.ErrNotFound
+	}
+
+	return u.TeamName, nil
+}
+
+// teamMembers returns users of the team in no particular order. Must be called with the lock held.
+func (s *Store) teamMembers(teamName string) []entity.User {
+	users := make([]entity.User, 0)
+	for _, u := range s.users {
+		if u.TeamName == teamName {
+			users = append(users, u)
+		}
+	}
+	return users
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) SetIsActive(_ context.Context, userID string, isActive bool) (*entity.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[userID]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+	u.IsActive = isActive
+	r.store.putUser(u)
+
+	return &u, nil
+}
+
+func (r *UserRepository) SetMaxOpenReviews(_ context.Context, userID string, limit *int) (*entity.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[userID]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+	u.MaxOpenReviews = limit
+	r.store.users[userID] = u
+
+	return &u, nil
+}
+
+func (r *UserRepository) ListAssignedTo(
+	_ context.Context,
+	userID string,
+	opts entity.PRListOptions,
+) (entity.Page[entity.PR], error) {
+	key, err := prSortKey(opts.Sort)
+	if err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prs := make([]entity.PR, 0)
+	for _, pr := range r.store.prs {
+		if r.store.isReviewer(pr.ID, userID) && r.store.matchPR(pr, opts.Filter) {
+			prs = append(prs, pr)
+		}
+	}
+
+	return paginate(prs, key, opts.Page)
+}
+
+func (r *UserRepository) IsAssignedToPR(_ context.Context, userID, prID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.isReviewer(prID, userID), nil
+}
+
+func (r *UserRepository) GetByID(_ context.Context, userID string) (*entity.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u, ok := r.store.users[userID]
+	if !ok {
+		return nil, apperror.ErrNotFound
+	}
+
+	return &u, nil
+}
+
+// ListTeamStatusHistory returns changes of is_active of the team members made
+// in [from, to), oldest first.
+func (r *UserRepository) ListTeamStatusHistory(
+	_ context.Context,
+	teamName string,
+	from, to time.Time,
+) ([]entity.StatusHistoryEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	history := make([]entity.StatusHistoryEntry, 0)
+	for _, h := range r.store.statusHistory {
+		if r.store.users[h.UserID].TeamName == teamName && !h.ChangedAt.Before(from) && h.ChangedAt.Before(to) {
+			history = append(history, h)
+		}
+	}
+
+	return history, nil
+}
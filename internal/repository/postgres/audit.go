@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type ReviewerAuditRepository struct {
+	pool *pgxpool.Pool
+	sb   sq.StatementBuilderType
+}
+
+func NewReviewerAuditRepository(pool *pgxpool.Pool) *ReviewerAuditRepository {
+	return &ReviewerAuditRepository{pool: pool, sb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar)}
+}
+
+func (r *ReviewerAuditRepository) Record(
+	ctx context.Context,
+	e entity.ReviewerAuditEntry,
+) (*entity.ReviewerAuditEntry, error) {
+	query := r.sb.
+		Insert("reviewer_audit").
+		Columns("pr_id", "reviewer_id", "action", "actor", "created_at").
+		Values(e.PRID, e.ReviewerID, e.Action, e.Actor, e.CreatedAt).
+		Suffix("RETURNING id")
+
+	row := tryQueryRow(ctx, "ReviewerAuditRepository.Record", query, r.pool)
+
+	if err := row.Scan(&e.ID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ReviewerAuditRepository.Record failed to insert audit entry: %w", err)
+	}
+
+	return &e, nil
+}
+
+// ListForPR returns audit entries of the PR, oldest first.
+func (r *ReviewerAuditRepository) ListForPR(ctx context.Context, prID string) ([]entity.ReviewerAuditEntry, error) {
+	query := r.sb.
+		Select("id", "pr_id", "reviewer_id", "action", "actor", "created_at").
+		From("reviewer_audit").
+		Where(sq.Eq{"pr_id": prID}).
+		OrderBy("created_at", "id")
+
+	rows, err := tryQuery(ctx, "ReviewerAuditRepository.ListForPR", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("ReviewerAuditRepository.ListForPR failed to select audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]entity.ReviewerAuditEntry, 0)
+	for rows.Next() {
+		var e entity.ReviewerAuditEntry
+		if err = rows.Scan(&e.ID, &e.PRID, &e.ReviewerID, &e.Action, &e.Actor, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ReviewerAuditRepository.ListForPR failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ReviewerAuditRepository.ListForPR failed to select audit entries: %w", err)
+	}
+
+	return entries, nil
+}
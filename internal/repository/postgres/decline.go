@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type DeclineRepository struct {
+	pool *pgxpool.Pool
+	sb   sq.StatementBuilderType
+}
+
+func NewDeclineRepository(pool *pgxpool.Pool) *DeclineRepository {
+	return &DeclineRepository{pool: pool, sb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar)}
+}
+
+// Record hands the declined review over to d.NewReviewerID and stores the
+// decline in one transaction. The PR must be open and reviewed by d.ReviewerID.
+func (r *DeclineRepository) Record(ctx context.Context, d entity.Decline) (*entity.Decline, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DeclineRepository.Record failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err = replaceReviewer(ctx, r.sb, "DeclineRepository.Record", tx, d.PRID, d.ReviewerID,
+		d.NewReviewerID); err != nil {
+		return nil, err
+	}
+
+	query := r.sb.
+		Insert("review_declines").
+		Columns("pr_id", "reviewer_id", "reason", "comment", "new_reviewer_id", "created_at").
+		Values(d.PRID, d.ReviewerID, d.Reason, d.Comment, d.NewReviewerID, d.CreatedAt).
+		Suffix("RETURNING id")
+
+	row := tryQueryRow(ctx, "DeclineRepository.Record", query, tx)
+
+	if err = row.Scan(&d.ID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("DeclineRepository.Record failed to insert decline: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("DeclineRepository.Record failed to commit: %w", err)
+	}
+
+	return &d, nil
+}
+
+// ListForPR returns declines of the PR, oldest first.
+func (r *DeclineRepository) ListForPR(ctx context.Context, prID string) ([]entity.Decline, error) {
+	query := r.sb.
+		Select("id", "pr_id", "reviewer_id", "reason", "comment", "new_reviewer_id", "created_at").
+		From("review_declines").
+		Where(sq.Eq{"pr_id": prID}).
+		OrderBy("created_at", "id")
+
+	rows, err := tryQuery(ctx, "DeclineRepository.ListForPR", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("DeclineRepository.ListForPR failed to select declines: %w", err)
+	}
+	defer rows.Close()
+
+	declines := make([]entity.Decline, 0)
+	for rows.Next() {
+		var d entity.Decline
+		if err = rows.Scan(&d.ID, &d.PRID, &d.ReviewerID, &d.Reason, &d.Comment, &d.NewReviewerID,
+			&d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("DeclineRepository.ListForPR failed to scan decline: %w", err)
+		}
+		declines = append(declines, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("DeclineRepository.ListForPR failed to select declines: %w", err)
+	}
+
+	return declines, nil
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type OrgRepository struct {
+	pool *pgxpool.Pool
+	sb   sq.StatementBuilderType
+}
+
+func NewOrgRepository(pool *pgxpool.Pool) *OrgRepository {
+	return &OrgRepository{pool: pool, sb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar)}
+}
+
+// ListOrg returns teams ordered by name with members ordered by id. Both are
+// read in one repeatable read transaction, so they come from the same snapshot.
+func (r *OrgRepository) ListOrg(ctx context.Context) ([]entity.OrgTeam, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := r.sb.
+		Select("name").
+		From("teams").
+		OrderBy("name")
+
+	rows, err := tryQuery(ctx, "OrgRepository.ListOrg", query, tx)
+	if err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to select teams: %w", err)
+	}
+	defer rows.Close()
+
+	teams := make([]entity.OrgTeam, 0)
+	index := make(map[string]int)
+	for rows.Next() {
+		var t entity.OrgTeam
+		if err = rows.Scan(&t.Name); err != nil {
+			return nil, fmt.Errorf("OrgRepository.ListOrg failed to scan team: %w", err)
+		}
+		index[t.Name] = len(teams)
+		teams = append(teams, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to select teams: %w", err)
+	}
+	rows.Close()
+
+	queryUsers := r.sb.
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
+		From("users").
+		OrderBy("id")
+
+	userRows, err := tryQuery(ctx, "OrgRepository.ListOrg", queryUsers, tx)
+	if err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to select users: %w", err)
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var u entity.User
+		if err = userRows.Scan(&u.ID, &u.TeamName, &u.Name, &u.IsActive, &u.CreatedAt, &u.MaxOpenReviews); err != nil {
+			return nil, fmt.Errorf("OrgRepository.ListOrg failed to scan user: %w", err)
+		}
+		// the foreign key keeps users in existing teams, a user without one is skipped
+		if i, ok := index[u.TeamName]; ok {
+			teams[i].Members = append(teams[i].Members, u)
+		}
+	}
+	if err = userRows.Err(); err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to select users: %w", err)
+	}
+
+	return teams, nil
+}
+
+func (r *OrgRepository) ApplyOrgPlan(ctx context.Context, plan entity.OrgPlan) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if len(plan.AddTeams) > 0 {
+		query := r.sb.
+			Insert("teams").
+			Columns("name", "created_at")
+		now := time.Now().UTC()
+		for _, name := range plan.AddTeams {
+			query = query.Values(name, now)
+		}
+
+		if err = tryExec(ctx, "OrgRepository.ApplyOrgPlan", query, tx); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return apperror.ErrTeamExists
+			}
+			return fmt.Errorf("OrgRepository.ApplyOrgPlan failed to insert teams: %w", err)
+		}
+	}
+
+	if len(plan.Users) > 0 {
+		query := r.sb.
+			Insert("users").
+			Columns("id", "team_name", "name", "is_active", "created_at")
+		for _, c := range plan.Users {
+			u := c.After
+			query = query.Values(u.ID, u.TeamName, u.Name, u.IsActive, u.CreatedAt)
+		}
+		query = query.Suffix("ON CONFLICT (id) DO UPDATE SET " +
+			"team_name = EXCLUDED.team_name, name = EXCLUDED.name, is_active = EXCLUDED.is_active")
+
+		if err = tryExec(ctx, "OrgRepository.ApplyOrgPlan", query, tx); err != nil {
+			return fmt.Errorf("OrgRepository.ApplyOrgPlan failed to insert or update users: %w", err)
+		}
+	}
+
+	for _, re := range plan.Reassign {
+		if err = replaceReviewer(ctx, r.sb, "OrgRepository.ApplyOrgPlan", tx, re.PRID, re.ReviewerID,
+			re.ReplacedBy); err != nil {
+			return err
+		}
+	}
+
+	if len(plan.RemoveTeams) > 0 {
+		query := r.sb.
+			Delete("teams").
+			Where(sq.Eq{"name": plan.RemoveTeams})
+
+		if err = tryExec(ctx, "OrgRepository.ApplyOrgPlan", query, tx); err != nil {
+			return fmt.Errorf("OrgRepository.ApplyOrgPlan failed to delete teams: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
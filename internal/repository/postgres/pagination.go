@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// cursor points at the last row of a page: the value of the sort column and the
+// unique tie-breaker id.
+type cursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// sortKey describes keyset ordering over a sort column with an id tie-breaker.
+type sortKey struct {
+	column   string
+	idColumn string
+	desc     bool
+	// parse converts the cursor value back to the column type.
+	parse func(string) (any, error)
+}
+
+func textKey(column, idColumn string, desc bool) sortKey {
+	return sortKey{
+		column:   column,
+		idColumn: idColumn,
+		desc:     desc,
+		parse:    func(s string) (any, error) { return s, nil },
+	}
+}
+
+func timeKey(column, idColumn string, desc bool) sortKey {
+	return sortKey{
+		column:   column,
+		idColumn: idColumn,
+		desc:     desc,
+		parse: func(s string) (any, error) {
+			return time.Parse(time.RFC3339Nano, s)
+		},
+	}
+}
+
+func formatTimeKey(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func encodeCursor(value, id string) string {
+	raw, _ := json.Marshal(cursor{Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", apperror.ErrInvalidListing)
+	}
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", apperror.ErrInvalidListing)
+	}
+	return c, nil
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	return min(limit, maxPageLimit)
+}
+
+// paginate orders the query by the key, skips rows up to and including the cursor
+// and fetches one row more than the page size so trimPage can tell whether a
+// next page exists. NoPageLimit fetches all the following rows.
+func paginate(query sq.SelectBuilder, key sortKey, page entity.PageRequest) (sq.SelectBuilder, error) {
+	order, cmp := "ASC", ">"
+	if key.desc {
+		order, cmp = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return query, err
+		}
+		value, err := key.parse(c.Value)
+		if err != nil {
+			return query, fmt.Errorf("%w: malformed cursor", apperror.ErrInvalidListing)
+		}
+		query = query.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", key.column, key.idColumn, cmp), value, c.ID)
+	}
+
+	query = query.OrderBy(key.column+" "+order, key.idColumn+" "+order)
+	if page.Limit == entity.NoPageLimit {
+		return query, nil
+	}
+	return query.Limit(uint64(pageLimit(page.Limit)) + 1), nil
+}
+
+// trimPage drops the look-ahead row added by paginate and builds the cursor of
+// the next page from the last returned item.
+func trimPage[T any](items []T, limit int, keyOf func(T) (string, string)) entity.Page[T] {
+	if limit == entity.NoPageLimit {
+		return entity.Page[T]{Items: items}
+	}
+	limit = pageLimit(limit)
+	if len(items) <= limit {
+		return entity.Page[T]{Items: items}
+	}
+
+	items = items[:limit]
+	value, id := keyOf(items[len(items)-1])
+	return entity.Page[T]{Items: items, NextCursor: encodeCursor(value, id)}
+}
+
+// prSortKey maps the requested PR sort onto columns of the prs table aliased as alias.
+func prSortKey(alias string, sort entity.Sort) (sortKey, func(entity.PR) (string, string), error) {
+	idColumn := alias + ".id"
+	switch sort.Field {
+	case "", entity.PRSortCreatedAt:
+		return timeKey(alias+".created_at", idColumn, sort.Desc()),
+			func(pr entity.PR) (string, string) { return formatTimeKey(pr.CreatedAt), pr.ID },
+			nil
+	case entity.PRSortTitle:
+		return textKey(alias+".title", idColumn, sort.Desc()),
+			func(pr entity.PR) (string, string) { return pr.Title, pr.ID },
+			nil
+	default:
+		return sortKey{}, nil, fmt.Errorf("%w: unknown sort field %q", apperror.ErrInvalidListing, sort.Field)
+	}
+}
+
+// applyPRFilter restricts a query over the prs table aliased as alias.
+func applyPRFilter(query sq.SelectBuilder, alias string, filter entity.PRFilter) sq.SelectBuilder {
+	if filter.Status != "" {
+		query = query.Where(sq.Eq{alias + ".status": filter.Status})
+	}
+	if filter.AuthorID != "" {
+		query = query.Where(sq.Eq{alias + ".author_id": filter.AuthorID})
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where(sq.GtOrEq{alias + ".created_at": *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where(sq.Lt{alias + ".created_at": *filter.CreatedTo})
+	}
+	if filter.TeamName != "" {
+		query = query.Where(alias+".author_id IN (SELECT id FROM users WHERE team_name = ?)", filter.TeamName)
+	}
+	if filter.ReviewerID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM pr_reviewers fr WHERE fr.pr_id = "+alias+".id "+
+			"AND fr.reviewer_id = ?)", filter.ReviewerID)
+	}
+	if filter.TitleContains != "" {
+		query = query.Where(sq.ILike{alias + ".title": "%" + escapeLike(filter.TitleContains) + "%"})
+	}
+	if filter.MergedFrom != nil {
+		query = query.Where(sq.GtOrEq{alias + ".merged_at": *filter.MergedFrom})
+	}
+	if filter.MergedTo != nil {
+		query = query.Where(sq.Lt{alias + ".merged_at": *filter.MergedTo})
+	}
+	if filter.FewerReviewersThan != nil {
+		query = query.Where("(SELECT COUNT(*) FROM pr_reviewers cr WHERE cr.pr_id = "+alias+".id) < ?",
+			*filter.FewerReviewersThan)
+	}
+	return query
+}
+
+// escapeLike escapes LIKE wildcards so the value is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -2,10 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
 )
 
 type errRow struct {
@@ -51,31 +57,114 @@ type toSqler interface {
 	ToSql() (string, []any, error)
 }
 
+type queryer interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type execer interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 }
 
-func tryExec(ctx context.Context, query toSqler, executor execer) error {
+const (
+	dbSystem   = "postgresql"
+	spanPrefix = "postgres."
+)
+
+// tryExec and the other try helpers run the query in a client span named
+// after the repository method op, e.g. "PRRepository.Create".
+func tryExec(ctx context.Context, op string, query toSqler, executor execer) error {
+	_, err := tryExecAffected(ctx, op, query, executor)
+	return err
+}
+
+func tryExecAffected(ctx context.Context, op string, query toSqler, executor execer) (int64, error) {
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return err
+		return 0, err
 	}
-	_, err = executor.Exec(ctx, sql, args...)
-	return err
+	ctx, span := tracing.StartQuery(ctx, dbSystem, spanPrefix+op, sql)
+	start := time.Now()
+	tag, err := executor.Exec(ctx, sql, args...)
+	logQuery(ctx, sql, start, err)
+	tracing.End(span, err)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
 
-func tryQueryRow(ctx context.Context, query toSqler, pool *pgxpool.Pool) pgx.Row {
+// tryQueryRow ends the span once the row is scanned, as pgx reads the result
+// lazily.
+func tryQueryRow(ctx context.Context, op string, query toSqler, q queryer) pgx.Row {
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return errRow{err: err}
 	}
-	return pool.QueryRow(ctx, sql, args...)
+	ctx, span := tracing.StartQuery(ctx, dbSystem, spanPrefix+op, sql)
+	start := time.Now()
+	row := q.QueryRow(ctx, sql, args...)
+	logQuery(ctx, sql, start, nil)
+	return tracedRow{Row: row, span: span}
 }
 
-func tryQuery(ctx context.Context, query toSqler, pool *pgxpool.Pool) (pgx.Rows, error) {
+// tryQuery ends the span when the rows are closed.
+func tryQuery(ctx context.Context, op string, query toSqler, q queryer) (pgx.Rows, error) {
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return errRows{err: err}, err
 	}
-	return pool.Query(ctx, sql, args...)
+	ctx, span := tracing.StartQuery(ctx, dbSystem, spanPrefix+op, sql)
+	start := time.Now()
+	rows, err := q.Query(ctx, sql, args...)
+	logQuery(ctx, sql, start, err)
+	if err != nil {
+		tracing.End(span, err)
+		return rows, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+// logQuery writes a debug record with the statement to the logger of the
+// request or job, arguments are left out as they may hold user data.
+func logQuery(ctx context.Context, sql string, start time.Time, err error) {
+	entry := logging.FromContext(ctx, nil)
+	if !entry.Logger.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+	entry = entry.WithFields(log.Fields{
+		"component":  "postgres",
+		"statement":  sql,
+		"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Debug("Query executed")
+}
+
+type tracedRow struct {
+	pgx.Row
+	span trace.Span
+}
+
+func (r tracedRow) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// not found is an expected outcome
+		tracing.End(r.span, nil)
+		return err
+	}
+	tracing.End(r.span, err)
+	return err
+}
+
+type tracedRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	tracing.End(r.span, r.Rows.Err())
 }
@@ -0,0 +1,140 @@
+package postgres_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	log "github.com/sirupsen/logrus"
+
+	repopg "github.com/Xausdorf/pr-reviewer-assignment/internal/repository/postgres"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/repotest"
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/migrate"
+	pg "github.com/Xausdorf/pr-reviewer-assignment/pkg/postgres"
+)
+
+// truncateAll lists every table of the migrations, so no case sees rows of another.
+const truncateAll = "TRUNCATE teams, users, prs, pr_reviewers, user_schedules, user_out_of_office, " +
+	"scheduled_status_changes, review_escalations, reviewer_audit, review_declines, removed_reviewers, " +
+	"user_status_history RESTART IDENTITY CASCADE"
+
+func TestRepositories(t *testing.T) {
+	pool := startPostgres(t)
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		if _, err := pool.Exec(t.Context(), truncateAll); err != nil {
+			t.Fatalf("failed to clean database: %v", err)
+		}
+		return repotest.Repositories{
+			PR:           repopg.NewPRRepository(pool),
+			Team:         repopg.NewTeamRepository(pool),
+			User:         repopg.NewUserRepository(pool),
+			Schedule:     repopg.NewScheduleRepository(pool),
+			StatusChange: repopg.NewStatusChangeRepository(pool),
+			SLA:          repopg.NewSLARepository(pool),
+			Audit:        repopg.NewReviewerAuditRepository(pool),
+			Decline:      repopg.NewDeclineRepository(pool),
+			Org:          repopg.NewOrgRepository(pool),
+		}
+	})
+}
+
+// startPostgres initializes a throwaway cluster with the locally installed
+// Postgres binaries and applies the migrations. The server listens only on a
+// unix socket in a temporary directory. POSTGRES_BIN_DIR points to the
+// binaries explicitly. PG_TEST_DSN uses an existing database instead; all its
+// tables are truncated. The test is skipped when neither is available, unless
+// CI is set, where a missing database fails the run.
+func startPostgres(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	if dsn := os.Getenv("PG_TEST_DSN"); dsn != "" {
+		return connect(t, dsn)
+	}
+
+	binDir, ok := findPostgresBin()
+	if !ok {
+		skipUnlessCI(t, "postgres binaries not found, set POSTGRES_BIN_DIR or PG_TEST_DSN "+
+			"to run postgres repository tests")
+	}
+	if os.Geteuid() == 0 {
+		skipUnlessCI(t, "postgres refuses to run as root, set PG_TEST_DSN to run postgres repository tests")
+	}
+
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	run(t, filepath.Join(binDir, "initdb"), "-D", dataDir, "-U", "postgres", "-A", "trust",
+		"-E", "UTF8", "--locale=C", "--no-sync")
+
+	pgCtl := filepath.Join(binDir, "pg_ctl")
+	run(t, pgCtl, "-D", dataDir, "-l", filepath.Join(dir, "postgres.log"), "-w",
+		"-o", fmt.Sprintf("-k %s -c listen_addresses='' -F", dir), "start")
+	t.Cleanup(func() {
+		_ = exec.Command(pgCtl, "-D", dataDir, "-m", "immediate", "stop").Run()
+	})
+
+	return connect(t, fmt.Sprintf("postgres://postgres@/postgres?host=%s&sslmode=disable", dir))
+}
+
+// connect applies the migrations to the database and opens a pool to it.
+func connect(t *testing.T, dsn string) *pgxpool.Pool {
+	t.Helper()
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	migrationsDir, err := filepath.Abs("../../../migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = migrate.RunMigrations(dsn, migrationsDir, logger); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	pool, err := pg.NewPool(t.Context(), pg.Config{ConnString: dsn}, logger)
+	if err != nil {
+		t.Fatalf("failed to connect to postgres: %v", err)
+	}
+	t.Cleanup(func() { pg.ClosePool(pool) })
+
+	return pool
+}
+
+// skipUnlessCI skips the test locally but fails it in CI, so the postgres
+// suite can not silently stop running there.
+func skipUnlessCI(t *testing.T, reason string) {
+	t.Helper()
+
+	if os.Getenv("CI") != "" {
+		t.Fatal(reason)
+	}
+	t.Skip(reason)
+}
+
+func findPostgresBin() (string, bool) {
+	if dir := os.Getenv("POSTGRES_BIN_DIR"); dir != "" {
+		return dir, true
+	}
+	if path, err := exec.LookPath("pg_ctl"); err == nil {
+		return filepath.Dir(path), true
+	}
+	// Debian and Ubuntu keep server binaries out of PATH
+	matches, _ := filepath.Glob("/usr/lib/postgresql/*/bin/pg_ctl")
+	if len(matches) > 0 {
+		return filepath.Dir(matches[len(matches)-1]), true
+	}
+	return "", false
+}
+
+func run(t *testing.T, name string, args ...string) {
+	t.Helper()
+
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s failed: %v\n%s", filepath.Base(name), err, out)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -29,11 +30,14 @@ func (r *PRRepository) Create(ctx context.Context, pr entity.PR) error {
 		Columns("id", "title", "author_id", "status", "created_at").
 		Values(pr.ID, pr.Title, pr.AuthorID, pr.Status, pr.CreatedAt)
 
-	if err := tryExec(ctx, query, r.pool); err != nil {
+	if err := tryExec(ctx, "PRRepository.Create", query, r.pool); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return apperror.ErrPRExists
 		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperror.ErrNotFound
+		}
 		return fmt.Errorf("PRRepository.Create failed to insert pr: %w", err)
 	}
 
@@ -46,7 +50,7 @@ func (r *PRRepository) GetByID(ctx context.Context, id string) (*entity.PR, erro
 		From("prs").
 		Where(sq.Eq{"id": id})
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "PRRepository.GetByID", query, r.pool)
 
 	var pr entity.PR
 	if err := row.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
@@ -66,7 +70,7 @@ func (r *PRRepository) UpdateStatus(ctx context.Context, id, status string) (*en
 		Where(sq.Eq{"id": id}).
 		Suffix("RETURNING id, title, author_id, status, created_at, merged_at")
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "PRRepository.UpdateStatus", query, r.pool)
 
 	var pr entity.PR
 	if err := row.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
@@ -79,36 +83,73 @@ func (r *PRRepository) UpdateStatus(ctx context.Context, id, status string) (*en
 	return &pr, nil
 }
 
-func (r *PRRepository) AssignReviewer(ctx context.Context, prID, teamName string) (string, error) {
-	querySelect := r.sb.
-		Select("id").
-		From("users").
-		Where(sq.Eq{"team_name": teamName, "is_active": true}).
-		Where("id NOT IN (SELECT reviewer_id FROM pr_reviewers WHERE pr_id = ?)", prID).
-		Where("id NOT IN (SELECT author_id FROM prs WHERE id = ?)", prID).
-		OrderBy("RANDOM()").
-		Limit(1)
+// AddReviewer assigns the user to the PR.
+func (r *PRRepository) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	return r.insertReviewer(ctx, "PRRepository.AddReviewer", prID, reviewerID, r.pool)
+}
 
-	row := tryQueryRow(ctx, querySelect, r.pool)
+// AddReviewerWithLimit assigns the user to the PR unless it already has
+// maxReviewers reviewers. The PR row is locked for the count, so concurrent
+// calls on the same PR can not exceed the limit together.
+func (r *PRRepository) AddReviewerWithLimit(ctx context.Context, prID, reviewerID string, maxReviewers int) error {
+	const op = "PRRepository.AddReviewerWithLimit"
 
-	var reviewerID string
-	if err := row.Scan(&reviewerID); err != nil {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("PRRepository.AddReviewerWithLimit failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	lock := r.sb.
+		Select("id").
+		From("prs").
+		Where(sq.Eq{"id": prID}).
+		Suffix("FOR UPDATE")
+	var id string
+	if err = tryQueryRow(ctx, op, lock, tx).Scan(&id); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", apperror.ErrNoCandidate
+			return apperror.ErrNotFound
 		}
-		return "", err
+		return fmt.Errorf("PRRepository.AddReviewerWithLimit failed to lock pr: %w", err)
+	}
+
+	count := r.sb.
+		Select("COUNT(*)").
+		From("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID})
+	var assigned int
+	if err = tryQueryRow(ctx, op, count, tx).Scan(&assigned); err != nil {
+		return fmt.Errorf("PRRepository.AddReviewerWithLimit failed to count reviewers: %w", err)
+	}
+	if assigned >= maxReviewers {
+		return apperror.ErrReviewersFull
 	}
 
-	queryInsert := r.sb.
+	if err = r.insertReviewer(ctx, op, prID, reviewerID, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PRRepository) insertReviewer(ctx context.Context, op, prID, reviewerID string, executor execer) error {
+	query := r.sb.
 		Insert("pr_reviewers").
 		Columns("pr_id", "reviewer_id").
 		Values(prID, reviewerID)
 
-	if err := tryExec(ctx, queryInsert, r.pool); err != nil {
-		return "", fmt.Errorf("PRRepository.AssignReviewer failed to insert pr_reviewer: %w", err)
+	if err := tryExec(ctx, op, query, executor); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return apperror.ErrReviewerExists
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("%s failed to insert pr_reviewer: %w", op, err)
 	}
 
-	return reviewerID, nil
+	return nil
 }
 
 func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
@@ -116,19 +157,68 @@ func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, reviewerID stri
 		Delete("pr_reviewers").
 		Where(sq.Eq{"pr_id": prID, "reviewer_id": reviewerID})
 
-	if err := tryExec(ctx, query, r.pool); err != nil {
+	if err := tryExec(ctx, "PRRepository.RemoveReviewer", query, r.pool); err != nil {
 		return fmt.Errorf("PRRepository.RemoveReviewer failed to delete pr_reviewer: %w", err)
 	}
 
 	return nil
 }
 
+// MarkReviewerRemoved remembers that the reviewer was taken off the PR, so the
+// reviewer is not picked for it again.
+func (r *PRRepository) MarkReviewerRemoved(ctx context.Context, prID, reviewerID string) error {
+	query := r.sb.
+		Insert("removed_reviewers").
+		Columns("pr_id", "reviewer_id").
+		Values(prID, reviewerID).
+		Suffix("ON CONFLICT (pr_id, reviewer_id) DO UPDATE SET removed_at = now()")
+
+	if err := tryExec(ctx, "PRRepository.MarkReviewerRemoved", query, r.pool); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("PRRepository.MarkReviewerRemoved failed to insert removed reviewer: %w", err)
+	}
+
+	return nil
+}
+
+// ListRemovedReviewers returns ids of reviewers ever taken off the PR.
+func (r *PRRepository) ListRemovedReviewers(ctx context.Context, prID string) ([]string, error) {
+	query := r.sb.
+		Select("reviewer_id").
+		From("removed_reviewers").
+		Where(sq.Eq{"pr_id": prID}).
+		OrderBy("reviewer_id")
+
+	rows, err := tryQuery(ctx, "PRRepository.ListRemovedReviewers", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.ListRemovedReviewers failed to select removed reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	removedIDs := make([]string, 0)
+	for rows.Next() {
+		var reviewerID string
+		if err = rows.Scan(&reviewerID); err != nil {
+			return nil, fmt.Errorf("PRRepository.ListRemovedReviewers failed to scan reviewer ID: %w", err)
+		}
+		removedIDs = append(removedIDs, reviewerID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PRRepository.ListRemovedReviewers failed to select removed reviewers: %w", err)
+	}
+
+	return removedIDs, nil
+}
+
 func (r *PRRepository) DeleteByID(ctx context.Context, prID string) error {
 	query := r.sb.
 		Delete("prs").
 		Where(sq.Eq{"id": prID})
 
-	if err := tryExec(ctx, query, r.pool); err != nil {
+	if err := tryExec(ctx, "PRRepository.DeleteByID", query, r.pool); err != nil {
 		return fmt.Errorf("PRRepository.DeleteByID failed to delete pr: %w", err)
 	}
 
@@ -141,7 +231,7 @@ func (r *PRRepository) GetAssignedReviewers(ctx context.Context, prID string) ([
 		From("pr_reviewers").
 		Where(sq.Eq{"pr_id": prID})
 
-	rows, err := tryQuery(ctx, query, r.pool)
+	rows, err := tryQuery(ctx, "PRRepository.GetAssignedReviewers", query, r.pool)
 	if err != nil {
 		return nil, fmt.Errorf("PRRepository.GetAssignedReviewers failed to select reviewers: %w", err)
 	}
@@ -155,6 +245,231 @@ func (r *PRRepository) GetAssignedReviewers(ctx context.Context, prID string) ([
 		}
 		assignedIDs = append(assignedIDs, reviewerID)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PRRepository.GetAssignedReviewers failed to select reviewers: %w", err)
+	}
 
 	return assignedIDs, nil
 }
+
+// List returns a page of PRs matching the options. Reviewers are not loaded, see
+// GetReviewersForPRs.
+func (r *PRRepository) List(ctx context.Context, opts entity.PRListOptions) (entity.Page[entity.PR], error) {
+	key, keyOf, err := prSortKey("p", opts.Sort)
+	if err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	query := r.sb.
+		Select("p.id", "p.title", "p.author_id", "p.status", "p.created_at", "p.merged_at").
+		From("prs p")
+	query = applyPRFilter(query, "p", opts.Filter)
+	if query, err = paginate(query, key, opts.Page); err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	rows, err := tryQuery(ctx, "PRRepository.List", query, r.pool)
+	if err != nil {
+		return entity.Page[entity.PR]{}, fmt.Errorf("PRRepository.List failed to select PRs: %w", err)
+	}
+	defer rows.Close()
+
+	prs := make([]entity.PR, 0)
+	for rows.Next() {
+		var pr entity.PR
+		if err = rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
+			return entity.Page[entity.PR]{}, fmt.Errorf("PRRepository.List failed to scan PR: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+	if err = rows.Err(); err != nil {
+		return entity.Page[entity.PR]{}, fmt.Errorf("PRRepository.List failed to select PRs: %w", err)
+	}
+
+	return trimPage(prs, opts.Page.Limit, keyOf), nil
+}
+
+// GetReviewersForPRs returns reviewer ids of several PRs in one query, keyed by PR id.
+func (r *PRRepository) GetReviewersForPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
+	reviewers := make(map[string][]string, len(prIDs))
+	if len(prIDs) == 0 {
+		return reviewers, nil
+	}
+
+	query := r.sb.
+		Select("pr_id", "reviewer_id").
+		From("pr_reviewers").
+		Where("pr_id = ANY(?)", prIDs).
+		OrderBy("pr_id", "assigned_at")
+
+	rows, err := tryQuery(ctx, "PRRepository.GetReviewersForPRs", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.GetReviewersForPRs failed to select reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID, reviewerID string
+		if err = rows.Scan(&prID, &reviewerID); err != nil {
+			return nil, fmt.Errorf("PRRepository.GetReviewersForPRs failed to scan reviewer: %w", err)
+		}
+		reviewers[prID] = append(reviewers[prID], reviewerID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PRRepository.GetReviewersForPRs failed to select reviewers: %w", err)
+	}
+
+	return reviewers, nil
+}
+
+// GetReviewerDetails returns reviewers of the PR with their profiles in assignment order.
+func (r *PRRepository) GetReviewerDetails(ctx context.Context, prID string) ([]entity.AssignedReviewer, error) {
+	query := r.sb.
+		Select("u.id", "u.name", "u.team_name", "u.is_active", "u.created_at", "u.max_open_reviews", "r.assigned_at").
+		From("pr_reviewers r").
+		Join("users u ON u.id = r.reviewer_id").
+		Where(sq.Eq{"r.pr_id": prID}).
+		OrderBy("r.assigned_at", "u.id")
+
+	rows, err := tryQuery(ctx, "PRRepository.GetReviewerDetails", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.GetReviewerDetails failed to select reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	reviewers := make([]entity.AssignedReviewer, 0)
+	for rows.Next() {
+		var rv entity.AssignedReviewer
+		err = rows.Scan(&rv.ID, &rv.Name, &rv.TeamName, &rv.IsActive, &rv.CreatedAt, &rv.MaxOpenReviews, &rv.AssignedAt)
+		if err != nil {
+			return nil, fmt.Errorf("PRRepository.GetReviewerDetails failed to scan reviewer: %w", err)
+		}
+		reviewers = append(reviewers, rv)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PRRepository.GetReviewerDetails failed to select reviewers: %w", err)
+	}
+
+	return reviewers, nil
+}
+
+// CountAssignments returns the number of assignments made to members of the team
+// since the given moment, keyed by reviewer id. Members without assignments are omitted.
+func (r *PRRepository) CountAssignments(ctx context.Context, teamName string, since time.Time) (map[string]int, error) {
+	query := r.sb.
+		Select("r.reviewer_id", "COUNT(*)").
+		From("pr_reviewers r").
+		Join("users u ON u.id = r.reviewer_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.GtOrEq{"r.assigned_at": since}).
+		GroupBy("r.reviewer_id")
+
+	rows, err := tryQuery(ctx, "PRRepository.CountAssignments", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.CountAssignments failed to count assignments: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			reviewerID string
+			count      int
+		)
+		if err = rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("PRRepository.CountAssignments failed to scan count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PRRepository.CountAssignments failed to count assignments: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountOpenReviews returns the number of open PRs each member of the team is
+// assigned to, keyed by reviewer id. Members without open reviews are omitted.
+func (r *PRRepository) CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error) {
+	query := r.sb.
+		Select("r.reviewer_id", "COUNT(*)").
+		From("pr_reviewers r").
+		Join("prs p ON p.id = r.pr_id").
+		Join("users u ON u.id = r.reviewer_id").
+		Where(sq.Eq{"u.team_name": teamName, "p.status": entity.PRStatusOpen}).
+		GroupBy("r.reviewer_id")
+
+	rows, err := tryQuery(ctx, "PRRepository.CountOpenReviews", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.CountOpenReviews failed to count reviews: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			reviewerID string
+			count      int
+		)
+		if err = rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("PRRepository.CountOpenReviews failed to scan count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("PRRepository.CountOpenReviews failed to count reviews: %w", err)
+	}
+
+	return counts, nil
+}
+
+// replaceReviewer hands the review of the PR over from oldID to newID and
+// remembers oldID as removed. The PR must still be open and reviewed by oldID.
+func replaceReviewer(
+	ctx context.Context,
+	sb sq.StatementBuilderType,
+	op string,
+	tx execer,
+	prID, oldID, newID string,
+) error {
+	remove := sb.
+		Delete("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID, "reviewer_id": oldID}).
+		Where("pr_id IN (SELECT id FROM prs WHERE status = ?)", entity.PRStatusOpen)
+
+	removed, err := tryExecAffected(ctx, op, remove, tx)
+	if err != nil {
+		return fmt.Errorf("%s failed to delete pr_reviewer: %w", op, err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("review of %s by %s: %w", prID, oldID, apperror.ErrNotAssigned)
+	}
+
+	add := sb.
+		Insert("pr_reviewers").
+		Columns("pr_id", "reviewer_id").
+		Values(prID, newID)
+
+	if err = tryExec(ctx, op, add, tx); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("review of %s by %s: %w", prID, newID, apperror.ErrReviewerExists)
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("%s failed to insert pr_reviewer: %w", op, err)
+	}
+
+	mark := sb.
+		Insert("removed_reviewers").
+		Columns("pr_id", "reviewer_id").
+		Values(prID, oldID).
+		Suffix("ON CONFLICT (pr_id, reviewer_id) DO UPDATE SET removed_at = now()")
+
+	if err = tryExec(ctx, op, mark, tx); err != nil {
+		return fmt.Errorf("%s failed to insert removed reviewer: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+const scheduleColumns = "user_id, timezone, to_char(work_start, 'HH24:MI'), to_char(work_end, 'HH24:MI'), updated_at"
+
+type ScheduleRepository struct {
+	pool *pgxpool.Pool
+	sb   sq.StatementBuilderType
+}
+
+func NewScheduleRepository(pool *pgxpool.Pool) *ScheduleRepository {
+	return &ScheduleRepository{pool: pool, sb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar)}
+}
+
+func (r *ScheduleRepository) UpsertSchedule(ctx context.Context, s entity.WorkSchedule) (*entity.WorkSchedule, error) {
+	query := r.sb.
+		Insert("user_schedules").
+		Columns("user_id", "timezone", "work_start", "work_end", "updated_at").
+		Values(s.UserID, s.Timezone, s.WorkStart, s.WorkEnd, s.UpdatedAt).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET " +
+			"timezone = EXCLUDED.timezone, work_start = EXCLUDED.work_start, " +
+			"work_end = EXCLUDED.work_end, updated_at = EXCLUDED.updated_at " +
+			"RETURNING " + scheduleColumns)
+
+	row := tryQueryRow(ctx, "ScheduleRepository.UpsertSchedule", query, r.pool)
+
+	var out entity.WorkSchedule
+	if err := row.Scan(&out.UserID, &out.Timezone, &out.WorkStart, &out.WorkEnd, &out.UpdatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ScheduleRepository.UpsertSchedule failed to upsert schedule: %w", err)
+	}
+
+	return &out, nil
+}
+
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, userID string) (*entity.WorkSchedule, error) {
+	query := r.sb.
+		Select(scheduleColumns).
+		From("user_schedules").
+		Where(sq.Eq{"user_id": userID})
+
+	row := tryQueryRow(ctx, "ScheduleRepository.GetSchedule", query, r.pool)
+
+	var s entity.WorkSchedule
+	if err := row.Scan(&s.UserID, &s.Timezone, &s.WorkStart, &s.WorkEnd, &s.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ScheduleRepository.GetSchedule failed to select schedule: %w", err)
+	}
+
+	return &s, nil
+}
+
+func (r *ScheduleRepository) DeleteSchedule(ctx context.Context, userID string) error {
+	query := r.sb.
+		Delete("user_schedules").
+		Where(sq.Eq{"user_id": userID})
+
+	affected, err := tryExecAffected(ctx, "ScheduleRepository.DeleteSchedule", query, r.pool)
+	if err != nil {
+		return fmt.Errorf("ScheduleRepository.DeleteSchedule failed to delete schedule: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) ListTeamSchedules(ctx context.Context, teamName string) ([]entity.WorkSchedule, error) {
+	query := r.sb.
+		Select("s.user_id", "s.timezone", "to_char(s.work_start, 'HH24:MI')",
+			"to_char(s.work_end, 'HH24:MI')", "s.updated_at").
+		From("user_schedules s").
+		Join("users u ON u.id = s.user_id").
+		Where(sq.Eq{"u.team_name": teamName})
+
+	rows, err := tryQuery(ctx, "ScheduleRepository.ListTeamSchedules", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduleRepository.ListTeamSchedules failed to select schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]entity.WorkSchedule, 0)
+	for rows.Next() {
+		var s entity.WorkSchedule
+		if err = rows.Scan(&s.UserID, &s.Timezone, &s.WorkStart, &s.WorkEnd, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("ScheduleRepository.ListTeamSchedules failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ScheduleRepository.ListTeamSchedules failed to select schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+func (r *ScheduleRepository) AddOutOfOffice(ctx context.Context, o entity.OutOfOffice) (*entity.OutOfOffice, error) {
+	query := r.sb.
+		Insert("user_out_of_office").
+		Columns("user_id", "starts_at", "ends_at", "reason", "created_at").
+		Values(o.UserID, o.StartsAt, o.EndsAt, o.Reason, o.CreatedAt).
+		Suffix("RETURNING id, user_id, starts_at, ends_at, reason, created_at")
+
+	row := tryQueryRow(ctx, "ScheduleRepository.AddOutOfOffice", query, r.pool)
+
+	var out entity.OutOfOffice
+	if err := row.Scan(&out.ID, &out.UserID, &out.StartsAt, &out.EndsAt, &out.Reason, &out.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ScheduleRepository.AddOutOfOffice failed to insert out of office: %w", err)
+	}
+
+	return &out, nil
+}
+
+func (r *ScheduleRepository) DeleteOutOfOffice(ctx context.Context, id int64) error {
+	query := r.sb.
+		Delete("user_out_of_office").
+		Where(sq.Eq{"id": id})
+
+	affected, err := tryExecAffected(ctx, "ScheduleRepository.DeleteOutOfOffice", query, r.pool)
+	if err != nil {
+		return fmt.Errorf("ScheduleRepository.DeleteOutOfOffice failed to delete out of office: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListOutOfOffice returns periods of the user that have not ended by now.
+func (r *ScheduleRepository) ListOutOfOffice(
+	ctx context.Context,
+	userID string,
+	now time.Time,
+) ([]entity.OutOfOffice, error) {
+	query := r.sb.
+		Select("id", "user_id", "starts_at", "ends_at", "reason", "created_at").
+		From("user_out_of_office").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Gt{"ends_at": now}).
+		OrderBy("starts_at")
+
+	return r.selectOutOfOffice(ctx, query, "ListOutOfOffice")
+}
+
+// ListTeamOutOfOffice returns periods of the team members that are in effect at the given moment.
+func (r *ScheduleRepository) ListTeamOutOfOffice(
+	ctx context.Context,
+	teamName string,
+	at time.Time,
+) ([]entity.OutOfOffice, error) {
+	query := r.sb.
+		Select("o.id", "o.user_id", "o.starts_at", "o.ends_at", "o.reason", "o.created_at").
+		From("user_out_of_office o").
+		Join("users u ON u.id = o.user_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.LtOrEq{"o.starts_at": at}).
+		Where(sq.Gt{"o.ends_at": at})
+
+	return r.selectOutOfOffice(ctx, query, "ListTeamOutOfOffice")
+}
+
+// ListTeamOutOfOfficeBetween returns periods of the team members that overlap [from, to).
+func (r *ScheduleRepository) ListTeamOutOfOfficeBetween(
+	ctx context.Context,
+	teamName string,
+	from, to time.Time,
+) ([]entity.OutOfOffice, error) {
+	query := r.sb.
+		Select("o.id", "o.user_id", "o.starts_at", "o.ends_at", "o.reason", "o.created_at").
+		From("user_out_of_office o").
+		Join("users u ON u.id = o.user_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.Lt{"o.starts_at": to}).
+		Where(sq.Gt{"o.ends_at": from}).
+		OrderBy("o.starts_at", "o.id")
+
+	return r.selectOutOfOffice(ctx, query, "ListTeamOutOfOfficeBetween")
+}
+
+func (r *ScheduleRepository) selectOutOfOffice(
+	ctx context.Context,
+	query sq.SelectBuilder,
+	method string,
+) ([]entity.OutOfOffice, error) {
+	rows, err := tryQuery(ctx, "ScheduleRepository."+method, query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduleRepository.%s failed to select out of office: %w", method, err)
+	}
+	defer rows.Close()
+
+	periods := make([]entity.OutOfOffice, 0)
+	for rows.Next() {
+		var o entity.OutOfOffice
+		if err = rows.Scan(&o.ID, &o.UserID, &o.StartsAt, &o.EndsAt, &o.Reason, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ScheduleRepository.%s failed to scan out of office: %w", method, err)
+		}
+		periods = append(periods, o)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ScheduleRepository.%s failed to select out of office: %w", method, err)
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type SLARepository struct {
+	pool *pgxpool.Pool
+	sb   sq.StatementBuilderType
+}
+
+func NewSLARepository(pool *pgxpool.Pool) *SLARepository {
+	return &SLARepository{pool: pool, sb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar)}
+}
+
+// SetTeamSLA sets the review SLA of the team, nil removes it.
+func (r *SLARepository) SetTeamSLA(ctx context.Context, teamName string, sla *time.Duration) error {
+	var seconds *int64
+	if sla != nil {
+		s := int64(sla.Seconds())
+		seconds = &s
+	}
+
+	query := r.sb.
+		Update("teams").
+		Set("review_sla_seconds", seconds).
+		Where(sq.Eq{"name": teamName})
+
+	affected, err := tryExecAffected(ctx, "SLARepository.SetTeamSLA", query, r.pool)
+	if err != nil {
+		return fmt.Errorf("SLARepository.SetTeamSLA failed to update team: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SLARepository) GetTeamSLA(ctx context.Context, teamName string) (*time.Duration, error) {
+	query := r.sb.
+		Select("review_sla_seconds").
+		From("teams").
+		Where(sq.Eq{"name": teamName})
+
+	row := tryQueryRow(ctx, "SLARepository.GetTeamSLA", query, r.pool)
+
+	var seconds *int64
+	if err := row.Scan(&seconds); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("SLARepository.GetTeamSLA failed to select team: %w", err)
+	}
+	if seconds == nil {
+		return nil, nil //nolint:nilnil // no SLA configured is not an error
+	}
+
+	sla := time.Duration(*seconds) * time.Second
+	return &sla, nil
+}
+
+// ListOverdueAssignments returns assignments on open PRs that exceeded the SLA of
+// the reviewer's team at the given moment and were not escalated yet.
+func (r *SLARepository) ListOverdueAssignments(ctx context.Context, now time.Time) ([]entity.OverdueAssignment, error) {
+	query := r.sb.
+		Select("r.pr_id", "r.reviewer_id", "u.team_name", "r.assigned_at", "t.review_sla_seconds").
+		From("pr_reviewers r").
+		Join("prs p ON p.id = r.pr_id").
+		Join("users u ON u.id = r.reviewer_id").
+		Join("teams t ON t.name = u.team_name").
+		Where(sq.Eq{"p.status": entity.PRStatusOpen}).
+		Where(sq.NotEq{"t.review_sla_seconds": nil}).
+		Where("r.assigned_at + make_interval(secs => t.review_sla_seconds) <= ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM review_escalations e " +
+			"WHERE e.pr_id = r.pr_id AND e.reviewer_id = r.reviewer_id AND e.assigned_at = r.assigned_at)").
+		OrderBy("r.assigned_at")
+
+	rows, err := tryQuery(ctx, "SLARepository.ListOverdueAssignments", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("SLARepository.ListOverdueAssignments failed to select assignments: %w", err)
+	}
+	defer rows.Close()
+
+	overdue := make([]entity.OverdueAssignment, 0)
+	for rows.Next() {
+		var (
+			o       entity.OverdueAssignment
+			seconds int64
+		)
+		if err = rows.Scan(&o.PRID, &o.ReviewerID, &o.TeamName, &o.AssignedAt, &seconds); err != nil {
+			return nil, fmt.Errorf("SLARepository.ListOverdueAssignments failed to scan assignment: %w", err)
+		}
+		o.SLA = time.Duration(seconds) * time.Second
+		overdue = append(overdue, o)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("SLARepository.ListOverdueAssignments failed to select assignments: %w", err)
+	}
+
+	return overdue, nil
+}
+
+// RecordEscalation stores the escalation and returns false when the same
+// assignment was already escalated, e.g. by another replica.
+func (r *SLARepository) RecordEscalation(ctx context.Context, e entity.Escalation) (*entity.Escalation, bool, error) {
+	query := r.sb.
+		Insert("review_escalations").
+		Columns("pr_id", "reviewer_id", "team_name", "assigned_at", "due_at", "detected_at", "action").
+		Values(e.PRID, e.ReviewerID, e.TeamName, e.AssignedAt, e.DueAt, e.DetectedAt, e.Action).
+		Suffix("ON CONFLICT (pr_id, reviewer_id, assigned_at) DO NOTHING RETURNING id")
+
+	row := tryQueryRow(ctx, "SLARepository.RecordEscalation", query, r.pool)
+
+	if err := row.Scan(&e.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("SLARepository.RecordEscalation failed to insert escalation: %w", err)
+	}
+
+	return &e, true, nil
+}
+
+func (r *SLARepository) UpdateEscalationAction(ctx context.Context, id int64, action, newReviewerID string) error {
+	query := r.sb.
+		Update("review_escalations").
+		Set("action", action).
+		Set("new_reviewer_id", newReviewerID).
+		Where(sq.Eq{"id": id})
+
+	if err := tryExec(ctx, "SLARepository.UpdateEscalationAction", query, r.pool); err != nil {
+		return fmt.Errorf("SLARepository.UpdateEscalationAction failed to update escalation: %w", err)
+	}
+
+	return nil
+}
+
+// ListOpenEscalations returns escalations of PRs that are still open.
+func (r *SLARepository) ListOpenEscalations(ctx context.Context) ([]entity.Escalation, error) {
+	query := r.sb.
+		Select("e.id", "e.pr_id", "e.reviewer_id", "e.team_name", "e.assigned_at", "e.due_at",
+			"e.detected_at", "e.action", "e.new_reviewer_id").
+		From("review_escalations e").
+		Join("prs p ON p.id = e.pr_id").
+		Where(sq.Eq{"p.status": entity.PRStatusOpen}).
+		OrderBy("e.due_at", "e.id")
+
+	rows, err := tryQuery(ctx, "SLARepository.ListOpenEscalations", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("SLARepository.ListOpenEscalations failed to select escalations: %w", err)
+	}
+	defer rows.Close()
+
+	escalations := make([]entity.Escalation, 0)
+	for rows.Next() {
+		var e entity.Escalation
+		if err = rows.Scan(&e.ID, &e.PRID, &e.ReviewerID, &e.TeamName, &e.AssignedAt, &e.DueAt,
+			&e.DetectedAt, &e.Action, &e.NewReviewerID); err != nil {
+			return nil, fmt.Errorf("SLARepository.ListOpenEscalations failed to scan escalation: %w", err)
+		}
+		escalations = append(escalations, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("SLARepository.ListOpenEscalations failed to select escalations: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// GetReviewStats aggregates timings of PRs authored by members of the team.
+func (r *SLARepository) GetReviewStats(
+	ctx context.Context,
+	teamName string,
+	now time.Time,
+) (*entity.ReviewStats, error) {
+	sla, err := r.GetTeamSLA(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	queryTimings := r.sb.
+		Select("COUNT(*)", "COUNT(p.merged_at)",
+			"COALESCE(EXTRACT(EPOCH FROM AVG(p.merged_at - p.created_at)), 0)::float8").
+		From("prs p").
+		Join("users a ON a.id = p.author_id").
+		Where(sq.Eq{"a.team_name": teamName})
+
+	stats := entity.ReviewStats{TeamName: teamName, SLA: sla}
+	var mergeSeconds float64
+	row := tryQueryRow(ctx, "SLARepository.GetReviewStats", queryTimings, r.pool)
+	if err = row.Scan(&stats.PRCount, &stats.MergedCount, &mergeSeconds); err != nil {
+		return nil, fmt.Errorf("SLARepository.GetReviewStats failed to aggregate timings: %w", err)
+	}
+	stats.AvgTimeToMerge = time.Duration(mergeSeconds * float64(time.Second))
+
+	queryOpen := r.sb.
+		Select("COUNT(*)").
+		Column(sq.Expr("COUNT(*) FILTER (WHERE t.review_sla_seconds IS NOT NULL "+
+			"AND r.assigned_at + make_interval(secs => t.review_sla_seconds) <= ?)", now)).
+		From("pr_reviewers r").
+		Join("prs p ON p.id = r.pr_id").
+		Join("users u ON u.id = r.reviewer_id").
+		Join("teams t ON t.name = u.team_name").
+		Where(sq.Eq{"p.status": entity.PRStatusOpen, "u.team_name": teamName})
+
+	row = tryQueryRow(ctx, "SLARepository.GetReviewStats", queryOpen, r.pool)
+	if err = row.Scan(&stats.OpenAssignments, &stats.OverdueAssignments); err != nil {
+		return nil, fmt.Errorf("SLARepository.GetReviewStats failed to count open assignments: %w", err)
+	}
+
+	queryDeclines := r.sb.
+		Select("d.reason", "COUNT(*)").
+		From("review_declines d").
+		Join("users u ON u.id = d.reviewer_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		GroupBy("d.reason")
+
+	rows, err := tryQuery(ctx, "SLARepository.GetReviewStats", queryDeclines, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("SLARepository.GetReviewStats failed to count declines: %w", err)
+	}
+	defer rows.Close()
+
+	stats.Declines = make(map[string]int)
+	for rows.Next() {
+		var (
+			reason string
+			count  int
+		)
+		if err = rows.Scan(&reason, &count); err != nil {
+			return nil, fmt.Errorf("SLARepository.GetReviewStats failed to scan declines: %w", err)
+		}
+		stats.Declines[reason] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("SLARepository.GetReviewStats failed to count declines: %w", err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type StatusChangeRepository struct {
+	pool *pgxpool.Pool
+	sb   sq.StatementBuilderType
+}
+
+func NewStatusChangeRepository(pool *pgxpool.Pool) *StatusChangeRepository {
+	return &StatusChangeRepository{pool: pool, sb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar)}
+}
+
+func (r *StatusChangeRepository) Create(ctx context.Context, c entity.StatusChange) (*entity.StatusChange, error) {
+	query := r.sb.
+		Insert("scheduled_status_changes").
+		Columns("user_id", "is_active", "apply_at", "created_at").
+		Values(c.UserID, c.IsActive, c.ApplyAt, c.CreatedAt).
+		Suffix("RETURNING id, user_id, is_active, apply_at, applied_at, created_at")
+
+	row := tryQueryRow(ctx, "StatusChangeRepository.Create", query, r.pool)
+
+	var out entity.StatusChange
+	if err := row.Scan(&out.ID, &out.UserID, &out.IsActive, &out.ApplyAt, &out.AppliedAt, &out.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("StatusChangeRepository.Create failed to insert status change: %w", err)
+	}
+
+	return &out, nil
+}
+
+// ListPending returns not yet applied changes of the user ordered by apply time.
+func (r *StatusChangeRepository) ListPending(ctx context.Context, userID string) ([]entity.StatusChange, error) {
+	query := r.sb.
+		Select("id", "user_id", "is_active", "apply_at", "applied_at", "created_at").
+		From("scheduled_status_changes").
+		Where(sq.Eq{"user_id": userID, "applied_at": nil}).
+		OrderBy("apply_at", "id")
+
+	rows, err := tryQuery(ctx, "StatusChangeRepository.ListPending", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("StatusChangeRepository.ListPending failed to select status changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]entity.StatusChange, 0)
+	for rows.Next() {
+		var c entity.StatusChange
+		if err = rows.Scan(&c.ID, &c.UserID, &c.IsActive, &c.ApplyAt, &c.AppliedAt, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("StatusChangeRepository.ListPending failed to scan status change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("StatusChangeRepository.ListPending failed to select status changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Cancel deletes a pending change. Already applied changes are reported as not found.
+func (r *StatusChangeRepository) Cancel(ctx context.Context, id int64) error {
+	query := r.sb.
+		Delete("scheduled_status_changes").
+		Where(sq.Eq{"id": id, "applied_at": nil})
+
+	affected, err := tryExecAffected(ctx, "StatusChangeRepository.Cancel", query, r.pool)
+	if err != nil {
+		return fmt.Errorf("StatusChangeRepository.Cancel failed to delete status change: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+// ApplyDue locks up to limit changes due at now and passes each of them to apply.
+// Rows are locked with SKIP LOCKED, so concurrent replicas never process the same
+// change. A change is marked as applied only when apply succeeds; failed ones are
+// retried on the next call.
+func (r *StatusChangeRepository) ApplyDue(
+	ctx context.Context,
+	now time.Time,
+	limit uint64,
+	apply func(ctx context.Context, c entity.StatusChange) error,
+) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	querySelect := r.sb.
+		Select("id", "user_id", "is_active", "apply_at", "applied_at", "created_at").
+		From("scheduled_status_changes").
+		Where(sq.Eq{"applied_at": nil}).
+		Where(sq.LtOrEq{"apply_at": now}).
+		OrderBy("apply_at", "id").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	rows, err := tryQuery(ctx, "StatusChangeRepository.ApplyDue", querySelect, tx)
+	if err != nil {
+		return 0, fmt.Errorf("StatusChangeRepository.ApplyDue failed to select due changes: %w", err)
+	}
+	due := make([]entity.StatusChange, 0)
+	for rows.Next() {
+		var c entity.StatusChange
+		if err = rows.Scan(&c.ID, &c.UserID, &c.IsActive, &c.ApplyAt, &c.AppliedAt, &c.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("StatusChangeRepository.ApplyDue failed to scan due change: %w", err)
+		}
+		due = append(due, c)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("StatusChangeRepository.ApplyDue failed to read due changes: %w", err)
+	}
+
+	applied := make([]int64, 0, len(due))
+	var applyErr error
+	for _, c := range due {
+		if err = apply(ctx, c); err != nil {
+			applyErr = errors.Join(applyErr, fmt.Errorf("status change %d: %w", c.ID, err))
+			continue
+		}
+		applied = append(applied, c.ID)
+	}
+
+	if len(applied) > 0 {
+		queryMark := r.sb.
+			Update("scheduled_status_changes").
+			Set("applied_at", now).
+			Where(sq.Eq{"id": applied})
+
+		if err = tryExec(ctx, "StatusChangeRepository.ApplyDue", queryMark, tx); err != nil {
+			return 0, fmt.Errorf("StatusChangeRepository.ApplyDue failed to mark changes as applied: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(applied), applyErr
+}
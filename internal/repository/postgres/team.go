@@ -35,7 +35,7 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, team entity.Team, membe
 		Columns("name", "created_at").
 		Values(team.Name, team.CreatedAt)
 
-	if err = tryExec(ctx, query, tx); err != nil {
+	if err = tryExec(ctx, "TeamRepository.CreateTeam", query, tx); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return apperror.ErrTeamExists
@@ -54,7 +54,7 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, team entity.Team, membe
 		queryAddUsers = queryAddUsers.Suffix("ON CONFLICT (id) DO UPDATE SET " +
 			"team_name = EXCLUDED.team_name, name = EXCLUDED.name, is_active = EXCLUDED.is_active")
 
-		if err = tryExec(ctx, queryAddUsers, tx); err != nil {
+		if err = tryExec(ctx, "TeamRepository.CreateTeam", queryAddUsers, tx); err != nil {
 			return fmt.Errorf("TeamRepository.CreateTeam failed to insert or update team members: %w", err)
 		}
 	}
@@ -64,14 +64,14 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, team entity.Team, membe
 
 func (r *TeamRepository) GetTeam(ctx context.Context, name string) (*entity.Team, []entity.User, error) {
 	query := r.sb.
-		Select("name", "created_at").
+		Select("name", "created_at", "max_open_reviews").
 		From("teams").
 		Where(sq.Eq{"name": name})
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "TeamRepository.GetTeam", query, r.pool)
 
 	var team entity.Team
-	if err := row.Scan(&team.Name, &team.CreatedAt); err != nil {
+	if err := row.Scan(&team.Name, &team.CreatedAt, &team.MaxOpenReviews); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil, apperror.ErrNotFound
 		}
@@ -79,11 +79,11 @@ func (r *TeamRepository) GetTeam(ctx context.Context, name string) (*entity.Team
 	}
 
 	queryUsers := r.sb.
-		Select("id", "team_name", "name", "is_active", "created_at").
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
 		From("users").
 		Where(sq.Eq{"team_name": name})
 
-	rows, err := tryQuery(ctx, queryUsers, r.pool)
+	rows, err := tryQuery(ctx, "TeamRepository.GetTeam", queryUsers, r.pool)
 	if err != nil {
 		return &team, nil, fmt.Errorf("TeamRepository.GetTeam failed to select team members: %w", err)
 	}
@@ -92,22 +92,147 @@ func (r *TeamRepository) GetTeam(ctx context.Context, name string) (*entity.Team
 	users := make([]entity.User, 0)
 	for rows.Next() {
 		var u entity.User
-		if err = rows.Scan(&u.ID, &u.TeamName, &u.Name, &u.IsActive, &u.CreatedAt); err != nil {
+		if err = rows.Scan(&u.ID, &u.TeamName, &u.Name, &u.IsActive, &u.CreatedAt, &u.MaxOpenReviews); err != nil {
 			return &team, nil, fmt.Errorf("TeamRepository.GetTeam failed to scan team member: %w", err)
 		}
 		users = append(users, u)
 	}
+	if err = rows.Err(); err != nil {
+		return &team, nil, fmt.Errorf("TeamRepository.GetTeam failed to select team members: %w", err)
+	}
 
 	return &team, users, nil
 }
 
+// ListMembers returns the team and one page of its members.
+func (r *TeamRepository) ListMembers(
+	ctx context.Context,
+	name string,
+	sort entity.Sort,
+	page entity.PageRequest,
+) (*entity.Team, entity.Page[entity.User], error) {
+	var (
+		key   sortKey
+		keyOf func(entity.User) (string, string)
+	)
+	switch sort.Field {
+	case "", entity.UserSortID:
+		key = textKey("id", "id", sort.Desc())
+		keyOf = func(u entity.User) (string, string) { return u.ID, u.ID }
+	case entity.UserSortName:
+		key = textKey("name", "id", sort.Desc())
+		keyOf = func(u entity.User) (string, string) { return u.Name, u.ID }
+	default:
+		return nil, entity.Page[entity.User]{}, fmt.Errorf(
+			"%w: unknown sort field %q", apperror.ErrInvalidListing, sort.Field)
+	}
+
+	query := r.sb.
+		Select("name", "created_at", "max_open_reviews").
+		From("teams").
+		Where(sq.Eq{"name": name})
+
+	row := tryQueryRow(ctx, "TeamRepository.ListMembers", query, r.pool)
+
+	var team entity.Team
+	if err := row.Scan(&team.Name, &team.CreatedAt, &team.MaxOpenReviews); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.Page[entity.User]{}, apperror.ErrNotFound
+		}
+		return nil, entity.Page[entity.User]{}, fmt.Errorf("TeamRepository.ListMembers failed to select team: %w", err)
+	}
+
+	queryUsers, err := paginate(r.sb.
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
+		From("users").
+		Where(sq.Eq{"team_name": name}), key, page)
+	if err != nil {
+		return nil, entity.Page[entity.User]{}, err
+	}
+
+	rows, err := tryQuery(ctx, "TeamRepository.ListMembers", queryUsers, r.pool)
+	if err != nil {
+		return nil, entity.Page[entity.User]{}, fmt.Errorf(
+			"TeamRepository.ListMembers failed to select team members: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]entity.User, 0)
+	for rows.Next() {
+		var u entity.User
+		if err = rows.Scan(&u.ID, &u.TeamName, &u.Name, &u.IsActive, &u.CreatedAt, &u.MaxOpenReviews); err != nil {
+			return nil, entity.Page[entity.User]{}, fmt.Errorf(
+				"TeamRepository.ListMembers failed to scan team member: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, entity.Page[entity.User]{}, fmt.Errorf(
+			"TeamRepository.ListMembers failed to select team members: %w", err)
+	}
+
+	return &team, trimPage(users, page.Limit, keyOf), nil
+}
+
+// SetMaxOpenReviews sets the limit of concurrent open reviews of the team
+// members, nil removes it.
+func (r *TeamRepository) SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) error {
+	query := r.sb.
+		Update("teams").
+		Set("max_open_reviews", limit).
+		Where(sq.Eq{"name": teamName})
+
+	affected, err := tryExecAffected(ctx, "TeamRepository.SetMaxOpenReviews", query, r.pool)
+	if err != nil {
+		return fmt.Errorf("TeamRepository.SetMaxOpenReviews failed to update team: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListLoad returns the review load of every team ordered by name.
+func (r *TeamRepository) ListLoad(ctx context.Context) ([]entity.TeamLoad, error) {
+	query := r.sb.
+		Select("t.name").
+		Column(sq.Expr("(SELECT COUNT(*) FROM prs p JOIN users a ON a.id = p.author_id "+
+			"WHERE a.team_name = t.name AND p.status = ?)", entity.PRStatusOpen)).
+		Column(sq.Expr("(SELECT COUNT(*) FROM users u WHERE u.team_name = t.name AND u.is_active "+
+			"AND NOT EXISTS (SELECT 1 FROM pr_reviewers r JOIN prs p ON p.id = r.pr_id "+
+			"WHERE r.reviewer_id = u.id AND p.status = ?))", entity.PRStatusOpen)).
+		From("teams t").
+		OrderBy("t.name")
+
+	rows, err := tryQuery(ctx, "TeamRepository.ListLoad", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("TeamRepository.ListLoad failed to select team load: %w", err)
+	}
+	defer rows.Close()
+
+	loads := make([]entity.TeamLoad, 0)
+	for rows.Next() {
+		var l entity.TeamLoad
+		if err = rows.Scan(&l.TeamName, &l.OpenPRs, &l.IdleReviewers); err != nil {
+			return nil, fmt.Errorf("TeamRepository.ListLoad failed to scan team load: %w", err)
+		}
+		loads = append(loads, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("TeamRepository.ListLoad failed to select team load: %w", err)
+	}
+
+	return loads, nil
+}
+
 func (r *TeamRepository) GetTeamForUser(ctx context.Context, userID string) (string, error) {
 	query := r.sb.
 		Select("team_name").
 		From("users").
 		Where(sq.Eq{"id": userID})
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "TeamRepository.GetTeamForUser", query, r.pool)
 
 	var teamName string
 	if err := row.Scan(&teamName); err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -27,12 +28,13 @@ func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActiv
 		Update("users").
 		Set("is_active", isActive).
 		Where(sq.Eq{"id": userID}).
-		Suffix("RETURNING id, name, team_name, is_active, created_at")
+		Suffix("RETURNING id, name, team_name, is_active, created_at, max_open_reviews")
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "UserRepository.SetIsActive", query, r.pool)
 
 	var user entity.User
-	if err := row.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.CreatedAt); err != nil {
+	err := row.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.CreatedAt, &user.MaxOpenReviews)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrNotFound
 		}
@@ -42,16 +44,53 @@ func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActiv
 	return &user, nil
 }
 
-func (r *UserRepository) ListAssignedTo(ctx context.Context, userID string) ([]entity.PR, error) {
+// SetMaxOpenReviews sets the user's own limit of concurrent open reviews, nil
+// falls back to the team limit.
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*entity.User, error) {
+	query := r.sb.
+		Update("users").
+		Set("max_open_reviews", limit).
+		Where(sq.Eq{"id": userID}).
+		Suffix("RETURNING id, name, team_name, is_active, created_at, max_open_reviews")
+
+	row := tryQueryRow(ctx, "UserRepository.SetMaxOpenReviews", query, r.pool)
+
+	var user entity.User
+	err := row.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.CreatedAt, &user.MaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("UserRepository.SetMaxOpenReviews failed to update user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) ListAssignedTo(
+	ctx context.Context,
+	userID string,
+	opts entity.PRListOptions,
+) (entity.Page[entity.PR], error) {
+	key, keyOf, err := prSortKey("p", opts.Sort)
+	if err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
 	query := r.sb.
 		Select("p.id", "p.title", "p.author_id", "p.status", "p.created_at", "p.merged_at").
 		From("prs p").
 		Join("pr_reviewers r ON p.id = r.pr_id").
 		Where(sq.Eq{"r.reviewer_id": userID})
+	query = applyPRFilter(query, "p", opts.Filter)
+	if query, err = paginate(query, key, opts.Page); err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
 
-	rows, err := tryQuery(ctx, query, r.pool)
+	rows, err := tryQuery(ctx, "UserRepository.ListAssignedTo", query, r.pool)
 	if err != nil {
-		return nil, fmt.Errorf("UserRepository.ListAssignedTo failed to select assigned PRs: %w", err)
+		return entity.Page[entity.PR]{}, fmt.Errorf(
+			"UserRepository.ListAssignedTo failed to select assigned PRs: %w", err)
 	}
 	defer rows.Close()
 
@@ -59,12 +98,17 @@ func (r *UserRepository) ListAssignedTo(ctx context.Context, userID string) ([]e
 	for rows.Next() {
 		var pr entity.PR
 		if err = rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
-			return nil, fmt.Errorf("UserRepository.ListAssignedTo failed to scan assigned PR: %w", err)
+			return entity.Page[entity.PR]{}, fmt.Errorf(
+				"UserRepository.ListAssignedTo failed to scan assigned PR: %w", err)
 		}
 		prs = append(prs, pr)
 	}
+	if err = rows.Err(); err != nil {
+		return entity.Page[entity.PR]{}, fmt.Errorf(
+			"UserRepository.ListAssignedTo failed to select assigned PRs: %w", err)
+	}
 
-	return prs, nil
+	return trimPage(prs, opts.Page.Limit, keyOf), nil
 }
 
 func (r *UserRepository) IsAssignedToPR(ctx context.Context, userID, prID string) (bool, error) {
@@ -74,7 +118,7 @@ func (r *UserRepository) IsAssignedToPR(ctx context.Context, userID, prID string
 		Where(sq.Eq{"pr_id": prID, "reviewer_id": userID}).
 		Suffix(")")
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "UserRepository.IsAssignedToPR", query, r.pool)
 
 	var exists bool
 	if err := row.Scan(&exists); err != nil {
@@ -86,14 +130,15 @@ func (r *UserRepository) IsAssignedToPR(ctx context.Context, userID, prID string
 
 func (r *UserRepository) GetByID(ctx context.Context, userID string) (*entity.User, error) {
 	query := r.sb.
-		Select("id", "team_name", "name", "is_active", "created_at").
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
 		From("users").
 		Where(sq.Eq{"id": userID})
 
-	row := tryQueryRow(ctx, query, r.pool)
+	row := tryQueryRow(ctx, "UserRepository.GetByID", query, r.pool)
 
 	var user entity.User
-	if err := row.Scan(&user.ID, &user.TeamName, &user.Name, &user.IsActive, &user.CreatedAt); err != nil {
+	err := row.Scan(&user.ID, &user.TeamName, &user.Name, &user.IsActive, &user.CreatedAt, &user.MaxOpenReviews)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrNotFound
 		}
@@ -102,3 +147,40 @@ func (r *UserRepository) GetByID(ctx context.Context, userID string) (*entity.Us
 
 	return &user, nil
 }
+
+// ListTeamStatusHistory returns changes of is_active of the team members made
+// in [from, to), oldest first.
+func (r *UserRepository) ListTeamStatusHistory(
+	ctx context.Context,
+	teamName string,
+	from, to time.Time,
+) ([]entity.StatusHistoryEntry, error) {
+	query := r.sb.
+		Select("h.user_id", "h.is_active", "h.changed_at").
+		From("user_status_history h").
+		Join("users u ON u.id = h.user_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.GtOrEq{"h.changed_at": from}).
+		Where(sq.Lt{"h.changed_at": to}).
+		OrderBy("h.changed_at", "h.id")
+
+	rows, err := tryQuery(ctx, "UserRepository.ListTeamStatusHistory", query, r.pool)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepository.ListTeamStatusHistory failed to select status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]entity.StatusHistoryEntry, 0)
+	for rows.Next() {
+		var h entity.StatusHistoryEntry
+		if err = rows.Scan(&h.UserID, &h.IsActive, &h.ChangedAt); err != nil {
+			return nil, fmt.Errorf("UserRepository.ListTeamStatusHistory failed to scan status history: %w", err)
+		}
+		history = append(history, h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("UserRepository.ListTeamStatusHistory failed to select status history: %w", err)
+	}
+
+	return history, nil
+}
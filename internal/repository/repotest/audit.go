@@ -0,0 +1,63 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func auditCases() []testCase {
+	return []testCase{
+		{"Audit/RecordAndList", testAuditRecordAndList},
+		{"Audit/RecordMissing", testAuditRecordMissing},
+	}
+}
+
+func testAuditRecordAndList(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "u1", time.Now())
+
+	added := entity.NewReviewerAuditEntry("pr-1", "u2", entity.ReviewerAuditAdded, "lead")
+	removed := entity.NewReviewerAuditEntry("pr-1", "u2", entity.ReviewerAuditRemoved, "")
+	removed.CreatedAt = added.CreatedAt.Add(time.Minute)
+
+	// recorded out of order, listed by time
+	got, err := r.Audit.Record(t.Context(), *removed)
+	noErr(t, err)
+	if got.ID == 0 {
+		t.Fatal("recorded entry has no id")
+	}
+	_, err = r.Audit.Record(t.Context(), *added)
+	noErr(t, err)
+	_, err = r.Audit.Record(t.Context(), *entity.NewReviewerAuditEntry("pr-2", "u3", entity.ReviewerAuditAdded, ""))
+	noErr(t, err)
+
+	entries, err := r.Audit.ListForPR(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "entries", len(entries), 2)
+	equal(t, "first action", entries[0].Action, entity.ReviewerAuditAdded)
+	equal(t, "first actor", entries[0].Actor, "lead")
+	equal(t, "first reviewer", entries[0].ReviewerID, "u2")
+	sameTime(t, "first created_at", entries[0].CreatedAt, added.CreatedAt)
+	equal(t, "second action", entries[1].Action, entity.ReviewerAuditRemoved)
+	equal(t, "second actor", entries[1].Actor, "")
+
+	noErr(t, r.PR.DeleteByID(t.Context(), "pr-1"))
+	entries, err = r.Audit.ListForPR(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "entries of deleted PR", len(entries), 0)
+}
+
+func testAuditRecordMissing(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+
+	_, err := r.Audit.Record(t.Context(), *entity.NewReviewerAuditEntry("missing", "u2", entity.ReviewerAuditAdded, ""))
+	wantErr(t, err, apperror.ErrNotFound)
+
+	_, err = r.Audit.Record(t.Context(), *entity.NewReviewerAuditEntry("pr-1", "missing", entity.ReviewerAuditAdded, ""))
+	wantErr(t, err, apperror.ErrNotFound)
+}
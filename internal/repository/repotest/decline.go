@@ -0,0 +1,107 @@
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func declineCases() []testCase {
+	return []testCase{
+		{"Decline/RecordAndList", testDeclineRecordAndList},
+		{"Decline/RecordRejected", testDeclineRecordRejected},
+	}
+}
+
+func testDeclineRecordAndList(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "u1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u3"))
+
+	first := entity.NewDecline("pr-1", "u2", entity.DeclineReasonConflictOfInterest, "my own module", "u3")
+	second := entity.NewDecline("pr-1", "u3", entity.DeclineReasonNoTime, "", "f1")
+	first.CreatedAt = second.CreatedAt.Add(time.Minute)
+
+	// recorded out of order, listed by time
+	got, err := r.Decline.Record(t.Context(), *first)
+	noErr(t, err)
+	if got.ID == 0 {
+		t.Fatal("recorded decline has no id")
+	}
+	_, err = r.Decline.Record(t.Context(), *second)
+	noErr(t, err)
+	_, err = r.Decline.Record(t.Context(), *entity.NewDecline("pr-2", "u3", entity.DeclineReasonOther, "", "u2"))
+	noErr(t, err)
+
+	declines, err := r.Decline.ListForPR(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "declines", len(declines), 2)
+	equal(t, "first reviewer", declines[0].ReviewerID, "u3")
+	equal(t, "first comment", declines[0].Comment, "")
+	equal(t, "first new reviewer", declines[0].NewReviewerID, "f1")
+	sameTime(t, "first created_at", declines[0].CreatedAt, second.CreatedAt)
+	equal(t, "second reviewer", declines[1].ReviewerID, "u2")
+	equal(t, "second reason", declines[1].Reason, entity.DeclineReasonConflictOfInterest)
+	equal(t, "second comment", declines[1].Comment, "my own module")
+
+	// the review is handed over and decliners are remembered as removed
+	reviewers, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "reviewers", len(reviewers), 1)
+	equal(t, "reviewer", reviewers[0], "f1")
+	removed, err := r.PR.ListRemovedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "removed", len(removed), 2)
+
+	noErr(t, r.PR.DeleteByID(t.Context(), "pr-1"))
+	declines, err = r.Decline.ListForPR(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "declines of deleted PR", len(declines), 0)
+}
+
+func testDeclineRecordRejected(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "u1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u3"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u2"))
+	_, err := r.PR.UpdateStatus(t.Context(), "pr-2", entity.PRStatusMerged)
+	noErr(t, err)
+
+	cases := []struct {
+		name    string
+		decline *entity.Decline
+		want    error
+	}{
+		{"missing PR", entity.NewDecline("missing", "u2", entity.DeclineReasonOther, "", "f1"), apperror.ErrNotAssigned},
+		{"not assigned", entity.NewDecline("pr-1", "f2", entity.DeclineReasonOther, "", "f1"), apperror.ErrNotAssigned},
+		{"merged PR", entity.NewDecline("pr-2", "u2", entity.DeclineReasonOther, "", "f1"), apperror.ErrNotAssigned},
+		{"missing replacement", entity.NewDecline("pr-1", "u2", entity.DeclineReasonOther, "", "missing"),
+			apperror.ErrNotFound},
+		{"assigned replacement", entity.NewDecline("pr-1", "u2", entity.DeclineReasonOther, "", "u3"),
+			apperror.ErrReviewerExists},
+	}
+	for _, tc := range cases {
+		_, err = r.Decline.Record(t.Context(), *tc.decline)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: got error %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	// nothing of a rejected decline is stored
+	declines, err := r.Decline.ListForPR(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "declines", len(declines), 0)
+	reviewers, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "reviewers", len(reviewers), 2)
+	removed, err := r.PR.ListRemovedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "removed", len(removed), 0)
+}
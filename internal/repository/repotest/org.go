@@ -0,0 +1,170 @@
+package repotest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func orgCases() []testCase {
+	return []testCase{
+		{"Org/List", testOrgList},
+		{"Org/ApplyPlan", testOrgApplyPlan},
+		{"Org/ApplyPlanIsAtomic", testOrgApplyPlanIsAtomic},
+		{"Org/ApplyPlanReassigns", testOrgApplyPlanReassigns},
+	}
+}
+
+func orgTeamNames(teams []entity.OrgTeam) []string {
+	names := make([]string, 0, len(teams))
+	for _, t := range teams {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func testOrgList(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	noErr(t, r.Team.CreateTeam(t.Context(), *entity.NewTeam("empty"), nil))
+
+	teams, err := r.Org.ListOrg(t.Context())
+	noErr(t, err)
+	if names := orgTeamNames(teams); !slices.Equal(names, []string{"backend", "empty", "frontend"}) {
+		t.Fatalf("teams = %v, want them ordered by name", names)
+	}
+
+	ids := make([]string, 0)
+	for _, u := range teams[0].Members {
+		equal(t, "member team", u.TeamName, "backend")
+		ids = append(ids, u.ID)
+	}
+	if !slices.Equal(ids, []string{"u1", "u2", "u3", "u4"}) {
+		t.Fatalf("backend members = %v, want them ordered by id", ids)
+	}
+	equal(t, "empty team members", len(teams[1].Members), 0)
+}
+
+func testOrgApplyPlan(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	limit := 3
+	_, err := r.User.SetMaxOpenReviews(t.Context(), "f1", &limit)
+	noErr(t, err)
+
+	u2, err := r.User.GetByID(t.Context(), "u2")
+	noErr(t, err)
+	f1, err := r.User.GetByID(t.Context(), "f1")
+	noErr(t, err)
+	f2, err := r.User.GetByID(t.Context(), "f2")
+	noErr(t, err)
+
+	movedF1, movedF2, inactiveU2 := *f1, *f2, *u2
+	movedF1.TeamName, movedF1.Name = "platform", "Frank F."
+	movedF2.TeamName = "platform"
+	inactiveU2.IsActive = false
+	plan := entity.OrgPlan{
+		AddTeams:    []string{"platform"},
+		RemoveTeams: []string{"frontend"},
+		Users: []entity.UserChange{
+			{After: *entity.NewUser("p1", "Paula", "platform", true)},
+			{Before: f1, After: movedF1},
+			{Before: f2, After: movedF2},
+			{Before: u2, After: inactiveU2},
+		},
+	}
+	noErr(t, r.Org.ApplyOrgPlan(t.Context(), plan))
+
+	teams, err := r.Org.ListOrg(t.Context())
+	noErr(t, err)
+	if names := orgTeamNames(teams); !slices.Equal(names, []string{"backend", "platform"}) {
+		t.Fatalf("teams = %v, want backend and platform", names)
+	}
+
+	got, err := r.User.GetByID(t.Context(), "f1")
+	noErr(t, err)
+	equal(t, "f1 team", got.TeamName, "platform")
+	equal(t, "f1 name", got.Name, "Frank F.")
+	if got.MaxOpenReviews == nil || *got.MaxOpenReviews != limit {
+		t.Fatalf("f1 max open reviews = %v, want the own limit kept", got.MaxOpenReviews)
+	}
+	sameTime(t, "f1 created at", got.CreatedAt, f1.CreatedAt)
+
+	got, err = r.User.GetByID(t.Context(), "u2")
+	noErr(t, err)
+	equal(t, "u2 active", got.IsActive, false)
+	equal(t, "u2 team", got.TeamName, "backend")
+
+	got, err = r.User.GetByID(t.Context(), "p1")
+	noErr(t, err)
+	equal(t, "p1 team", got.TeamName, "platform")
+
+	_, _, err = r.Team.GetTeam(t.Context(), "frontend")
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testOrgApplyPlanIsAtomic(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	plan := entity.OrgPlan{
+		AddTeams: []string{"platform", "backend"},
+		Users:    []entity.UserChange{{After: *entity.NewUser("p1", "Paula", "platform", true)}},
+	}
+	wantErr(t, r.Org.ApplyOrgPlan(t.Context(), plan), apperror.ErrTeamExists)
+
+	_, _, err := r.Team.GetTeam(t.Context(), "platform")
+	wantErr(t, err, apperror.ErrNotFound)
+	_, err = r.User.GetByID(t.Context(), "p1")
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testOrgApplyPlanReassigns(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	_, err := r.User.SetIsActive(t.Context(), "u4", true)
+	noErr(t, err)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u3"))
+
+	u2, err := r.User.GetByID(t.Context(), "u2")
+	noErr(t, err)
+	inactiveU2 := *u2
+	inactiveU2.IsActive = false
+	plan := entity.OrgPlan{
+		Users: []entity.UserChange{{Before: u2, After: inactiveU2}},
+		Reassign: []entity.Reassignment{{
+			OpenReview: entity.OpenReview{PRID: "pr-1", ReviewerID: "u2", TeamName: "backend"},
+			ReplacedBy: "u4",
+		}},
+	}
+	noErr(t, r.Org.ApplyOrgPlan(t.Context(), plan))
+
+	assigned, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	sameSet(t, "reviewers", assigned, []string{"u3", "u4"})
+	removed, err := r.PR.ListRemovedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	sameSet(t, "removed reviewers", removed, []string{"u2"})
+
+	// a plan built before the reviewers changed is not applied at all
+	stale := entity.OrgPlan{
+		AddTeams: []string{"platform"},
+		Reassign: []entity.Reassignment{{
+			OpenReview: entity.OpenReview{PRID: "pr-1", ReviewerID: "u2", TeamName: "backend"},
+			ReplacedBy: "u1",
+		}},
+	}
+	wantErr(t, r.Org.ApplyOrgPlan(t.Context(), stale), apperror.ErrNotAssigned)
+	_, _, err = r.Team.GetTeam(t.Context(), "platform")
+	wantErr(t, err, apperror.ErrNotFound)
+
+	stale.Reassign = []entity.Reassignment{{
+		OpenReview: entity.OpenReview{PRID: "pr-1", ReviewerID: "u3", TeamName: "backend"},
+		ReplacedBy: "u4",
+	}}
+	wantErr(t, r.Org.ApplyOrgPlan(t.Context(), stale), apperror.ErrReviewerExists)
+	assigned, err = r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	sameSet(t, "reviewers after a failed plan", assigned, []string{"u3", "u4"})
+}
@@ -0,0 +1,401 @@
+package repotest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func prCases() []testCase {
+	return []testCase{
+		{"PR/CreateAndGet", testPRCreateAndGet},
+		{"PR/CreateDuplicate", testPRCreateDuplicate},
+		{"PR/CreateMissingAuthor", testPRCreateMissingAuthor},
+		{"PR/Missing", testPRMissing},
+		{"PR/UpdateStatusSetsMergedAt", testPRUpdateStatusSetsMergedAt},
+		{"PR/AddReviewer", testPRAddReviewer},
+		{"PR/AddReviewerMissing", testPRAddReviewerMissing},
+		{"PR/AddReviewerWithLimit", testPRAddReviewerWithLimit},
+		{"PR/RemoveReviewer", testPRRemoveReviewer},
+		{"PR/RemovedReviewers", testPRRemovedReviewers},
+		{"PR/DeleteCascades", testPRDeleteCascades},
+		{"PR/ListFilters", testPRListFilters},
+		{"PR/ListPages", testPRListPages},
+		{"PR/GetReviewersForPRs", testPRGetReviewersForPRs},
+		{"PR/GetReviewerDetails", testPRGetReviewerDetails},
+		{"PR/CountAssignments", testPRCountAssignments},
+		{"PR/CountOpenReviews", testPRCountOpenReviews},
+	}
+}
+
+func testPRCreateAndGet(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	created := createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+
+	pr, err := r.PR.GetByID(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "title", pr.Title, "Add search")
+	equal(t, "author", pr.AuthorID, "u1")
+	equal(t, "status", pr.Status, entity.PRStatusOpen)
+	sameTime(t, "created_at", pr.CreatedAt, created.CreatedAt)
+	if pr.MergedAt != nil {
+		t.Fatalf("merged_at = %v, want nil", pr.MergedAt)
+	}
+
+	reviewers, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "reviewers", len(reviewers), 0)
+}
+
+func testPRCreateDuplicate(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+
+	err := r.PR.Create(t.Context(), *entity.NewPR("pr-1", "Other", "u2"))
+	wantErr(t, err, apperror.ErrPRExists)
+}
+
+func testPRCreateMissingAuthor(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	err := r.PR.Create(t.Context(), *entity.NewPR("pr-1", "Add search", "missing"))
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testPRMissing(t *testing.T, r Repositories) {
+	_, err := r.PR.GetByID(t.Context(), "missing")
+	wantErr(t, err, apperror.ErrNotFound)
+
+	_, err = r.PR.UpdateStatus(t.Context(), "missing", entity.PRStatusMerged)
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testPRUpdateStatusSetsMergedAt(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now().Add(-time.Hour))
+
+	pr, err := r.PR.UpdateStatus(t.Context(), "pr-1", entity.PRStatusMerged)
+	noErr(t, err)
+	equal(t, "status", pr.Status, entity.PRStatusMerged)
+	if pr.MergedAt == nil {
+		t.Fatal("merged_at is not set after merge")
+	}
+	if d := time.Since(*pr.MergedAt); d < -time.Minute || d > time.Minute {
+		t.Fatalf("merged_at = %s, want about now", pr.MergedAt)
+	}
+
+	again, err := r.PR.UpdateStatus(t.Context(), "pr-1", entity.PRStatusMerged)
+	noErr(t, err)
+	if again.MergedAt == nil {
+		t.Fatal("merged_at is reset by repeated merge")
+	}
+	sameTime(t, "merged_at after repeated merge", *again.MergedAt, *pr.MergedAt)
+}
+
+func testPRAddReviewer(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	// reviewers may come from another team than the author's
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "f1"))
+	wantErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"), apperror.ErrReviewerExists)
+
+	stored, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	sameSet(t, "stored reviewers", stored, []string{"u2", "f1"})
+}
+
+func testPRAddReviewerMissing(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+
+	wantErr(t, r.PR.AddReviewer(t.Context(), "missing", "u2"), apperror.ErrNotFound)
+	wantErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "missing"), apperror.ErrNotFound)
+}
+
+func testPRAddReviewerWithLimit(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix search", "u1", time.Now())
+
+	wantErr(t, r.PR.AddReviewerWithLimit(t.Context(), "missing", "u2", 2), apperror.ErrNotFound)
+	wantErr(t, r.PR.AddReviewerWithLimit(t.Context(), "pr-1", "missing", 2), apperror.ErrNotFound)
+	noErr(t, r.PR.AddReviewerWithLimit(t.Context(), "pr-1", "u2", 2))
+	wantErr(t, r.PR.AddReviewerWithLimit(t.Context(), "pr-1", "u2", 2), apperror.ErrReviewerExists)
+	noErr(t, r.PR.AddReviewerWithLimit(t.Context(), "pr-1", "u3", 2))
+	wantErr(t, r.PR.AddReviewerWithLimit(t.Context(), "pr-1", "f1", 2), apperror.ErrReviewersFull)
+
+	// concurrent calls on the same PR never exceed the limit together
+	var wg sync.WaitGroup
+	errs := make([]error, 0, 4)
+	var mu sync.Mutex
+	for _, reviewerID := range []string{"u2", "u3", "f1", "f2"} {
+		wg.Go(func() {
+			err := r.PR.AddReviewerWithLimit(t.Context(), "pr-2", reviewerID, 2)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	added := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case !errors.Is(err, apperror.ErrReviewersFull):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	equal(t, "added concurrently", added, 2)
+	reviewers, err := r.PR.GetAssignedReviewers(t.Context(), "pr-2")
+	noErr(t, err)
+	equal(t, "reviewers", len(reviewers), 2)
+}
+
+func testPRRemoveReviewer(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "f1", time.Now())
+	reviewerID := "f2"
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", reviewerID))
+
+	noErr(t, r.PR.RemoveReviewer(t.Context(), "pr-1", reviewerID))
+	reviewers, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "reviewers after remove", len(reviewers), 0)
+
+	// removing a reviewer that is not assigned is a no-op
+	noErr(t, r.PR.RemoveReviewer(t.Context(), "pr-1", reviewerID))
+
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", reviewerID))
+}
+
+func testPRRemovedReviewers(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "u1", time.Now())
+
+	removed, err := r.PR.ListRemovedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "removed before any removal", len(removed), 0)
+
+	noErr(t, r.PR.MarkReviewerRemoved(t.Context(), "pr-1", "u3"))
+	noErr(t, r.PR.MarkReviewerRemoved(t.Context(), "pr-1", "u2"))
+	// marking twice keeps a single record
+	noErr(t, r.PR.MarkReviewerRemoved(t.Context(), "pr-1", "u3"))
+	noErr(t, r.PR.MarkReviewerRemoved(t.Context(), "pr-2", "f1"))
+
+	removed, err = r.PR.ListRemovedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "removed", len(removed), 2)
+	equal(t, "first removed", removed[0], "u2")
+	equal(t, "second removed", removed[1], "u3")
+
+	wantErr(t, r.PR.MarkReviewerRemoved(t.Context(), "missing", "u2"), apperror.ErrNotFound)
+	wantErr(t, r.PR.MarkReviewerRemoved(t.Context(), "pr-1", "missing"), apperror.ErrNotFound)
+
+	noErr(t, r.PR.DeleteByID(t.Context(), "pr-1"))
+	removed, err = r.PR.ListRemovedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "removed of deleted PR", len(removed), 0)
+}
+
+func testPRDeleteCascades(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	reviewerID := "u2"
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", reviewerID))
+	noErr(t, r.SLA.SetTeamSLA(t.Context(), "backend", ptr(time.Minute)))
+	overdue, err := r.SLA.ListOverdueAssignments(t.Context(), time.Now().Add(time.Hour))
+	noErr(t, err)
+	equal(t, "overdue", len(overdue), 1)
+	_, _, err = r.SLA.RecordEscalation(t.Context(), escalationOf(overdue[0]))
+	noErr(t, err)
+
+	noErr(t, r.PR.DeleteByID(t.Context(), "pr-1"))
+
+	_, err = r.PR.GetByID(t.Context(), "pr-1")
+	wantErr(t, err, apperror.ErrNotFound)
+	reviewers, err := r.PR.GetAssignedReviewers(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "reviewers of deleted PR", len(reviewers), 0)
+	assigned, err := r.User.IsAssignedToPR(t.Context(), reviewerID, "pr-1")
+	noErr(t, err)
+	equal(t, "assigned to deleted PR", assigned, false)
+	escalations, err := r.SLA.ListOpenEscalations(t.Context())
+	noErr(t, err)
+	equal(t, "escalations of deleted PR", len(escalations), 0)
+
+	// reviewers and authors survive the PR, so the id can be reused
+	createPR(t, r, "pr-1", "Add search again", "u1", time.Now())
+	_, err = r.User.GetByID(t.Context(), reviewerID)
+	noErr(t, err)
+}
+
+func testPRListFilters(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	base := time.Now().Add(-time.Hour)
+	createPR(t, r, "pr-1", "Add search", "u1", base)
+	createPR(t, r, "pr-2", "Fix 100% CPU usage", "u2", base.Add(time.Minute))
+	createPR(t, r, "pr-3", "Frontend search page", "f1", base.Add(2*time.Minute))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-3", "f2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-3", "u3"))
+	merged, err := r.PR.UpdateStatus(t.Context(), "pr-2", entity.PRStatusMerged)
+	noErr(t, err)
+
+	cases := []struct {
+		name   string
+		filter entity.PRFilter
+		want   []string
+	}{
+		{"no filter", entity.PRFilter{}, []string{"pr-1", "pr-2", "pr-3"}},
+		{"team", entity.PRFilter{TeamName: "backend"}, []string{"pr-1", "pr-2"}},
+		{"author", entity.PRFilter{AuthorID: "f1"}, []string{"pr-3"}},
+		{"reviewer", entity.PRFilter{ReviewerID: "u2"}, []string{"pr-1"}},
+		{"status", entity.PRFilter{Status: entity.PRStatusMerged}, []string{"pr-2"}},
+		{"title ignores case", entity.PRFilter{TitleContains: "SEARCH"}, []string{"pr-1", "pr-3"}},
+		{"title matches wildcards literally", entity.PRFilter{TitleContains: "0%"}, []string{"pr-2"}},
+		{"title underscore", entity.PRFilter{TitleContains: "_"}, nil},
+		{"created from", entity.PRFilter{CreatedFrom: ptr(base.Add(time.Minute))}, []string{"pr-2", "pr-3"}},
+		{"created to", entity.PRFilter{CreatedTo: ptr(base.Add(time.Minute))}, []string{"pr-1"}},
+		{"merged from", entity.PRFilter{MergedFrom: ptr(merged.MergedAt.Add(-time.Minute))}, []string{"pr-2"}},
+		{"merged to", entity.PRFilter{MergedTo: ptr(merged.MergedAt.Add(-time.Minute))}, nil},
+		{"fewer reviewers", entity.PRFilter{FewerReviewersThan: ptr(2)}, []string{"pr-1", "pr-2"}},
+		{"fewer reviewers than one", entity.PRFilter{FewerReviewersThan: ptr(1)}, []string{"pr-2"}},
+		{"combined", entity.PRFilter{TeamName: "backend", Status: entity.PRStatusOpen}, []string{"pr-1"}},
+	}
+	for _, tc := range cases {
+		page, err := r.PR.List(t.Context(), entity.PRListOptions{Filter: tc.filter})
+		noErr(t, err)
+		sameSet(t, tc.name, prIDs(page.Items), tc.want)
+	}
+}
+
+func testPRListPages(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	base := time.Now().Add(-time.Hour)
+	titles := map[string]string{"pr-1": "b", "pr-2": "a", "pr-3": "c", "pr-4": "a"}
+	for i, id := range []string{"pr-1", "pr-2", "pr-3", "pr-4"} {
+		createPR(t, r, id, titles[id], "u1", base.Add(time.Duration(i)*time.Minute))
+	}
+
+	opts := entity.PRListOptions{
+		Sort: entity.Sort{Field: entity.PRSortTitle, Order: entity.SortAsc},
+		Page: entity.PageRequest{Limit: 3},
+	}
+	first, err := r.PR.List(t.Context(), opts)
+	noErr(t, err)
+	sameOrder(t, "first page", prIDs(first.Items), []string{"pr-2", "pr-4", "pr-1"})
+	if first.NextCursor == "" {
+		t.Fatal("first page has no next cursor")
+	}
+
+	opts.Page.Cursor = first.NextCursor
+	second, err := r.PR.List(t.Context(), opts)
+	noErr(t, err)
+	sameOrder(t, "second page", prIDs(second.Items), []string{"pr-3"})
+	equal(t, "last page cursor", second.NextCursor, "")
+
+	_, err = r.PR.List(t.Context(), entity.PRListOptions{Sort: entity.Sort{Field: "author"}})
+	wantErr(t, err, apperror.ErrInvalidListing)
+	_, err = r.PR.List(t.Context(), entity.PRListOptions{Page: entity.PageRequest{Cursor: "not a cursor"}})
+	wantErr(t, err, apperror.ErrInvalidListing)
+}
+
+func testPRGetReviewersForPRs(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "f1", time.Now())
+	createPR(t, r, "pr-3", "No reviewers", "u1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "f2"))
+
+	reviewers, err := r.PR.GetReviewersForPRs(t.Context(), []string{"pr-1", "pr-2", "pr-3", "missing"})
+	noErr(t, err)
+	sameSet(t, "pr-1", reviewers["pr-1"], []string{"u2"})
+	sameSet(t, "pr-2", reviewers["pr-2"], []string{"f2"})
+	equal(t, "pr-3", len(reviewers["pr-3"]), 0)
+
+	empty, err := r.PR.GetReviewersForPRs(t.Context(), nil)
+	noErr(t, err)
+	equal(t, "no PRs", len(empty), 0)
+}
+
+func testPRGetReviewerDetails(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "f2"))
+
+	reviewers, err := r.PR.GetReviewerDetails(t.Context(), "pr-1")
+	noErr(t, err)
+	equal(t, "reviewers", len(reviewers), 1)
+	rv := reviewers[0]
+	equal(t, "id", rv.ID, "f2")
+	equal(t, "name", rv.Name, "Grace")
+	equal(t, "team", rv.TeamName, "frontend")
+	equal(t, "active", rv.IsActive, true)
+	if d := time.Since(rv.AssignedAt); d < -time.Minute || d > time.Minute {
+		t.Fatalf("assigned_at = %s, want about now", rv.AssignedAt)
+	}
+
+	none, err := r.PR.GetReviewerDetails(t.Context(), "missing")
+	noErr(t, err)
+	equal(t, "reviewers of missing PR", len(none), 0)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testPRCountAssignments(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "f1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "f2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u3"))
+	_, err := r.PR.UpdateStatus(t.Context(), "pr-2", entity.PRStatusMerged)
+	noErr(t, err)
+
+	counts, err := r.PR.CountAssignments(t.Context(), "backend", time.Now().Add(-time.Hour))
+	noErr(t, err)
+	equal(t, "backend reviewers", len(counts), 2)
+	equal(t, "u2 assignments", counts["u2"], 2)
+	equal(t, "u3 assignments", counts["u3"], 1)
+
+	counts, err = r.PR.CountAssignments(t.Context(), "frontend", time.Now().Add(-time.Hour))
+	noErr(t, err)
+	equal(t, "frontend reviewers", len(counts), 1)
+	equal(t, "f2 assignments", counts["f2"], 1)
+
+	counts, err = r.PR.CountAssignments(t.Context(), "backend", time.Now().Add(time.Hour))
+	noErr(t, err)
+	equal(t, "assignments before window", len(counts), 0)
+}
+
+func testPRCountOpenReviews(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "f1", time.Now())
+	createPR(t, r, "pr-3", "Bump deps", "u3", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "f2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-3", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-3", "u1"))
+	_, err := r.PR.UpdateStatus(t.Context(), "pr-3", entity.PRStatusMerged)
+	noErr(t, err)
+
+	counts, err := r.PR.CountOpenReviews(t.Context(), "backend")
+	noErr(t, err)
+	equal(t, "backend reviewers", len(counts), 1)
+	equal(t, "u2 open reviews", counts["u2"], 2)
+}
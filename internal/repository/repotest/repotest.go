@@ -0,0 +1,137 @@
+// Package repotest is a conformance suite for storage backends. Every backend
+// runs the same cases against the repository interfaces of the usecase package,
+// so behavior that callers rely on (error mapping, reviewer selection rules,
+// cascades) stays identical between implementations.
+package repotest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+// timeTolerance absorbs precision loss of storages, e.g. microseconds in Postgres.
+const timeTolerance = time.Millisecond
+
+// Repositories is the set of repositories a storage backend provides.
+type Repositories struct {
+	PR           usecase.PRRepository
+	Team         usecase.TeamRepository
+	User         usecase.UserRepository
+	Schedule     usecase.ScheduleRepository
+	StatusChange usecase.StatusChangeRepository
+	SLA          usecase.SLARepository
+	Audit        usecase.ReviewerAuditRepository
+	Decline      usecase.DeclineRepository
+	Org          usecase.OrgRepository
+}
+
+// Factory returns repositories over an empty storage. It is called once per case.
+type Factory func(t *testing.T) Repositories
+
+type testCase struct {
+	name string
+	run  func(t *testing.T, r Repositories)
+}
+
+// Run executes the whole suite against the backend.
+func Run(t *testing.T, newRepos Factory) {
+	t.Helper()
+
+	cases := slices.Concat(teamCases(), userCases(), prCases(), scheduleCases(), statusChangeCases(), slaCases(),
+		auditCases(), declineCases(), orgCases())
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newRepos(t))
+		})
+	}
+}
+
+// seedTeams creates two teams:
+//   - backend: u1, u2, u3 active and u4 inactive;
+//   - frontend: f1, f2 active.
+func seedTeams(t *testing.T, r Repositories) {
+	t.Helper()
+
+	teams := map[string][]entity.User{
+		"backend": {
+			*entity.NewUser("u1", "Alice", "backend", true),
+			*entity.NewUser("u2", "Bob", "backend", true),
+			*entity.NewUser("u3", "Carol", "backend", true),
+			*entity.NewUser("u4", "Dave", "backend", false),
+		},
+		"frontend": {
+			*entity.NewUser("f1", "Frank", "frontend", true),
+			*entity.NewUser("f2", "Grace", "frontend", true),
+		},
+	}
+	for name, members := range teams {
+		noErr(t, r.Team.CreateTeam(t.Context(), *entity.NewTeam(name), members))
+	}
+}
+
+// createPR creates an open PR with the given creation time.
+func createPR(t *testing.T, r Repositories, id, title, authorID string, createdAt time.Time) entity.PR {
+	t.Helper()
+
+	pr := *entity.NewPR(id, title, authorID)
+	pr.CreatedAt = createdAt.UTC()
+	noErr(t, r.PR.Create(t.Context(), pr))
+	return pr
+}
+
+func noErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func wantErr(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if d := got.Sub(want); d > timeTolerance || d < -timeTolerance {
+		t.Fatalf("%s = %s, want %s", name, got, want)
+	}
+}
+
+func equal[T comparable](t *testing.T, name string, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+}
+
+// sameSet compares slices ignoring order.
+func sameSet(t *testing.T, name string, got, want []string) {
+	t.Helper()
+	got, want = slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(want))
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func prIDs(prs []entity.PR) []string {
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
+	}
+	return ids
+}
+
+func sameOrder(t *testing.T, name string, got, want []string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Fatalf("%s = %v, want %v", name, got, want)
+	}
+}
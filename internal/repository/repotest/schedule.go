@@ -0,0 +1,147 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func scheduleCases() []testCase {
+	return []testCase{
+		{"Schedule/UpsertGetDelete", testScheduleUpsertGetDelete},
+		{"Schedule/MissingUser", testScheduleMissingUser},
+		{"Schedule/ListTeamSchedules", testScheduleListTeamSchedules},
+		{"Schedule/OutOfOffice", testScheduleOutOfOffice},
+		{"Schedule/OutOfOfficeBetween", testScheduleOutOfOfficeBetween},
+	}
+}
+
+func testScheduleUpsertGetDelete(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	_, err := r.Schedule.GetSchedule(t.Context(), "u1")
+	wantErr(t, err, apperror.ErrNotFound)
+
+	saved, err := r.Schedule.UpsertSchedule(t.Context(), *entity.NewWorkSchedule("u1", "Europe/Moscow", "09:00", "18:00"))
+	noErr(t, err)
+	equal(t, "work start", saved.WorkStart, "09:00")
+
+	_, err = r.Schedule.UpsertSchedule(t.Context(), *entity.NewWorkSchedule("u1", "Asia/Tokyo", "22:00", "06:30"))
+	noErr(t, err)
+
+	s, err := r.Schedule.GetSchedule(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "timezone", s.Timezone, "Asia/Tokyo")
+	equal(t, "work start", s.WorkStart, "22:00")
+	equal(t, "work end", s.WorkEnd, "06:30")
+
+	noErr(t, r.Schedule.DeleteSchedule(t.Context(), "u1"))
+	_, err = r.Schedule.GetSchedule(t.Context(), "u1")
+	wantErr(t, err, apperror.ErrNotFound)
+	wantErr(t, r.Schedule.DeleteSchedule(t.Context(), "u1"), apperror.ErrNotFound)
+}
+
+func testScheduleMissingUser(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	_, err := r.Schedule.UpsertSchedule(t.Context(), *entity.NewWorkSchedule("missing", "UTC", "09:00", "18:00"))
+	wantErr(t, err, apperror.ErrNotFound)
+
+	now := time.Now()
+	_, err = r.Schedule.AddOutOfOffice(t.Context(), *entity.NewOutOfOffice("missing", now, now.Add(time.Hour), ""))
+	wantErr(t, err, apperror.ErrNotFound)
+
+	wantErr(t, r.Schedule.DeleteOutOfOffice(t.Context(), 42), apperror.ErrNotFound)
+}
+
+func testScheduleListTeamSchedules(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	for _, userID := range []string{"u1", "u2", "f1"} {
+		_, err := r.Schedule.UpsertSchedule(t.Context(), *entity.NewWorkSchedule(userID, "UTC", "09:00", "18:00"))
+		noErr(t, err)
+	}
+
+	schedules, err := r.Schedule.ListTeamSchedules(t.Context(), "backend")
+	noErr(t, err)
+	ids := make([]string, 0, len(schedules))
+	for _, s := range schedules {
+		ids = append(ids, s.UserID)
+	}
+	sameSet(t, "backend schedules", ids, []string{"u1", "u2"})
+}
+
+func testScheduleOutOfOffice(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	now := time.Now().UTC()
+
+	past, err := r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("u1", now.Add(-48*time.Hour), now.Add(-24*time.Hour), "vacation"))
+	noErr(t, err)
+	current, err := r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("u1", now.Add(-time.Hour), now.Add(time.Hour), "sick"))
+	noErr(t, err)
+	future, err := r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("u1", now.Add(24*time.Hour), now.Add(48*time.Hour), ""))
+	noErr(t, err)
+	other, err := r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("f1", now.Add(-time.Hour), now.Add(time.Hour), ""))
+	noErr(t, err)
+	if past.ID == current.ID || current.ID == future.ID {
+		t.Fatalf("out of office ids are not unique: %d, %d, %d", past.ID, current.ID, future.ID)
+	}
+	equal(t, "reason", current.Reason, "sick")
+	sameTime(t, "starts_at", current.StartsAt, now.Add(-time.Hour))
+
+	periods, err := r.Schedule.ListOutOfOffice(t.Context(), "u1", now)
+	noErr(t, err)
+	equal(t, "not ended periods", len(periods), 2)
+	equal(t, "first period", periods[0].ID, current.ID)
+	equal(t, "second period", periods[1].ID, future.ID)
+
+	team, err := r.Schedule.ListTeamOutOfOffice(t.Context(), "backend", now)
+	noErr(t, err)
+	equal(t, "periods in effect", len(team), 1)
+	equal(t, "period in effect", team[0].ID, current.ID)
+
+	noErr(t, r.Schedule.DeleteOutOfOffice(t.Context(), current.ID))
+	team, err = r.Schedule.ListTeamOutOfOffice(t.Context(), "backend", now)
+	noErr(t, err)
+	equal(t, "periods in effect after delete", len(team), 0)
+
+	team, err = r.Schedule.ListTeamOutOfOffice(t.Context(), "frontend", now)
+	noErr(t, err)
+	equal(t, "frontend periods", len(team), 1)
+	equal(t, "frontend period", team[0].ID, other.ID)
+}
+
+func testScheduleOutOfOfficeBetween(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	now := time.Now().UTC()
+
+	past, err := r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("u1", now.Add(-48*time.Hour), now.Add(-24*time.Hour), "vacation"))
+	noErr(t, err)
+	current, err := r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("u2", now.Add(-time.Hour), now.Add(time.Hour), ""))
+	noErr(t, err)
+	_, err = r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("u1", now.Add(24*time.Hour), now.Add(48*time.Hour), ""))
+	noErr(t, err)
+	_, err = r.Schedule.AddOutOfOffice(t.Context(),
+		*entity.NewOutOfOffice("f1", now.Add(-time.Hour), now.Add(time.Hour), ""))
+	noErr(t, err)
+
+	periods, err := r.Schedule.ListTeamOutOfOfficeBetween(t.Context(), "backend", now.Add(-30*time.Hour), now)
+	noErr(t, err)
+	equal(t, "overlapping periods", len(periods), 2)
+	equal(t, "first period", periods[0].ID, past.ID)
+	equal(t, "second period", periods[1].ID, current.ID)
+
+	// the window is half-open, a period ending at its start does not overlap
+	periods, err = r.Schedule.ListTeamOutOfOfficeBetween(t.Context(), "backend",
+		past.EndsAt, now.Add(-2*time.Hour))
+	noErr(t, err)
+	equal(t, "periods in gap", len(periods), 0)
+}
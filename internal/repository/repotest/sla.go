@@ -0,0 +1,154 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func slaCases() []testCase {
+	return []testCase{
+		{"SLA/SetAndGet", testSLASetAndGet},
+		{"SLA/OverdueAndEscalations", testSLAOverdueAndEscalations},
+		{"SLA/ReviewStats", testSLAReviewStats},
+	}
+}
+
+func testSLASetAndGet(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	sla, err := r.SLA.GetTeamSLA(t.Context(), "backend")
+	noErr(t, err)
+	if sla != nil {
+		t.Fatalf("sla = %s, want nil", sla)
+	}
+
+	noErr(t, r.SLA.SetTeamSLA(t.Context(), "backend", ptr(36*time.Hour)))
+	sla, err = r.SLA.GetTeamSLA(t.Context(), "backend")
+	noErr(t, err)
+	if sla == nil || *sla != 36*time.Hour {
+		t.Fatalf("sla = %v, want 36h", sla)
+	}
+
+	noErr(t, r.SLA.SetTeamSLA(t.Context(), "backend", nil))
+	sla, err = r.SLA.GetTeamSLA(t.Context(), "backend")
+	noErr(t, err)
+	if sla != nil {
+		t.Fatalf("sla after reset = %s, want nil", sla)
+	}
+
+	wantErr(t, r.SLA.SetTeamSLA(t.Context(), "missing", ptr(time.Hour)), apperror.ErrNotFound)
+	_, err = r.SLA.GetTeamSLA(t.Context(), "missing")
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testSLAOverdueAndEscalations(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	noErr(t, r.SLA.SetTeamSLA(t.Context(), "backend", ptr(time.Hour)))
+	createPR(t, r, "pr-1", "Add search", "f1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "f1", time.Now())
+	backendReviewer := "u2"
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", backendReviewer))
+	// frontend has no SLA, so its assignments are never overdue
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "f2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u3"))
+
+	overdue, err := r.SLA.ListOverdueAssignments(t.Context(), time.Now())
+	noErr(t, err)
+	equal(t, "overdue before SLA", len(overdue), 0)
+
+	later := time.Now().Add(2 * time.Hour)
+	overdue, err = r.SLA.ListOverdueAssignments(t.Context(), later)
+	noErr(t, err)
+	equal(t, "overdue after SLA", len(overdue), 2)
+	var o entity.OverdueAssignment
+	for _, candidate := range overdue {
+		if candidate.PRID == "pr-1" {
+			o = candidate
+		}
+	}
+	equal(t, "reviewer", o.ReviewerID, backendReviewer)
+	equal(t, "team", o.TeamName, "backend")
+	equal(t, "sla", o.SLA, time.Hour)
+
+	e, recorded, err := r.SLA.RecordEscalation(t.Context(), escalationOf(o))
+	noErr(t, err)
+	equal(t, "recorded", recorded, true)
+	equal(t, "action", e.Action, entity.EscalationActionNone)
+
+	_, recorded, err = r.SLA.RecordEscalation(t.Context(), escalationOf(o))
+	noErr(t, err)
+	equal(t, "recorded twice", recorded, false)
+
+	overdue, err = r.SLA.ListOverdueAssignments(t.Context(), later)
+	noErr(t, err)
+	equal(t, "overdue after escalation", len(overdue), 1)
+	equal(t, "not escalated PR", overdue[0].PRID, "pr-2")
+
+	noErr(t, r.SLA.UpdateEscalationAction(t.Context(), e.ID, entity.EscalationActionReviewerAdded, "u9"))
+	escalations, err := r.SLA.ListOpenEscalations(t.Context())
+	noErr(t, err)
+	equal(t, "open escalations", len(escalations), 1)
+	equal(t, "stored action", escalations[0].Action, entity.EscalationActionReviewerAdded)
+	if escalations[0].NewReviewerID == nil || *escalations[0].NewReviewerID != "u9" {
+		t.Fatalf("new reviewer = %v, want u9", escalations[0].NewReviewerID)
+	}
+	sameTime(t, "due_at", escalations[0].DueAt, o.DueAt())
+
+	_, err = r.PR.UpdateStatus(t.Context(), "pr-1", entity.PRStatusMerged)
+	noErr(t, err)
+	escalations, err = r.SLA.ListOpenEscalations(t.Context())
+	noErr(t, err)
+	equal(t, "escalations of merged PR", len(escalations), 0)
+	overdue, err = r.SLA.ListOverdueAssignments(t.Context(), later)
+	noErr(t, err)
+	equal(t, "overdue on open PRs", len(overdue), 1)
+}
+
+func testSLAReviewStats(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	noErr(t, r.SLA.SetTeamSLA(t.Context(), "backend", ptr(time.Hour)))
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now().Add(-time.Hour))
+	createPR(t, r, "pr-2", "Fix bug", "u2", time.Now().Add(-time.Hour))
+	createPR(t, r, "pr-3", "Frontend", "f1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u3"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-2", "u3"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-3", "f2"))
+	for _, d := range []*entity.Decline{
+		entity.NewDecline("pr-1", "u3", entity.DeclineReasonNoTime, "", "u2"),
+		entity.NewDecline("pr-2", "u3", entity.DeclineReasonNoTime, "", "u1"),
+		entity.NewDecline("pr-2", "u1", entity.DeclineReasonLackingContext, "", "u3"),
+		entity.NewDecline("pr-3", "f2", entity.DeclineReasonOther, "", "u3"),
+	} {
+		_, err := r.Decline.Record(t.Context(), *d)
+		noErr(t, err)
+	}
+	_, err := r.PR.UpdateStatus(t.Context(), "pr-2", entity.PRStatusMerged)
+	noErr(t, err)
+
+	stats, err := r.SLA.GetReviewStats(t.Context(), "backend", time.Now().Add(2*time.Hour))
+	noErr(t, err)
+	equal(t, "team", stats.TeamName, "backend")
+	if stats.SLA == nil || *stats.SLA != time.Hour {
+		t.Fatalf("sla = %v, want 1h", stats.SLA)
+	}
+	equal(t, "PRs", stats.PRCount, 2)
+	equal(t, "merged", stats.MergedCount, 1)
+	equal(t, "open assignments", stats.OpenAssignments, 2)
+	equal(t, "overdue assignments", stats.OverdueAssignments, 2)
+	if d := stats.AvgTimeToMerge - time.Hour; d < -time.Minute || d > time.Minute {
+		t.Fatalf("avg time to merge = %s, want about 1h", stats.AvgTimeToMerge)
+	}
+	equal(t, "declines", len(stats.Declines), 2)
+	equal(t, "declines for no time", stats.Declines[entity.DeclineReasonNoTime], 2)
+	equal(t, "declines for lacking context", stats.Declines[entity.DeclineReasonLackingContext], 1)
+
+	_, err = r.SLA.GetReviewStats(t.Context(), "missing", time.Now())
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func escalationOf(o entity.OverdueAssignment) entity.Escalation {
+	return *entity.NewEscalation(o, time.Now())
+}
@@ -0,0 +1,115 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func statusChangeCases() []testCase {
+	return []testCase{
+		{"StatusChange/CreateAndList", testStatusChangeCreateAndList},
+		{"StatusChange/Cancel", testStatusChangeCancel},
+		{"StatusChange/ApplyDue", testStatusChangeApplyDue},
+	}
+}
+
+func testStatusChangeCreateAndList(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	now := time.Now()
+
+	later, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u1", true, now.Add(2*time.Hour)))
+	noErr(t, err)
+	sooner, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u1", false, now.Add(time.Hour)))
+	noErr(t, err)
+	_, err = r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u2", false, now.Add(time.Hour)))
+	noErr(t, err)
+	if later.ID == sooner.ID {
+		t.Fatalf("status change ids are not unique: %d", later.ID)
+	}
+	sameTime(t, "apply_at", sooner.ApplyAt, now.Add(time.Hour))
+
+	pending, err := r.StatusChange.ListPending(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "pending", len(pending), 2)
+	equal(t, "first pending", pending[0].ID, sooner.ID)
+	equal(t, "first pending active", pending[0].IsActive, false)
+	equal(t, "second pending", pending[1].ID, later.ID)
+
+	_, err = r.StatusChange.Create(t.Context(), *entity.NewStatusChange("missing", true, now))
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testStatusChangeCancel(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	c, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u1", false, time.Now().Add(time.Hour)))
+	noErr(t, err)
+
+	noErr(t, r.StatusChange.Cancel(t.Context(), c.ID))
+	pending, err := r.StatusChange.ListPending(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "pending after cancel", len(pending), 0)
+
+	wantErr(t, r.StatusChange.Cancel(t.Context(), c.ID), apperror.ErrNotFound)
+}
+
+func testStatusChangeApplyDue(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	now := time.Now()
+
+	first, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u1", false, now.Add(-2*time.Minute)))
+	noErr(t, err)
+	failing, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u2", false, now.Add(-time.Minute)))
+	noErr(t, err)
+	third, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("u3", false, now.Add(-time.Second)))
+	noErr(t, err)
+	notDue, err := r.StatusChange.Create(t.Context(), *entity.NewStatusChange("f1", false, now.Add(time.Hour)))
+	noErr(t, err)
+
+	errApply := errors.New("apply failed")
+	var seen []int64
+	apply := func(ctx context.Context, c entity.StatusChange) error {
+		seen = append(seen, c.ID)
+		if c.ID == failing.ID {
+			return errApply
+		}
+		_, err := r.User.SetIsActive(ctx, c.UserID, c.IsActive)
+		return err
+	}
+
+	applied, err := r.StatusChange.ApplyDue(t.Context(), now, 2, apply)
+	wantErr(t, err, errApply)
+	equal(t, "applied", applied, 1)
+	equal(t, "seen in first batch", len(seen), 2)
+	equal(t, "first seen", seen[0], first.ID)
+	equal(t, "second seen", seen[1], failing.ID)
+
+	u, err := r.User.GetByID(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "u1 active", u.IsActive, false)
+
+	// the failed change is retried, applied ones are not
+	seen = nil
+	applied, err = r.StatusChange.ApplyDue(t.Context(), now, 10, apply)
+	wantErr(t, err, errApply)
+	equal(t, "applied on retry", applied, 1)
+	equal(t, "seen on retry", len(seen), 2)
+	equal(t, "retried", seen[0], failing.ID)
+	equal(t, "applied next", seen[1], third.ID)
+
+	pending, err := r.StatusChange.ListPending(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "u1 pending", len(pending), 0)
+	pending, err = r.StatusChange.ListPending(t.Context(), "f1")
+	noErr(t, err)
+	equal(t, "f1 pending", len(pending), 1)
+	equal(t, "f1 pending change", pending[0].ID, notDue.ID)
+
+	// applied changes can not be cancelled anymore
+	wantErr(t, r.StatusChange.Cancel(t.Context(), first.ID), apperror.ErrNotFound)
+}
@@ -0,0 +1,216 @@
+package repotest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func teamCases() []testCase {
+	return []testCase{
+		{"Team/CreateAndGet", testTeamCreateAndGet},
+		{"Team/CreateDuplicate", testTeamCreateDuplicate},
+		{"Team/CreateMovesExistingUsers", testTeamCreateMovesExistingUsers},
+		{"Team/GetMissing", testTeamGetMissing},
+		{"Team/ListMembersPages", testTeamListMembersPages},
+		{"Team/ListMembersNoLimit", testTeamListMembersNoLimit},
+		{"Team/ListMembersInvalid", testTeamListMembersInvalid},
+		{"Team/GetTeamForUser", testTeamGetTeamForUser},
+		{"Team/SetMaxOpenReviews", testTeamSetMaxOpenReviews},
+		{"Team/ListLoad", testTeamListLoad},
+	}
+}
+
+func testTeamCreateAndGet(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	team, members, err := r.Team.GetTeam(t.Context(), "backend")
+	noErr(t, err)
+	equal(t, "team name", team.Name, "backend")
+
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		equal(t, "member team", m.TeamName, "backend")
+		ids = append(ids, m.ID)
+		if m.ID == "u4" {
+			equal(t, "u4 active", m.IsActive, false)
+		}
+	}
+	sameSet(t, "members", ids, []string{"u1", "u2", "u3", "u4"})
+}
+
+func testTeamCreateDuplicate(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	err := r.Team.CreateTeam(t.Context(), *entity.NewTeam("backend"), nil)
+	wantErr(t, err, apperror.ErrTeamExists)
+}
+
+func testTeamCreateMovesExistingUsers(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	moved := *entity.NewUser("u3", "Carol R.", "platform", false)
+	noErr(t, r.Team.CreateTeam(t.Context(), *entity.NewTeam("platform"), []entity.User{moved}))
+
+	u, err := r.User.GetByID(t.Context(), "u3")
+	noErr(t, err)
+	equal(t, "team", u.TeamName, "platform")
+	equal(t, "name", u.Name, "Carol R.")
+	equal(t, "active", u.IsActive, false)
+
+	_, members, err := r.Team.GetTeam(t.Context(), "backend")
+	noErr(t, err)
+	equal(t, "backend members", len(members), 3)
+}
+
+func testTeamGetMissing(t *testing.T, r Repositories) {
+	_, _, err := r.Team.GetTeam(t.Context(), "missing")
+	wantErr(t, err, apperror.ErrNotFound)
+
+	_, _, err = r.Team.ListMembers(t.Context(), "missing", entity.Sort{}, entity.PageRequest{})
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testTeamListMembersPages(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	var ids []string
+	page := entity.PageRequest{Limit: 3}
+	for {
+		team, members, err := r.Team.ListMembers(t.Context(), "backend", entity.Sort{}, page)
+		noErr(t, err)
+		equal(t, "team name", team.Name, "backend")
+		for _, m := range members.Items {
+			ids = append(ids, m.ID)
+		}
+		if members.NextCursor == "" {
+			break
+		}
+		page.Cursor = members.NextCursor
+	}
+	sameOrder(t, "members by id", ids, []string{"u1", "u2", "u3", "u4"})
+
+	sortByName := entity.Sort{Field: entity.UserSortName, Order: entity.SortDesc}
+	_, first, err := r.Team.ListMembers(t.Context(), "backend", sortByName, entity.PageRequest{Limit: 2})
+	noErr(t, err)
+	_, second, err := r.Team.ListMembers(t.Context(), "backend", sortByName,
+		entity.PageRequest{Limit: 2, Cursor: first.NextCursor})
+	noErr(t, err)
+	names := make([]string, 0, len(first.Items)+len(second.Items))
+	for _, m := range append(first.Items, second.Items...) {
+		names = append(names, m.Name)
+	}
+	sameOrder(t, "members by name desc", names, []string{"Dave", "Carol", "Bob", "Alice"})
+	equal(t, "last page cursor", second.NextCursor, "")
+}
+
+func testTeamListMembersNoLimit(t *testing.T, r Repositories) {
+	// more members than the default page holds
+	members := make([]entity.User, 60)
+	for i := range members {
+		members[i] = *entity.NewUser(fmt.Sprintf("u%02d", i), fmt.Sprintf("User %d", i), "big", true)
+	}
+	noErr(t, r.Team.CreateTeam(t.Context(), *entity.NewTeam("big"), members))
+
+	_, page, err := r.Team.ListMembers(t.Context(), "big", entity.Sort{}, entity.PageRequest{})
+	noErr(t, err)
+	equal(t, "default page size", len(page.Items), 50)
+
+	_, rest, err := r.Team.ListMembers(t.Context(), "big", entity.Sort{},
+		entity.PageRequest{Limit: entity.NoPageLimit, Cursor: page.NextCursor})
+	noErr(t, err)
+	equal(t, "members after the default page", len(rest.Items), 10)
+	equal(t, "first member after the default page", rest.Items[0].ID, "u50")
+
+	_, all, err := r.Team.ListMembers(t.Context(), "big", entity.Sort{}, entity.PageRequest{Limit: entity.NoPageLimit})
+	noErr(t, err)
+	equal(t, "all members", len(all.Items), 60)
+	equal(t, "cursor without a limit", all.NextCursor, "")
+}
+
+func testTeamListMembersInvalid(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	_, _, err := r.Team.ListMembers(t.Context(), "backend", entity.Sort{}, entity.PageRequest{Cursor: "%%%"})
+	wantErr(t, err, apperror.ErrInvalidListing)
+
+	_, _, err = r.Team.ListMembers(t.Context(), "backend", entity.Sort{Field: "age"}, entity.PageRequest{})
+	wantErr(t, err, apperror.ErrInvalidListing)
+}
+
+func testTeamGetTeamForUser(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	team, err := r.Team.GetTeamForUser(t.Context(), "f1")
+	noErr(t, err)
+	equal(t, "team", team, "frontend")
+
+	_, err = r.Team.GetTeamForUser(t.Context(), "missing")
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testTeamSetMaxOpenReviews(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	_, err := r.User.SetMaxOpenReviews(t.Context(), "u2", ptr(1))
+	noErr(t, err)
+
+	noErr(t, r.Team.SetMaxOpenReviews(t.Context(), "backend", ptr(3)))
+	team, members, err := r.Team.GetTeam(t.Context(), "backend")
+	noErr(t, err)
+	equal(t, "team limit", *team.MaxOpenReviews, 3)
+	for _, m := range members {
+		if m.ID == "u2" {
+			equal(t, "u2 limit", *m.MaxOpenReviews, 1)
+		} else if m.MaxOpenReviews != nil {
+			t.Fatalf("%s limit = %d, want nil", m.ID, *m.MaxOpenReviews)
+		}
+	}
+
+	team, page, err := r.Team.ListMembers(t.Context(), "backend", entity.Sort{}, entity.PageRequest{Limit: 2})
+	noErr(t, err)
+	equal(t, "listed team limit", *team.MaxOpenReviews, 3)
+	equal(t, "listed u2 limit", *page.Items[1].MaxOpenReviews, 1)
+
+	// re-adding a member keeps the own limit
+	noErr(t, r.Team.CreateTeam(t.Context(), *entity.NewTeam("platform"),
+		[]entity.User{*entity.NewUser("u2", "Bob", "platform", true)}))
+	u, err := r.User.GetByID(t.Context(), "u2")
+	noErr(t, err)
+	equal(t, "moved u2 limit", *u.MaxOpenReviews, 1)
+
+	noErr(t, r.Team.SetMaxOpenReviews(t.Context(), "backend", nil))
+	team, _, err = r.Team.GetTeam(t.Context(), "backend")
+	noErr(t, err)
+	if team.MaxOpenReviews != nil {
+		t.Fatalf("team limit = %d, want nil", *team.MaxOpenReviews)
+	}
+
+	wantErr(t, r.Team.SetMaxOpenReviews(t.Context(), "missing", ptr(1)), apperror.ErrNotFound)
+}
+
+func testTeamListLoad(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "u1", time.Now())
+	createPR(t, r, "pr-2", "Fix bug", "u1", time.Now())
+	createPR(t, r, "pr-3", "Frontend", "f1", time.Now())
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "u2"))
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "f2"))
+	// reviews of merged PRs are not load
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-3", "u3"))
+	_, err := r.PR.UpdateStatus(t.Context(), "pr-3", entity.PRStatusMerged)
+	noErr(t, err)
+
+	loads, err := r.Team.ListLoad(t.Context())
+	noErr(t, err)
+	equal(t, "teams", len(loads), 2)
+	equal(t, "first team", loads[0].TeamName, "backend")
+	equal(t, "backend open PRs", loads[0].OpenPRs, 2)
+	// u1 and u3 are idle, u4 is inactive
+	equal(t, "backend idle reviewers", loads[0].IdleReviewers, 2)
+	equal(t, "second team", loads[1].TeamName, "frontend")
+	equal(t, "frontend open PRs", loads[1].OpenPRs, 0)
+	equal(t, "frontend idle reviewers", loads[1].IdleReviewers, 1)
+}
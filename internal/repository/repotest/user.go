@@ -0,0 +1,173 @@
+package repotest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func userCases() []testCase {
+	return []testCase{
+		{"User/GetByID", testUserGetByID},
+		{"User/SetIsActive", testUserSetIsActive},
+		{"User/SetMaxOpenReviews", testUserSetMaxOpenReviews},
+		{"User/IsAssignedToPR", testUserIsAssignedToPR},
+		{"User/ListAssignedTo", testUserListAssignedTo},
+		{"User/ListTeamStatusHistory", testUserListTeamStatusHistory},
+	}
+}
+
+func testUserGetByID(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	u, err := r.User.GetByID(t.Context(), "u2")
+	noErr(t, err)
+	equal(t, "name", u.Name, "Bob")
+	equal(t, "team", u.TeamName, "backend")
+	equal(t, "active", u.IsActive, true)
+
+	_, err = r.User.GetByID(t.Context(), "missing")
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testUserSetIsActive(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	u, err := r.User.SetIsActive(t.Context(), "u1", false)
+	noErr(t, err)
+	equal(t, "returned active", u.IsActive, false)
+	equal(t, "returned team", u.TeamName, "backend")
+
+	u, err = r.User.GetByID(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "stored active", u.IsActive, false)
+
+	_, err = r.User.SetIsActive(t.Context(), "missing", true)
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testUserSetMaxOpenReviews(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+
+	u, err := r.User.SetMaxOpenReviews(t.Context(), "u1", ptr(2))
+	noErr(t, err)
+	equal(t, "returned limit", *u.MaxOpenReviews, 2)
+	equal(t, "returned team", u.TeamName, "backend")
+
+	u, err = r.User.GetByID(t.Context(), "u1")
+	noErr(t, err)
+	equal(t, "stored limit", *u.MaxOpenReviews, 2)
+
+	u, err = r.User.SetMaxOpenReviews(t.Context(), "u1", nil)
+	noErr(t, err)
+	if u.MaxOpenReviews != nil {
+		t.Fatalf("limit = %d, want nil", *u.MaxOpenReviews)
+	}
+
+	_, err = r.User.SetMaxOpenReviews(t.Context(), "missing", ptr(1))
+	wantErr(t, err, apperror.ErrNotFound)
+}
+
+func testUserIsAssignedToPR(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	createPR(t, r, "pr-1", "Add search", "f1", time.Now())
+
+	noErr(t, r.PR.AddReviewer(t.Context(), "pr-1", "f2"))
+
+	assigned, err := r.User.IsAssignedToPR(t.Context(), "f2", "pr-1")
+	noErr(t, err)
+	equal(t, "f2 assigned", assigned, true)
+
+	assigned, err = r.User.IsAssignedToPR(t.Context(), "f1", "pr-1")
+	noErr(t, err)
+	equal(t, "author assigned", assigned, false)
+
+	assigned, err = r.User.IsAssignedToPR(t.Context(), "f2", "missing")
+	noErr(t, err)
+	equal(t, "missing PR assigned", assigned, false)
+}
+
+func testUserListAssignedTo(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		createPR(t, r, id, "Change "+id, "f1", base.Add(time.Duration(i)*time.Minute))
+		noErr(t, r.PR.AddReviewer(t.Context(), id, "f2"))
+	}
+	createPR(t, r, "pr-other", "Not reviewed by f2", "f2", base)
+	_, err := r.PR.UpdateStatus(t.Context(), "pr-2", entity.PRStatusMerged)
+	noErr(t, err)
+
+	newestFirst := entity.PRListOptions{
+		Sort: entity.Sort{Field: entity.PRSortCreatedAt, Order: entity.SortDesc},
+		Page: entity.PageRequest{Limit: 2},
+	}
+	first, err := r.User.ListAssignedTo(t.Context(), "f2", newestFirst)
+	noErr(t, err)
+	sameOrder(t, "first page", prIDs(first.Items), []string{"pr-3", "pr-2"})
+
+	newestFirst.Page.Cursor = first.NextCursor
+	second, err := r.User.ListAssignedTo(t.Context(), "f2", newestFirst)
+	noErr(t, err)
+	sameOrder(t, "second page", prIDs(second.Items), []string{"pr-1"})
+	equal(t, "last page cursor", second.NextCursor, "")
+
+	newestFirst.Page = entity.PageRequest{Limit: entity.NoPageLimit}
+	all, err := r.User.ListAssignedTo(t.Context(), "f2", newestFirst)
+	noErr(t, err)
+	sameOrder(t, "all PRs", prIDs(all.Items), []string{"pr-3", "pr-2", "pr-1"})
+	equal(t, "cursor without a limit", all.NextCursor, "")
+
+	open, err := r.User.ListAssignedTo(t.Context(), "f2", entity.PRListOptions{
+		Filter: entity.PRFilter{Status: entity.PRStatusOpen},
+	})
+	noErr(t, err)
+	sameSet(t, "open PRs", prIDs(open.Items), []string{"pr-1", "pr-3"})
+
+	none, err := r.User.ListAssignedTo(t.Context(), "missing", entity.PRListOptions{})
+	noErr(t, err)
+	equal(t, "PRs of missing user", len(none.Items), 0)
+}
+
+func testUserListTeamStatusHistory(t *testing.T, r Repositories) {
+	seedTeams(t, r)
+	from := time.Now().Add(-time.Minute)
+
+	_, err := r.User.SetIsActive(t.Context(), "u1", false)
+	noErr(t, err)
+	_, err = r.User.SetIsActive(t.Context(), "u2", true) // already active, not a change
+	noErr(t, err)
+	_, err = r.User.SetIsActive(t.Context(), "u4", true)
+	noErr(t, err)
+	_, err = r.User.SetIsActive(t.Context(), "u1", true)
+	noErr(t, err)
+	_, err = r.User.SetIsActive(t.Context(), "f1", false)
+	noErr(t, err)
+	// the team upsert moves u3 to another team and deactivates it
+	noErr(t, r.Team.CreateTeam(t.Context(), *entity.NewTeam("platform"),
+		[]entity.User{*entity.NewUser("u3", "Carol", "platform", false)}))
+
+	to := time.Now().Add(time.Minute)
+	history, err := r.User.ListTeamStatusHistory(t.Context(), "backend", from, to)
+	noErr(t, err)
+	changes := make([]string, 0, len(history))
+	for _, h := range history {
+		changes = append(changes, fmt.Sprintf("%s:%t", h.UserID, h.IsActive))
+		if h.ChangedAt.Before(from) || !h.ChangedAt.Before(to) {
+			t.Fatalf("change %+v is outside of [%v, %v)", h, from, to)
+		}
+	}
+	sameOrder(t, "backend changes", changes, []string{"u1:false", "u4:true", "u1:true"})
+
+	history, err = r.User.ListTeamStatusHistory(t.Context(), "platform", from, to)
+	noErr(t, err)
+	equal(t, "platform changes", len(history), 1)
+	equal(t, "u3 active", history[0].IsActive, false)
+
+	history, err = r.User.ListTeamStatusHistory(t.Context(), "backend", from.Add(-time.Hour), from)
+	noErr(t, err)
+	equal(t, "changes before the window", len(history), 0)
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type ReviewerAuditRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewReviewerAuditRepository(db *sql.DB) *ReviewerAuditRepository {
+	return &ReviewerAuditRepository{db: db, sb: newBuilder()}
+}
+
+func (r *ReviewerAuditRepository) Record(
+	ctx context.Context,
+	e entity.ReviewerAuditEntry,
+) (*entity.ReviewerAuditEntry, error) {
+	query := r.sb.
+		Insert("reviewer_audit").
+		Columns("pr_id", "reviewer_id", "action", "actor", "created_at").
+		Values(e.PRID, e.ReviewerID, e.Action, e.Actor, nanos(e.CreatedAt)).
+		Suffix("RETURNING id")
+
+	row := tryQueryRow(ctx, "ReviewerAuditRepository.Record", query, r.db)
+
+	if err := row.Scan(&e.ID); err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ReviewerAuditRepository.Record failed to insert audit entry: %w", err)
+	}
+
+	return &e, nil
+}
+
+// ListForPR returns audit entries of the PR, oldest first.
+func (r *ReviewerAuditRepository) ListForPR(ctx context.Context, prID string) ([]entity.ReviewerAuditEntry, error) {
+	query := r.sb.
+		Select("id", "pr_id", "reviewer_id", "action", "actor", "created_at").
+		From("reviewer_audit").
+		Where(sq.Eq{"pr_id": prID}).
+		OrderBy("created_at", "id")
+
+	rows, err := tryQuery(ctx, "ReviewerAuditRepository.ListForPR", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("ReviewerAuditRepository.ListForPR failed to select audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]entity.ReviewerAuditEntry, 0)
+	for rows.Next() {
+		var e entity.ReviewerAuditEntry
+		if err = rows.Scan(&e.ID, &e.PRID, &e.ReviewerID, &e.Action, &e.Actor, scanTime(&e.CreatedAt)); err != nil {
+			return nil, fmt.Errorf("ReviewerAuditRepository.ListForPR failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
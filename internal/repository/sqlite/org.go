@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type OrgRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewOrgRepository(db *sql.DB) *OrgRepository {
+	return &OrgRepository{db: db, sb: newBuilder()}
+}
+
+// ListOrg returns teams ordered by name with members ordered by id. Both are
+// read in one transaction, so they come from the same snapshot.
+func (r *OrgRepository) ListOrg(ctx context.Context) ([]entity.OrgTeam, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := r.sb.
+		Select("name").
+		From("teams").
+		OrderBy("name")
+
+	teams, err := selectOrgTeams(ctx, "OrgRepository.ListOrg", query, tx)
+	if err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to select teams: %w", err)
+	}
+	index := make(map[string]int, len(teams))
+	for i, t := range teams {
+		index[t.Name] = i
+	}
+
+	queryUsers := r.sb.
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
+		From("users").
+		OrderBy("id")
+
+	users, err := selectUsers(ctx, "OrgRepository.ListOrg", queryUsers, tx)
+	if err != nil {
+		return nil, fmt.Errorf("OrgRepository.ListOrg failed to select users: %w", err)
+	}
+	for _, u := range users {
+		// the foreign key keeps users in existing teams, a user without one is skipped
+		if i, ok := index[u.TeamName]; ok {
+			teams[i].Members = append(teams[i].Members, u)
+		}
+	}
+
+	return teams, nil
+}
+
+func selectOrgTeams(ctx context.Context, op string, query sq.SelectBuilder, q queryer) ([]entity.OrgTeam, error) {
+	rows, err := tryQuery(ctx, op, query, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]entity.OrgTeam, 0)
+	for rows.Next() {
+		var t entity.OrgTeam
+		if err = rows.Scan(&t.Name); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+
+	return teams, rows.Err()
+}
+
+func (r *OrgRepository) ApplyOrgPlan(ctx context.Context, plan entity.OrgPlan) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if len(plan.AddTeams) > 0 {
+		query := r.sb.
+			Insert("teams").
+			Columns("name", "created_at")
+		now := nanos(time.Now().UTC())
+		for _, name := range plan.AddTeams {
+			query = query.Values(name, now)
+		}
+
+		if err = tryExec(ctx, "OrgRepository.ApplyOrgPlan", query, tx); err != nil {
+			if isUniqueViolation(err) {
+				return apperror.ErrTeamExists
+			}
+			return fmt.Errorf("OrgRepository.ApplyOrgPlan failed to insert teams: %w", err)
+		}
+	}
+
+	if len(plan.Users) > 0 {
+		query := r.sb.
+			Insert("users").
+			Columns("id", "team_name", "name", "is_active", "created_at")
+		for _, c := range plan.Users {
+			u := c.After
+			query = query.Values(u.ID, u.TeamName, u.Name, u.IsActive, nanos(u.CreatedAt))
+		}
+		query = query.Suffix("ON CONFLICT (id) DO UPDATE SET " +
+			"team_name = excluded.team_name, name = excluded.name, is_active = excluded.is_active")
+
+		if err = tryExec(ctx, "OrgRepository.ApplyOrgPlan", query, tx); err != nil {
+			return fmt.Errorf("OrgRepository.ApplyOrgPlan failed to insert or update users: %w", err)
+		}
+	}
+
+	for _, re := range plan.Reassign {
+		if err = replaceReviewer(ctx, r.sb, "OrgRepository.ApplyOrgPlan", tx, re.PRID, re.ReviewerID,
+			re.ReplacedBy); err != nil {
+			return err
+		}
+	}
+
+	if len(plan.RemoveTeams) > 0 {
+		query := r.sb.
+			Delete("teams").
+			Where(sq.Eq{"name": plan.RemoveTeams})
+
+		if err = tryExec(ctx, "OrgRepository.ApplyOrgPlan", query, tx); err != nil {
+			return fmt.Errorf("OrgRepository.ApplyOrgPlan failed to delete teams: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
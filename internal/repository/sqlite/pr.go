@@ -0,0 +1,466 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type PRRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewPRRepository(db *sql.DB) *PRRepository {
+	return &PRRepository{db: db, sb: newBuilder()}
+}
+
+func (r *PRRepository) Create(ctx context.Context, pr entity.PR) error {
+	query := r.sb.
+		Insert("prs").
+		Columns("id", "title", "author_id", "status", "created_at").
+		Values(pr.ID, pr.Title, pr.AuthorID, pr.Status, nanos(pr.CreatedAt))
+
+	if err := tryExec(ctx, "PRRepository.Create", query, r.db); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrPRExists
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("PRRepository.Create failed to insert pr: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PRRepository) GetByID(ctx context.Context, id string) (*entity.PR, error) {
+	query := r.sb.
+		Select("id", "title", "author_id", "status", "created_at", "merged_at").
+		From("prs").
+		Where(sq.Eq{"id": id})
+
+	row := tryQueryRow(ctx, "PRRepository.GetByID", query, r.db)
+
+	pr, err := scanPR(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("PRRepository.GetByID failed to select pr: %w", err)
+	}
+
+	return pr, nil
+}
+
+// UpdateStatus re-reads the PR after the update: RETURNING does not see merged_at
+// set by the trigger.
+func (r *PRRepository) UpdateStatus(ctx context.Context, id, status string) (*entity.PR, error) {
+	query := r.sb.
+		Update("prs").
+		Set("status", status).
+		Where(sq.Eq{"id": id})
+
+	affected, err := tryExecAffected(ctx, "PRRepository.UpdateStatus", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.UpdateStatus failed to update pr status: %w", err)
+	}
+	if affected == 0 {
+		return nil, apperror.ErrNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// AddReviewer assigns the user to the PR.
+func (r *PRRepository) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	return r.insertReviewer(ctx, "PRRepository.AddReviewer", prID, reviewerID, r.db)
+}
+
+// AddReviewerWithLimit assigns the user to the PR unless it already has
+// maxReviewers reviewers. The count and the insert run in one transaction,
+// which SQLite serializes with other writers.
+func (r *PRRepository) AddReviewerWithLimit(ctx context.Context, prID, reviewerID string, maxReviewers int) error {
+	const op = "PRRepository.AddReviewerWithLimit"
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("PRRepository.AddReviewerWithLimit failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	exists := r.sb.
+		Select("COUNT(*)").
+		From("prs").
+		Where(sq.Eq{"id": prID})
+	var prs int
+	if err = tryQueryRow(ctx, op, exists, tx).Scan(&prs); err != nil {
+		return fmt.Errorf("PRRepository.AddReviewerWithLimit failed to select pr: %w", err)
+	}
+	if prs == 0 {
+		return apperror.ErrNotFound
+	}
+
+	count := r.sb.
+		Select("COUNT(*)").
+		From("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID})
+	var assigned int
+	if err = tryQueryRow(ctx, op, count, tx).Scan(&assigned); err != nil {
+		return fmt.Errorf("PRRepository.AddReviewerWithLimit failed to count reviewers: %w", err)
+	}
+	if assigned >= maxReviewers {
+		return apperror.ErrReviewersFull
+	}
+
+	if err = r.insertReviewer(ctx, op, prID, reviewerID, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PRRepository) insertReviewer(ctx context.Context, op, prID, reviewerID string, q queryer) error {
+	query := r.sb.
+		Insert("pr_reviewers").
+		Columns("pr_id", "reviewer_id", "assigned_at").
+		Values(prID, reviewerID, nanos(time.Now()))
+
+	if err := tryExec(ctx, op, query, q); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrReviewerExists
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("%s failed to insert pr_reviewer: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
+	query := r.sb.
+		Delete("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID, "reviewer_id": reviewerID})
+
+	if err := tryExec(ctx, "PRRepository.RemoveReviewer", query, r.db); err != nil {
+		return fmt.Errorf("PRRepository.RemoveReviewer failed to delete pr_reviewer: %w", err)
+	}
+
+	return nil
+}
+
+// MarkReviewerRemoved remembers that the reviewer was taken off the PR, so the
+// reviewer is not picked for it again.
+func (r *PRRepository) MarkReviewerRemoved(ctx context.Context, prID, reviewerID string) error {
+	query := r.sb.
+		Insert("removed_reviewers").
+		Columns("pr_id", "reviewer_id", "removed_at").
+		Values(prID, reviewerID, nanos(time.Now())).
+		Suffix("ON CONFLICT (pr_id, reviewer_id) DO UPDATE SET removed_at = excluded.removed_at")
+
+	if err := tryExec(ctx, "PRRepository.MarkReviewerRemoved", query, r.db); err != nil {
+		if isForeignKeyViolation(err) {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("PRRepository.MarkReviewerRemoved failed to insert removed reviewer: %w", err)
+	}
+
+	return nil
+}
+
+// ListRemovedReviewers returns ids of reviewers ever taken off the PR.
+func (r *PRRepository) ListRemovedReviewers(ctx context.Context, prID string) ([]string, error) {
+	query := r.sb.
+		Select("reviewer_id").
+		From("removed_reviewers").
+		Where(sq.Eq{"pr_id": prID}).
+		OrderBy("reviewer_id")
+
+	rows, err := tryQuery(ctx, "PRRepository.ListRemovedReviewers", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.ListRemovedReviewers failed to select removed reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	removedIDs := make([]string, 0)
+	for rows.Next() {
+		var reviewerID string
+		if err = rows.Scan(&reviewerID); err != nil {
+			return nil, fmt.Errorf("PRRepository.ListRemovedReviewers failed to scan reviewer ID: %w", err)
+		}
+		removedIDs = append(removedIDs, reviewerID)
+	}
+
+	return removedIDs, rows.Err()
+}
+
+func (r *PRRepository) DeleteByID(ctx context.Context, prID string) error {
+	query := r.sb.
+		Delete("prs").
+		Where(sq.Eq{"id": prID})
+
+	if err := tryExec(ctx, "PRRepository.DeleteByID", query, r.db); err != nil {
+		return fmt.Errorf("PRRepository.DeleteByID failed to delete pr: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PRRepository) GetAssignedReviewers(ctx context.Context, prID string) ([]string, error) {
+	query := r.sb.
+		Select("reviewer_id").
+		From("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID})
+
+	rows, err := tryQuery(ctx, "PRRepository.GetAssignedReviewers", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.GetAssignedReviewers failed to select reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	assignedIDs := make([]string, 0)
+	for rows.Next() {
+		var reviewerID string
+		if err = rows.Scan(&reviewerID); err != nil {
+			return nil, fmt.Errorf("PRRepository.GetAssignedReviewers failed to scan reviewer ID: %w", err)
+		}
+		assignedIDs = append(assignedIDs, reviewerID)
+	}
+
+	return assignedIDs, rows.Err()
+}
+
+// List returns a page of PRs matching the options. Reviewers are not loaded, see
+// GetReviewersForPRs.
+func (r *PRRepository) List(ctx context.Context, opts entity.PRListOptions) (entity.Page[entity.PR], error) {
+	key, keyOf, err := prSortKey("p", opts.Sort)
+	if err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	query := r.sb.
+		Select("p.id", "p.title", "p.author_id", "p.status", "p.created_at", "p.merged_at").
+		From("prs p")
+	query = applyPRFilter(query, "p", opts.Filter)
+	if query, err = paginate(query, key, opts.Page); err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	prs, err := selectPRs(ctx, "PRRepository.List", query, r.db)
+	if err != nil {
+		return entity.Page[entity.PR]{}, fmt.Errorf("PRRepository.List failed to select PRs: %w", err)
+	}
+
+	return trimPage(prs, opts.Page.Limit, keyOf), nil
+}
+
+// GetReviewersForPRs returns reviewer ids of several PRs in one query, keyed by PR id.
+func (r *PRRepository) GetReviewersForPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
+	reviewers := make(map[string][]string, len(prIDs))
+	if len(prIDs) == 0 {
+		return reviewers, nil
+	}
+
+	query := r.sb.
+		Select("pr_id", "reviewer_id").
+		From("pr_reviewers").
+		Where(sq.Eq{"pr_id": prIDs}).
+		OrderBy("pr_id", "assigned_at")
+
+	rows, err := tryQuery(ctx, "PRRepository.GetReviewersForPRs", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.GetReviewersForPRs failed to select reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID, reviewerID string
+		if err = rows.Scan(&prID, &reviewerID); err != nil {
+			return nil, fmt.Errorf("PRRepository.GetReviewersForPRs failed to scan reviewer: %w", err)
+		}
+		reviewers[prID] = append(reviewers[prID], reviewerID)
+	}
+
+	return reviewers, rows.Err()
+}
+
+// GetReviewerDetails returns reviewers of the PR with their profiles in assignment order.
+func (r *PRRepository) GetReviewerDetails(ctx context.Context, prID string) ([]entity.AssignedReviewer, error) {
+	query := r.sb.
+		Select("u.id", "u.name", "u.team_name", "u.is_active", "u.created_at", "u.max_open_reviews", "r.assigned_at").
+		From("pr_reviewers r").
+		Join("users u ON u.id = r.reviewer_id").
+		Where(sq.Eq{"r.pr_id": prID}).
+		OrderBy("r.assigned_at", "u.id")
+
+	rows, err := tryQuery(ctx, "PRRepository.GetReviewerDetails", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.GetReviewerDetails failed to select reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	reviewers := make([]entity.AssignedReviewer, 0)
+	for rows.Next() {
+		var rv entity.AssignedReviewer
+		if err = rows.Scan(&rv.ID, &rv.Name, &rv.TeamName, &rv.IsActive,
+			scanTime(&rv.CreatedAt), &rv.MaxOpenReviews, scanTime(&rv.AssignedAt)); err != nil {
+			return nil, fmt.Errorf("PRRepository.GetReviewerDetails failed to scan reviewer: %w", err)
+		}
+		reviewers = append(reviewers, rv)
+	}
+
+	return reviewers, rows.Err()
+}
+
+// CountAssignments returns the number of assignments made to members of the team
+// since the given moment, keyed by reviewer id. Members without assignments are omitted.
+func (r *PRRepository) CountAssignments(ctx context.Context, teamName string, since time.Time) (map[string]int, error) {
+	query := r.sb.
+		Select("r.reviewer_id", "COUNT(*)").
+		From("pr_reviewers r").
+		Join("users u ON u.id = r.reviewer_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.GtOrEq{"r.assigned_at": nanos(since)}).
+		GroupBy("r.reviewer_id")
+
+	rows, err := tryQuery(ctx, "PRRepository.CountAssignments", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.CountAssignments failed to count assignments: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			reviewerID string
+			count      int
+		)
+		if err = rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("PRRepository.CountAssignments failed to scan count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// CountOpenReviews returns the number of open PRs each member of the team is
+// assigned to, keyed by reviewer id. Members without open reviews are omitted.
+func (r *PRRepository) CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error) {
+	query := r.sb.
+		Select("r.reviewer_id", "COUNT(*)").
+		From("pr_reviewers r").
+		Join("prs p ON p.id = r.pr_id").
+		Join("users u ON u.id = r.reviewer_id").
+		Where(sq.Eq{"u.team_name": teamName, "p.status": entity.PRStatusOpen}).
+		GroupBy("r.reviewer_id")
+
+	rows, err := tryQuery(ctx, "PRRepository.CountOpenReviews", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("PRRepository.CountOpenReviews failed to count reviews: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			reviewerID string
+			count      int
+		)
+		if err = rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("PRRepository.CountOpenReviews failed to scan count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// scanPR scans the id, title, author_id, status, created_at and merged_at columns.
+func scanPR(row row) (*entity.PR, error) {
+	var pr entity.PR
+	err := row.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.Status, scanTime(&pr.CreatedAt), scanNullTime(&pr.MergedAt))
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func selectPRs(ctx context.Context, op string, query sq.SelectBuilder, q queryer) ([]entity.PR, error) {
+	rows, err := tryQuery(ctx, op, query, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs := make([]entity.PR, 0)
+	for rows.Next() {
+		pr, err := scanPR(rows)
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, *pr)
+	}
+
+	return prs, rows.Err()
+}
+
+// replaceReviewer hands the review of the PR over from oldID to newID and
+// remembers oldID as removed. The PR must still be open and reviewed by oldID.
+func replaceReviewer(
+	ctx context.Context,
+	sb sq.StatementBuilderType,
+	op string,
+	tx queryer,
+	prID, oldID, newID string,
+) error {
+	remove := sb.
+		Delete("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID, "reviewer_id": oldID}).
+		Where("pr_id IN (SELECT id FROM prs WHERE status = ?)", entity.PRStatusOpen)
+
+	removed, err := tryExecAffected(ctx, op, remove, tx)
+	if err != nil {
+		return fmt.Errorf("%s failed to delete pr_reviewer: %w", op, err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("review of %s by %s: %w", prID, oldID, apperror.ErrNotAssigned)
+	}
+
+	now := nanos(time.Now())
+	add := sb.
+		Insert("pr_reviewers").
+		Columns("pr_id", "reviewer_id", "assigned_at").
+		Values(prID, newID, now)
+
+	if err = tryExec(ctx, op, add, tx); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("review of %s by %s: %w", prID, newID, apperror.ErrReviewerExists)
+		}
+		if isForeignKeyViolation(err) {
+			return apperror.ErrNotFound
+		}
+		return fmt.Errorf("%s failed to insert pr_reviewer: %w", op, err)
+	}
+
+	mark := sb.
+		Insert("removed_reviewers").
+		Columns("pr_id", "reviewer_id", "removed_at").
+		Values(prID, oldID, now).
+		Suffix("ON CONFLICT (pr_id, reviewer_id) DO UPDATE SET removed_at = excluded.removed_at")
+
+	if err = tryExec(ctx, op, mark, tx); err != nil {
+		return fmt.Errorf("%s failed to insert removed reviewer: %w", op, err)
+	}
+
+	return nil
+}
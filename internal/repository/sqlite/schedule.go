@@ -0,0 +1,233 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type ScheduleRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db, sb: newBuilder()}
+}
+
+func (r *ScheduleRepository) UpsertSchedule(ctx context.Context, s entity.WorkSchedule) (*entity.WorkSchedule, error) {
+	query := r.sb.
+		Insert("user_schedules").
+		Columns("user_id", "timezone", "work_start", "work_end", "updated_at").
+		Values(s.UserID, s.Timezone, s.WorkStart, s.WorkEnd, nanos(s.UpdatedAt)).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET " +
+			"timezone = excluded.timezone, work_start = excluded.work_start, " +
+			"work_end = excluded.work_end, updated_at = excluded.updated_at " +
+			"RETURNING user_id, timezone, work_start, work_end, updated_at")
+
+	row := tryQueryRow(ctx, "ScheduleRepository.UpsertSchedule", query, r.db)
+
+	out, err := scanSchedule(row)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ScheduleRepository.UpsertSchedule failed to upsert schedule: %w", err)
+	}
+
+	return out, nil
+}
+
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, userID string) (*entity.WorkSchedule, error) {
+	query := r.sb.
+		Select("user_id", "timezone", "work_start", "work_end", "updated_at").
+		From("user_schedules").
+		Where(sq.Eq{"user_id": userID})
+
+	row := tryQueryRow(ctx, "ScheduleRepository.GetSchedule", query, r.db)
+
+	s, err := scanSchedule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ScheduleRepository.GetSchedule failed to select schedule: %w", err)
+	}
+
+	return s, nil
+}
+
+func (r *ScheduleRepository) DeleteSchedule(ctx context.Context, userID string) error {
+	query := r.sb.
+		Delete("user_schedules").
+		Where(sq.Eq{"user_id": userID})
+
+	affected, err := tryExecAffected(ctx, "ScheduleRepository.DeleteSchedule", query, r.db)
+	if err != nil {
+		return fmt.Errorf("ScheduleRepository.DeleteSchedule failed to delete schedule: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ScheduleRepository) ListTeamSchedules(ctx context.Context, teamName string) ([]entity.WorkSchedule, error) {
+	query := r.sb.
+		Select("s.user_id", "s.timezone", "s.work_start", "s.work_end", "s.updated_at").
+		From("user_schedules s").
+		Join("users u ON u.id = s.user_id").
+		Where(sq.Eq{"u.team_name": teamName})
+
+	rows, err := tryQuery(ctx, "ScheduleRepository.ListTeamSchedules", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduleRepository.ListTeamSchedules failed to select schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := make([]entity.WorkSchedule, 0)
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ScheduleRepository.ListTeamSchedules failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, *s)
+	}
+
+	return schedules, rows.Err()
+}
+
+func (r *ScheduleRepository) AddOutOfOffice(ctx context.Context, o entity.OutOfOffice) (*entity.OutOfOffice, error) {
+	query := r.sb.
+		Insert("user_out_of_office").
+		Columns("user_id", "starts_at", "ends_at", "reason", "created_at").
+		Values(o.UserID, nanos(o.StartsAt), nanos(o.EndsAt), o.Reason, nanos(o.CreatedAt)).
+		Suffix("RETURNING id, user_id, starts_at, ends_at, reason, created_at")
+
+	row := tryQueryRow(ctx, "ScheduleRepository.AddOutOfOffice", query, r.db)
+
+	out, err := scanOutOfOffice(row)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("ScheduleRepository.AddOutOfOffice failed to insert out of office: %w", err)
+	}
+
+	return out, nil
+}
+
+func (r *ScheduleRepository) DeleteOutOfOffice(ctx context.Context, id int64) error {
+	query := r.sb.
+		Delete("user_out_of_office").
+		Where(sq.Eq{"id": id})
+
+	affected, err := tryExecAffected(ctx, "ScheduleRepository.DeleteOutOfOffice", query, r.db)
+	if err != nil {
+		return fmt.Errorf("ScheduleRepository.DeleteOutOfOffice failed to delete out of office: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListOutOfOffice returns periods of the user that have not ended by now.
+func (r *ScheduleRepository) ListOutOfOffice(
+	ctx context.Context,
+	userID string,
+	now time.Time,
+) ([]entity.OutOfOffice, error) {
+	query := r.sb.
+		Select("id", "user_id", "starts_at", "ends_at", "reason", "created_at").
+		From("user_out_of_office").
+		Where(sq.Eq{"user_id": userID}).
+		Where(sq.Gt{"ends_at": nanos(now)}).
+		OrderBy("starts_at")
+
+	return r.selectOutOfOffice(ctx, query, "ListOutOfOffice")
+}
+
+// ListTeamOutOfOffice returns periods of the team members that are in effect at the given moment.
+func (r *ScheduleRepository) ListTeamOutOfOffice(
+	ctx context.Context,
+	teamName string,
+	at time.Time,
+) ([]entity.OutOfOffice, error) {
+	query := r.sb.
+		Select("o.id", "o.user_id", "o.starts_at", "o.ends_at", "o.reason", "o.created_at").
+		From("user_out_of_office o").
+		Join("users u ON u.id = o.user_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.LtOrEq{"o.starts_at": nanos(at)}).
+		Where(sq.Gt{"o.ends_at": nanos(at)})
+
+	return r.selectOutOfOffice(ctx, query, "ListTeamOutOfOffice")
+}
+
+// ListTeamOutOfOfficeBetween returns periods of the team members that overlap [from, to).
+func (r *ScheduleRepository) ListTeamOutOfOfficeBetween(
+	ctx context.Context,
+	teamName string,
+	from, to time.Time,
+) ([]entity.OutOfOffice, error) {
+	query := r.sb.
+		Select("o.id", "o.user_id", "o.starts_at", "o.ends_at", "o.reason", "o.created_at").
+		From("user_out_of_office o").
+		Join("users u ON u.id = o.user_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.Lt{"o.starts_at": nanos(to)}).
+		Where(sq.Gt{"o.ends_at": nanos(from)}).
+		OrderBy("o.starts_at", "o.id")
+
+	return r.selectOutOfOffice(ctx, query, "ListTeamOutOfOfficeBetween")
+}
+
+func (r *ScheduleRepository) selectOutOfOffice(
+	ctx context.Context,
+	query sq.SelectBuilder,
+	method string,
+) ([]entity.OutOfOffice, error) {
+	rows, err := tryQuery(ctx, "ScheduleRepository."+method, query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduleRepository.%s failed to select out of office: %w", method, err)
+	}
+	defer rows.Close()
+
+	periods := make([]entity.OutOfOffice, 0)
+	for rows.Next() {
+		o, err := scanOutOfOffice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ScheduleRepository.%s failed to scan out of office: %w", method, err)
+		}
+		periods = append(periods, *o)
+	}
+
+	return periods, rows.Err()
+}
+
+func scanSchedule(row row) (*entity.WorkSchedule, error) {
+	var s entity.WorkSchedule
+	if err := row.Scan(&s.UserID, &s.Timezone, &s.WorkStart, &s.WorkEnd, scanTime(&s.UpdatedAt)); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func scanOutOfOffice(row row) (*entity.OutOfOffice, error) {
+	var o entity.OutOfOffice
+	err := row.Scan(&o.ID, &o.UserID, scanTime(&o.StartsAt), scanTime(&o.EndsAt), &o.Reason, scanTime(&o.CreatedAt))
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,229 @@
+// Package sqlite implements the repositories on top of an SQLite database file
+// using the schema from migrations/sqlite. Timestamps are stored as INTEGER
+// nanoseconds since the Unix epoch.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	moderncsqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+type row interface {
+	Scan(dest ...any) error
+}
+
+type rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type errRow struct {
+	err error
+}
+
+func (e errRow) Scan(_ ...any) error {
+	return e.err
+}
+
+type toSqler interface {
+	ToSql() (string, []any, error)
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func newBuilder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(sq.Question)
+}
+
+const (
+	dbSystem   = "sqlite"
+	spanPrefix = "sqlite."
+)
+
+// tryExec and the other try helpers run the query in a client span named
+// after the repository method op, e.g. "PRRepository.Create".
+func tryExec(ctx context.Context, op string, query toSqler, q queryer) error {
+	_, err := tryExecAffected(ctx, op, query, q)
+	return err
+}
+
+func tryExecAffected(ctx context.Context, op string, query toSqler, q queryer) (int64, error) {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	ctx, span := tracing.StartQuery(ctx, dbSystem, spanPrefix+op, sqlStr)
+	start := time.Now()
+	res, err := q.ExecContext(ctx, sqlStr, args...)
+	logQuery(ctx, sqlStr, start, err)
+	tracing.End(span, err)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// tryQueryRow ends the span once the row is scanned, as the result is read
+// lazily.
+func tryQueryRow(ctx context.Context, op string, query toSqler, q queryer) row {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return errRow{err: err}
+	}
+	ctx, span := tracing.StartQuery(ctx, dbSystem, spanPrefix+op, sqlStr)
+	start := time.Now()
+	res := q.QueryRowContext(ctx, sqlStr, args...)
+	logQuery(ctx, sqlStr, start, res.Err())
+	return tracedRow{row: res, span: span}
+}
+
+// tryQuery ends the span when the rows are closed.
+func tryQuery(ctx context.Context, op string, query toSqler, q queryer) (rows, error) {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := tracing.StartQuery(ctx, dbSystem, spanPrefix+op, sqlStr)
+	start := time.Now()
+	res, err := q.QueryContext(ctx, sqlStr, args...)
+	logQuery(ctx, sqlStr, start, err)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	return tracedRows{rows: res, span: span}, nil
+}
+
+// logQuery writes a debug record with the statement to the logger of the
+// request or job, arguments are left out as they may hold user data.
+func logQuery(ctx context.Context, statement string, start time.Time, err error) {
+	entry := logging.FromContext(ctx, nil)
+	if !entry.Logger.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+	entry = entry.WithFields(log.Fields{
+		"component":  "sqlite",
+		"statement":  statement,
+		"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Debug("Query executed")
+}
+
+type tracedRow struct {
+	row
+	span trace.Span
+}
+
+func (r tracedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		// not found is an expected outcome
+		tracing.End(r.span, nil)
+		return err
+	}
+	tracing.End(r.span, err)
+	return err
+}
+
+type tracedRows struct {
+	rows
+	span trace.Span
+}
+
+func (r tracedRows) Close() error {
+	err := r.rows.Close()
+	if err == nil {
+		err = r.rows.Err()
+	}
+	tracing.End(r.span, err)
+	return err
+}
+
+// isConstraint reports whether err is a violation of the given extended
+// constraint code, e.g. sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY.
+func isConstraint(err error, code int) bool {
+	var sqliteErr *moderncsqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+func isUniqueViolation(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) || isConstraint(err, sqlite3.SQLITE_CONSTRAINT_UNIQUE)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return isConstraint(err, sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY)
+}
+
+// nanos converts a timestamp to the stored representation.
+func nanos(t time.Time) int64 {
+	return t.UnixNano()
+}
+
+func nullNanos(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UnixNano()
+}
+
+type timeScanner struct {
+	dst *time.Time
+}
+
+func (s timeScanner) Scan(src any) error {
+	n, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("unexpected timestamp value %T", src)
+	}
+	*s.dst = time.Unix(0, n).UTC()
+	return nil
+}
+
+type nullTimeScanner struct {
+	dst **time.Time
+}
+
+func (s nullTimeScanner) Scan(src any) error {
+	if src == nil {
+		*s.dst = nil
+		return nil
+	}
+	var t time.Time
+	if err := (timeScanner{dst: &t}).Scan(src); err != nil {
+		return err
+	}
+	*s.dst = &t
+	return nil
+}
+
+// scanTime scans a stored timestamp into dst.
+func scanTime(dst *time.Time) sql.Scanner {
+	return timeScanner{dst: dst}
+}
+
+// scanNullTime scans a nullable stored timestamp into dst.
+func scanNullTime(dst **time.Time) sql.Scanner {
+	return nullTimeScanner{dst: dst}
+}
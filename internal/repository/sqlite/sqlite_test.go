@@ -0,0 +1,104 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"io"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/repotest"
+	reposqlite "github.com/Xausdorf/pr-reviewer-assignment/internal/repository/sqlite"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/migrate"
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/sqlite"
+)
+
+func openDB(t *testing.T, logger *log.Logger) *sql.DB {
+	t.Helper()
+
+	migrationsDir, err := filepath.Abs("../../../migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "test.db")
+	if err = migrate.RunSQLiteMigrations(path, migrationsDir, logger); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	db, err := sqlite.Open(t.Context(), sqlite.Config{Path: path}, logger)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlite.Close(db) })
+	return db
+}
+
+func TestRepositories(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	// every case gets its own database file
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db := openDB(t, logger)
+		return repotest.Repositories{
+			PR:           reposqlite.NewPRRepository(db),
+			Team:         reposqlite.NewTeamRepository(db),
+			User:         reposqlite.NewUserRepository(db),
+			Schedule:     reposqlite.NewScheduleRepository(db),
+			StatusChange: reposqlite.NewStatusChangeRepository(db),
+			SLA:          reposqlite.NewSLARepository(db),
+			Audit:        reposqlite.NewReviewerAuditRepository(db),
+			Decline:      reposqlite.NewDeclineRepository(db),
+			Org:          reposqlite.NewOrgRepository(db),
+		}
+	})
+}
+
+func TestQuerySpans(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	db := openDB(t, logger)
+
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	uc := usecase.NewTeamUseCase(reposqlite.NewTeamRepository(db), logger)
+	members := []entity.User{*entity.NewUser("u1", "Alice", "backend", true)}
+	if err := uc.AddTeam(t.Context(), *entity.NewTeam("backend"), members); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := uc.GetTeam(t.Context(), "backend", entity.Sort{}, entity.PageRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	parents := make(map[string]string, len(spans))
+	for _, s := range spans {
+		parents[s.SpanContext.SpanID().String()] = s.Name
+	}
+	queries := make(map[string]string)
+	for _, s := range spans {
+		if s.SpanKind == trace.SpanKindClient {
+			queries[s.Name] = parents[s.Parent.SpanID().String()]
+		}
+	}
+
+	want := map[string]string{
+		"sqlite.TeamRepository.CreateTeam":  "TeamUseCase.AddTeam",
+		"sqlite.TeamRepository.ListMembers": "TeamUseCase.GetTeam",
+	}
+	for query, parent := range want {
+		if queries[query] != parent {
+			t.Fatalf("query spans with parents = %v, want %s under %s", queries, query, parent)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type StatusChangeRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewStatusChangeRepository(db *sql.DB) *StatusChangeRepository {
+	return &StatusChangeRepository{db: db, sb: newBuilder()}
+}
+
+func (r *StatusChangeRepository) Create(ctx context.Context, c entity.StatusChange) (*entity.StatusChange, error) {
+	query := r.sb.
+		Insert("scheduled_status_changes").
+		Columns("user_id", "is_active", "apply_at", "created_at").
+		Values(c.UserID, c.IsActive, nanos(c.ApplyAt), nanos(c.CreatedAt)).
+		Suffix("RETURNING id, user_id, is_active, apply_at, applied_at, created_at")
+
+	row := tryQueryRow(ctx, "StatusChangeRepository.Create", query, r.db)
+
+	out, err := scanStatusChange(row)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("StatusChangeRepository.Create failed to insert status change: %w", err)
+	}
+
+	return out, nil
+}
+
+// ListPending returns not yet applied changes of the user ordered by apply time.
+func (r *StatusChangeRepository) ListPending(ctx context.Context, userID string) ([]entity.StatusChange, error) {
+	query := r.sb.
+		Select("id", "user_id", "is_active", "apply_at", "applied_at", "created_at").
+		From("scheduled_status_changes").
+		Where(sq.Eq{"user_id": userID, "applied_at": nil}).
+		OrderBy("apply_at", "id")
+
+	changes, err := r.selectStatusChanges(ctx, "StatusChangeRepository.ListPending", query)
+	if err != nil {
+		return nil, fmt.Errorf("StatusChangeRepository.ListPending failed to select status changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Cancel deletes a pending change. Already applied changes are reported as not found.
+func (r *StatusChangeRepository) Cancel(ctx context.Context, id int64) error {
+	query := r.sb.
+		Delete("scheduled_status_changes").
+		Where(sq.Eq{"id": id, "applied_at": nil})
+
+	affected, err := tryExecAffected(ctx, "StatusChangeRepository.Cancel", query, r.db)
+	if err != nil {
+		return fmt.Errorf("StatusChangeRepository.Cancel failed to delete status change: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+// ApplyDue passes up to limit changes due at now to apply and marks the
+// successful ones as applied; failed ones are retried on the next call.
+// SQLite has no row locks and the pool holds a single connection, so apply runs
+// outside of a transaction and the database file must not be shared between
+// replicas.
+func (r *StatusChangeRepository) ApplyDue(
+	ctx context.Context,
+	now time.Time,
+	limit uint64,
+	apply func(ctx context.Context, c entity.StatusChange) error,
+) (int, error) {
+	querySelect := r.sb.
+		Select("id", "user_id", "is_active", "apply_at", "applied_at", "created_at").
+		From("scheduled_status_changes").
+		Where(sq.Eq{"applied_at": nil}).
+		Where(sq.LtOrEq{"apply_at": nanos(now)}).
+		OrderBy("apply_at", "id").
+		Limit(limit)
+
+	due, err := r.selectStatusChanges(ctx, "StatusChangeRepository.ApplyDue", querySelect)
+	if err != nil {
+		return 0, fmt.Errorf("StatusChangeRepository.ApplyDue failed to select due changes: %w", err)
+	}
+
+	applied := make([]int64, 0, len(due))
+	var applyErr error
+	for _, c := range due {
+		if err = apply(ctx, c); err != nil {
+			applyErr = errors.Join(applyErr, fmt.Errorf("status change %d: %w", c.ID, err))
+			continue
+		}
+		applied = append(applied, c.ID)
+	}
+
+	if len(applied) > 0 {
+		queryMark := r.sb.
+			Update("scheduled_status_changes").
+			Set("applied_at", nanos(now)).
+			Where(sq.Eq{"id": applied})
+
+		if err = tryExec(ctx, "StatusChangeRepository.ApplyDue", queryMark, r.db); err != nil {
+			return 0, fmt.Errorf("StatusChangeRepository.ApplyDue failed to mark changes as applied: %w", err)
+		}
+	}
+
+	return len(applied), applyErr
+}
+
+func (r *StatusChangeRepository) selectStatusChanges(
+	ctx context.Context,
+	op string,
+	query sq.SelectBuilder,
+) ([]entity.StatusChange, error) {
+	rows, err := tryQuery(ctx, op, query, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]entity.StatusChange, 0)
+	for rows.Next() {
+		c, err := scanStatusChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *c)
+	}
+
+	return changes, rows.Err()
+}
+
+func scanStatusChange(row row) (*entity.StatusChange, error) {
+	var c entity.StatusChange
+	if err := row.Scan(&c.ID, &c.UserID, &c.IsActive, scanTime(&c.ApplyAt), scanNullTime(&c.AppliedAt),
+		scanTime(&c.CreatedAt)); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db, sb: newBuilder()}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, team entity.Team, members []entity.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := r.sb.
+		Insert("teams").
+		Columns("name", "created_at").
+		Values(team.Name, nanos(team.CreatedAt))
+
+	if err = tryExec(ctx, "TeamRepository.CreateTeam", query, tx); err != nil {
+		if isUniqueViolation(err) {
+			return apperror.ErrTeamExists
+		}
+		return fmt.Errorf("TeamRepository.CreateTeam failed to insert team: %w", err)
+	}
+
+	if len(members) > 0 {
+		queryAddUsers := r.sb.
+			Insert("users").
+			Columns("id", "team_name", "name", "is_active", "created_at")
+
+		for _, m := range members {
+			queryAddUsers = queryAddUsers.Values(m.ID, team.Name, m.Name, m.IsActive, nanos(m.CreatedAt))
+		}
+		queryAddUsers = queryAddUsers.Suffix("ON CONFLICT (id) DO UPDATE SET " +
+			"team_name = excluded.team_name, name = excluded.name, is_active = excluded.is_active")
+
+		if err = tryExec(ctx, "TeamRepository.CreateTeam", queryAddUsers, tx); err != nil {
+			return fmt.Errorf("TeamRepository.CreateTeam failed to insert or update team members: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *TeamRepository) GetTeam(ctx context.Context, name string) (*entity.Team, []entity.User, error) {
+	team, err := r.getTeam(ctx, "TeamRepository.GetTeam", name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, apperror.ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("TeamRepository.GetTeam failed to select team: %w", err)
+	}
+
+	queryUsers := r.sb.
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
+		From("users").
+		Where(sq.Eq{"team_name": name})
+
+	users, err := selectUsers(ctx, "TeamRepository.GetTeam", queryUsers, r.db)
+	if err != nil {
+		return team, nil, fmt.Errorf("TeamRepository.GetTeam failed to select team members: %w", err)
+	}
+
+	return team, users, nil
+}
+
+// ListMembers returns the team and one page of its members.
+func (r *TeamRepository) ListMembers(
+	ctx context.Context,
+	name string,
+	sort entity.Sort,
+	page entity.PageRequest,
+) (*entity.Team, entity.Page[entity.User], error) {
+	var (
+		key   sortKey
+		keyOf func(entity.User) (string, string)
+	)
+	switch sort.Field {
+	case "", entity.UserSortID:
+		key = textKey("id", "id", sort.Desc())
+		keyOf = func(u entity.User) (string, string) { return u.ID, u.ID }
+	case entity.UserSortName:
+		key = textKey("name", "id", sort.Desc())
+		keyOf = func(u entity.User) (string, string) { return u.Name, u.ID }
+	default:
+		return nil, entity.Page[entity.User]{}, fmt.Errorf(
+			"%w: unknown sort field %q", apperror.ErrInvalidListing, sort.Field)
+	}
+
+	team, err := r.getTeam(ctx, "TeamRepository.ListMembers", name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, entity.Page[entity.User]{}, apperror.ErrNotFound
+		}
+		return nil, entity.Page[entity.User]{}, fmt.Errorf("TeamRepository.ListMembers failed to select team: %w", err)
+	}
+
+	queryUsers, err := paginate(r.sb.
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
+		From("users").
+		Where(sq.Eq{"team_name": name}), key, page)
+	if err != nil {
+		return nil, entity.Page[entity.User]{}, err
+	}
+
+	users, err := selectUsers(ctx, "TeamRepository.ListMembers", queryUsers, r.db)
+	if err != nil {
+		return nil, entity.Page[entity.User]{}, fmt.Errorf(
+			"TeamRepository.ListMembers failed to select team members: %w", err)
+	}
+
+	return team, trimPage(users, page.Limit, keyOf), nil
+}
+
+// SetMaxOpenReviews sets the limit of concurrent open reviews of the team
+// members, nil removes it.
+func (r *TeamRepository) SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) error {
+	query := r.sb.
+		Update("teams").
+		Set("max_open_reviews", limit).
+		Where(sq.Eq{"name": teamName})
+
+	affected, err := tryExecAffected(ctx, "TeamRepository.SetMaxOpenReviews", query, r.db)
+	if err != nil {
+		return fmt.Errorf("TeamRepository.SetMaxOpenReviews failed to update team: %w", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListLoad returns the review load of every team ordered by name.
+func (r *TeamRepository) ListLoad(ctx context.Context) ([]entity.TeamLoad, error) {
+	query := r.sb.
+		Select("t.name").
+		Column(sq.Expr("(SELECT COUNT(*) FROM prs p JOIN users a ON a.id = p.author_id "+
+			"WHERE a.team_name = t.name AND p.status = ?)", entity.PRStatusOpen)).
+		Column(sq.Expr("(SELECT COUNT(*) FROM users u WHERE u.team_name = t.name AND u.is_active "+
+			"AND NOT EXISTS (SELECT 1 FROM pr_reviewers r JOIN prs p ON p.id = r.pr_id "+
+			"WHERE r.reviewer_id = u.id AND p.status = ?))", entity.PRStatusOpen)).
+		From("teams t").
+		OrderBy("t.name")
+
+	rows, err := tryQuery(ctx, "TeamRepository.ListLoad", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("TeamRepository.ListLoad failed to select team load: %w", err)
+	}
+	defer rows.Close()
+
+	loads := make([]entity.TeamLoad, 0)
+	for rows.Next() {
+		var l entity.TeamLoad
+		if err = rows.Scan(&l.TeamName, &l.OpenPRs, &l.IdleReviewers); err != nil {
+			return nil, fmt.Errorf("TeamRepository.ListLoad failed to scan team load: %w", err)
+		}
+		loads = append(loads, l)
+	}
+
+	return loads, rows.Err()
+}
+
+func (r *TeamRepository) GetTeamForUser(ctx context.Context, userID string) (string, error) {
+	query := r.sb.
+		Select("team_name").
+		From("users").
+		Where(sq.Eq{"id": userID})
+
+	row := tryQueryRow(ctx, "TeamRepository.GetTeamForUser", query, r.db)
+
+	var teamName string
+	if err := row.Scan(&teamName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperror.ErrNotFound
+		}
+		return "", err
+	}
+
+	return teamName, nil
+}
+
+func (r *TeamRepository) getTeam(ctx context.Context, op, name string) (*entity.Team, error) {
+	query := r.sb.
+		Select("name", "created_at", "max_open_reviews").
+		From("teams").
+		Where(sq.Eq{"name": name})
+
+	row := tryQueryRow(ctx, op, query, r.db)
+
+	var team entity.Team
+	if err := row.Scan(&team.Name, scanTime(&team.CreatedAt), &team.MaxOpenReviews); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
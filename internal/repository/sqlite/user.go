@@ -0,0 +1,192 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+type UserRepository struct {
+	db *sql.DB
+	sb sq.StatementBuilderType
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db, sb: newBuilder()}
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error) {
+	query := r.sb.
+		Update("users").
+		Set("is_active", isActive).
+		Where(sq.Eq{"id": userID}).
+		Suffix("RETURNING id, team_name, name, is_active, created_at, max_open_reviews")
+
+	row := tryQueryRow(ctx, "UserRepository.SetIsActive", query, r.db)
+
+	user, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("UserRepository.SetIsActive failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+// SetMaxOpenReviews sets the user's own limit of concurrent open reviews, nil
+// falls back to the team limit.
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*entity.User, error) {
+	query := r.sb.
+		Update("users").
+		Set("max_open_reviews", limit).
+		Where(sq.Eq{"id": userID}).
+		Suffix("RETURNING id, team_name, name, is_active, created_at, max_open_reviews")
+
+	row := tryQueryRow(ctx, "UserRepository.SetMaxOpenReviews", query, r.db)
+
+	user, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("UserRepository.SetMaxOpenReviews failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) ListAssignedTo(
+	ctx context.Context,
+	userID string,
+	opts entity.PRListOptions,
+) (entity.Page[entity.PR], error) {
+	key, keyOf, err := prSortKey("p", opts.Sort)
+	if err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	query := r.sb.
+		Select("p.id", "p.title", "p.author_id", "p.status", "p.created_at", "p.merged_at").
+		From("prs p").
+		Join("pr_reviewers r ON p.id = r.pr_id").
+		Where(sq.Eq{"r.reviewer_id": userID})
+	query = applyPRFilter(query, "p", opts.Filter)
+	if query, err = paginate(query, key, opts.Page); err != nil {
+		return entity.Page[entity.PR]{}, err
+	}
+
+	prs, err := selectPRs(ctx, "UserRepository.ListAssignedTo", query, r.db)
+	if err != nil {
+		return entity.Page[entity.PR]{}, fmt.Errorf(
+			"UserRepository.ListAssignedTo failed to select assigned PRs: %w", err)
+	}
+
+	return trimPage(prs, opts.Page.Limit, keyOf), nil
+}
+
+func (r *UserRepository) IsAssignedToPR(ctx context.Context, userID, prID string) (bool, error) {
+	query := r.sb.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("pr_reviewers").
+		Where(sq.Eq{"pr_id": prID, "reviewer_id": userID}).
+		Suffix(")")
+
+	row := tryQueryRow(ctx, "UserRepository.IsAssignedToPR", query, r.db)
+
+	var exists bool
+	if err := row.Scan(&exists); err != nil {
+		return false, fmt.Errorf("UserRepository.IsAssignedToPR failed to check assignment: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID string) (*entity.User, error) {
+	query := r.sb.
+		Select("id", "team_name", "name", "is_active", "created_at", "max_open_reviews").
+		From("users").
+		Where(sq.Eq{"id": userID})
+
+	row := tryQueryRow(ctx, "UserRepository.GetByID", query, r.db)
+
+	user, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.ErrNotFound
+		}
+		return nil, fmt.Errorf("UserRepository.GetByID failed to select user: %w", err)
+	}
+
+	return user, nil
+}
+
+// scanUser scans the id, team_name, name, is_active, created_at and max_open_reviews columns.
+func scanUser(row row) (*entity.User, error) {
+	var u entity.User
+	if err := row.Scan(&u.ID, &u.TeamName, &u.Name, &u.IsActive, scanTime(&u.CreatedAt), &u.MaxOpenReviews); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func selectUsers(ctx context.Context, op string, query sq.SelectBuilder, q queryer) ([]entity.User, error) {
+	rows, err := tryQuery(ctx, op, query, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]entity.User, 0)
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+
+	return users, rows.Err()
+}
+
+// ListTeamStatusHistory returns changes of is_active of the team members made
+// in [from, to), oldest first.
+func (r *UserRepository) ListTeamStatusHistory(
+	ctx context.Context,
+	teamName string,
+	from, to time.Time,
+) ([]entity.StatusHistoryEntry, error) {
+	query := r.sb.
+		Select("h.user_id", "h.is_active", "h.changed_at").
+		From("user_status_history h").
+		Join("users u ON u.id = h.user_id").
+		Where(sq.Eq{"u.team_name": teamName}).
+		Where(sq.GtOrEq{"h.changed_at": nanos(from)}).
+		Where(sq.Lt{"h.changed_at": nanos(to)}).
+		OrderBy("h.changed_at", "h.id")
+
+	rows, err := tryQuery(ctx, "UserRepository.ListTeamStatusHistory", query, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("UserRepository.ListTeamStatusHistory failed to select status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]entity.StatusHistoryEntry, 0)
+	for rows.Next() {
+		var h entity.StatusHistoryEntry
+		if err = rows.Scan(&h.UserID, &h.IsActive, scanTime(&h.ChangedAt)); err != nil {
+			return nil, fmt.Errorf("UserRepository.ListTeamStatusHistory failed to scan status history: %w", err)
+		}
+		history = append(history, h)
+	}
+
+	return history, rows.Err()
+}
@@ -0,0 +1,61 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+func TestMiddlewareContinuesTraceparent(t *testing.T) {
+	shutdown, err := tracing.Setup(t.Context(), tracing.ExporterNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = shutdown(t.Context()) })
+
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	router := chi.NewRouter()
+	router.With(tracing.Middleware).Get("/team/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "TeamUseCase.GetTeam")
+		span.End()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/team/backend", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want the usecase and the server span", len(spans))
+	}
+	inner, server := spans[0], spans[1]
+	if server.Name != "GET /team/{name}" || server.SpanKind != trace.SpanKindServer {
+		t.Fatalf("server span = %q of kind %v", server.Name, server.SpanKind)
+	}
+	if got := server.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace id = %s, want the one from traceparent", got)
+	}
+	if got := server.Parent.SpanID().String(); got != "00f067aa0ba902b7" || !server.Parent.IsRemote() {
+		t.Fatalf("parent span = %s, want the remote span from traceparent", got)
+	}
+	if inner.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Fatal("usecase span is not a child of the server span")
+	}
+	if server.Status.Code != codes.Error {
+		t.Fatalf("status = %v, want an error for 503", server.Status.Code)
+	}
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+)
+
+func ValidCapacityPolicy(policy string) bool {
+	switch policy {
+	case entity.CapacityPolicyAllow, entity.CapacityPolicyUnderstaff, entity.CapacityPolicyFail:
+		return true
+	default:
+		return false
+	}
+}
+
+func validateCapacity(limit *int) error {
+	if limit != nil && *limit < 0 {
+		return fmt.Errorf("%w: max_open_reviews must not be negative", apperror.ErrInvalidCapacity)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+// DeclineReview replaces the assigned reviewer who refuses the review of the PR
+// like ReassignReviewer does and records the decline, so the decliner is not
+// picked for the PR again. The replacement and the decline are stored in one
+// repository call, so a failure changes nothing.
+func (s *PRUseCase) DeclineReview(
+	ctx context.Context,
+	prID, reviewerID, reason, comment string,
+) (_ *entity.PR, _ *entity.Decline, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.DeclineReview")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":       prID,
+		"reviewerID": reviewerID,
+		"reason":     reason,
+	}).Info("PRUseCase - declining review")
+	if !entity.ValidDeclineReason(reason) {
+		return nil, nil, fmt.Errorf("%w: reason must be one of %s", apperror.ErrInvalidDecline,
+			strings.Join(entity.DeclineReasons, ", "))
+	}
+
+	var (
+		pr      *entity.PR
+		decline *entity.Decline
+	)
+	for attempt := 1; ; attempt++ {
+		var sim *entity.AssignmentSimulation
+		pr, sim, err = s.planReassign(ctx, prID, reviewerID, entity.ReassignOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(sim.Reviewers) == 0 {
+			s.metrics.NoCandidate(OperationReassign)
+			return nil, nil, apperror.ErrNoCandidate
+		}
+
+		d := entity.NewDecline(prID, reviewerID, reason, comment, sim.Reviewers[0])
+		decline, err = s.declineRepo.Record(ctx, *d)
+		if err == nil {
+			break
+		}
+		if !s.retryPick(ctx, err, true, attempt) {
+			return nil, nil, err
+		}
+	}
+	s.metrics.ReviewerReassigned()
+
+	return pr, decline, nil
+}
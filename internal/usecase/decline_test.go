@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+func TestDeclinedReviewerIsNotPickedAgain(t *testing.T) {
+	uc := newPRUseCase(t, usecase.FixedSeeder(3))
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decliner := assigned[0]
+
+	_, _, err = uc.DeclineReview(t.Context(), "pr-1", decliner, "BORED", "")
+	if !errors.Is(err, apperror.ErrInvalidDecline) {
+		t.Fatalf("unknown reason: error = %v, want %v", err, apperror.ErrInvalidDecline)
+	}
+	_, _, err = uc.DeclineReview(t.Context(), "pr-1", "u1", entity.DeclineReasonOther, "")
+	if !errors.Is(err, apperror.ErrNotAssigned) {
+		t.Fatalf("declining by the author: error = %v, want %v", err, apperror.ErrNotAssigned)
+	}
+
+	_, decline, err := uc.DeclineReview(t.Context(), "pr-1", decliner, entity.DeclineReasonConflictOfInterest, "mine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decline.ReviewerID != decliner || decline.Comment != "mine" || decline.NewReviewerID == decliner {
+		t.Fatalf("decline = %+v", decline)
+	}
+
+	reviewers, err := uc.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(reviewers, decliner) || !slices.Contains(reviewers, decline.NewReviewerID) {
+		t.Fatalf("reviewers = %v, want %s replaced by %s", reviewers, decliner, decline.NewReviewerID)
+	}
+
+	sim, err := uc.SimulateReassignReviewer(t.Context(), "pr-1", decline.NewReviewerID, entity.ReassignOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range sim.Candidates {
+		if c.ID == decliner && c.ExclusionReason != entity.ExclusionDeclined {
+			t.Fatalf("decliner exclusion = %q, want %q", c.ExclusionReason, entity.ExclusionDeclined)
+		}
+	}
+	if slices.Contains(sim.Reviewers, decliner) {
+		t.Fatalf("decliner %s picked again", decliner)
+	}
+}
+
+type failingDeclineRepository struct {
+	usecase.DeclineRepository
+}
+
+func (failingDeclineRepository) Record(context.Context, entity.Decline) (*entity.Decline, error) {
+	return nil, errors.New("storage is down")
+}
+
+func TestFailedDeclineKeepsReviewers(t *testing.T) {
+	_, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{})
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	uc := usecase.NewPRUseCase(memory.NewPRRepository(store), memory.NewUserRepository(store),
+		memory.NewTeamRepository(store), memory.NewScheduleRepository(store), memory.NewReviewerAuditRepository(store),
+		failingDeclineRepository{memory.NewDeclineRepository(store)},
+		usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(3)}, nil, logger)
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = uc.DeclineReview(t.Context(), "pr-1", assigned[0], entity.DeclineReasonNoTime, ""); err == nil {
+		t.Fatal("decline succeeded while the storage is down")
+	}
+
+	reviewers, err := uc.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(assigned)
+	slices.Sort(reviewers)
+	if !slices.Equal(reviewers, assigned) {
+		t.Fatalf("reviewers = %v after a failed decline, want %v", reviewers, assigned)
+	}
+	removed, err := memory.NewPRRepository(store).ListRemovedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("removed reviewers = %v after a failed decline, want none", removed)
+	}
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+const DefaultFairnessWindow = 30 * 24 * time.Hour
+
+func ValidSelectionMode(mode string) bool {
+	return mode == entity.SelectionModeRandom || mode == entity.SelectionModeFairness
+}
+
+// GetFairnessLedger returns assignments of the team members over the fairness
+// window compared to their fair share.
+func (s *PRUseCase) GetFairnessLedger(ctx context.Context, teamName string) (_ *entity.FairnessLedger, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.GetFairnessLedger")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("team", teamName).Info("PRUseCase - getting fairness ledger")
+	return s.fairnessLedger(ctx, teamName, s.policy.forTeam(teamName).FairnessWindow, time.Now().UTC())
+}
+
+func (s *PRUseCase) fairnessLedger(
+	ctx context.Context,
+	teamName string,
+	window time.Duration,
+	now time.Time,
+) (*entity.FairnessLedger, error) {
+	_, members, err := s.teamRepo.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	from := now.Add(-window)
+	counts, err := s.prRepo.CountAssignments(ctx, teamName, from)
+	if err != nil {
+		return nil, err
+	}
+	away, err := s.scheduleRepo.ListTeamOutOfOfficeBetween(ctx, teamName, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.userRepo.ListTeamStatusHistory(ctx, teamName, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := entity.NewFairnessLedger(teamName, members, counts, away, history, from, now)
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"team":  teamName,
+		"total": ledger.Total,
+	}).Debug("PRUseCase - computed fairness ledger")
+	return ledger, nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+const (
+	OperationCreate      = "create"
+	OperationReassign    = "reassign"
+	OperationAddReviewer = "add_reviewer"
+)
+
+// PRMetrics receives outcomes of reviewer assignment.
+type PRMetrics interface {
+	ReviewersAssigned(n int)
+	ReviewerReassigned()
+	// NoCandidate counts operations that found nobody to assign, one of Operation*.
+	NoCandidate(operation string)
+	PRMerged()
+}
+
+// LoadMetrics publishes the review load of teams.
+type LoadMetrics interface {
+	SetTeamLoad(loads []entity.TeamLoad)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ReviewersAssigned(int) {}
+func (nopMetrics) ReviewerReassigned()   {}
+func (nopMetrics) NoCandidate(string)    {}
+func (nopMetrics) PRMerged()             {}
+
+type LoadUseCase struct {
+	teamRepo TeamRepository
+	metrics  LoadMetrics
+	log      *log.Logger
+}
+
+func NewLoadUseCase(team TeamRepository, metrics LoadMetrics, logger *log.Logger) *LoadUseCase {
+	return &LoadUseCase{teamRepo: team, metrics: metrics, log: logger}
+}
+
+// RefreshLoad publishes the current review load of every team.
+func (s *LoadUseCase) RefreshLoad(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "LoadUseCase.RefreshLoad")
+	defer func() { tracing.End(span, err) }()
+
+	loads, err := s.teamRepo.ListLoad(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.metrics.SetTeamLoad(loads)
+	return nil
+}
@@ -0,0 +1,63 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+type recordedMetrics struct {
+	assigned    int
+	reassigned  int
+	noCandidate map[string]int
+	merged      int
+}
+
+func (m *recordedMetrics) ReviewersAssigned(n int)      { m.assigned += n }
+func (m *recordedMetrics) ReviewerReassigned()          { m.reassigned++ }
+func (m *recordedMetrics) NoCandidate(operation string) { m.noCandidate[operation]++ }
+func (m *recordedMetrics) PRMerged()                    { m.merged++ }
+
+func TestPRUseCaseRecordsMetrics(t *testing.T) {
+	metrics := &recordedMetrics{noCandidate: make(map[string]int)}
+	uc, store := newPRUseCaseWithMetrics(t, usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(1)}, metrics)
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = uc.ReassignReviewer(t.Context(), "pr-1", assigned[0], entity.ReassignOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// nobody else is left in the team
+	for _, id := range []string{"u2", "u3", "u5", "u6"} {
+		if _, err = memory.NewUserRepository(store).SetIsActive(t.Context(), id, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-2", "Fix bug", "u1")); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = uc.ReassignReviewer(t.Context(), "pr-1", assigned[1], entity.ReassignOptions{})
+	if !errors.Is(err, apperror.ErrNoCandidate) {
+		t.Fatalf("error = %v, want %v", err, apperror.ErrNoCandidate)
+	}
+
+	for range 2 {
+		if _, err = uc.MergePullRequest(t.Context(), "pr-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if metrics.assigned != 2 || metrics.reassigned != 1 || metrics.merged != 1 {
+		t.Fatalf("metrics = %+v, want 2 assigned, 1 reassigned and 1 merged", metrics)
+	}
+	if metrics.noCandidate[usecase.OperationCreate] != 1 || metrics.noCandidate[usecase.OperationReassign] != 1 {
+		t.Fatalf("no candidate = %v, want one create and one reassign", metrics.noCandidate)
+	}
+}
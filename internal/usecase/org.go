@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+// openReviewsPageSize is the page size used to collect open reviews of
+// deactivated users.
+const openReviewsPageSize = 200
+
+// ReviewReassigner picks replacements for open reviews of deactivated users.
+// It is satisfied by PRUseCase.
+type ReviewReassigner interface {
+	SimulateReassignReviewer(
+		ctx context.Context,
+		prID, oldUserID string,
+		opts entity.ReassignOptions,
+	) (*entity.AssignmentSimulation, error)
+}
+
+type OrgUseCase struct {
+	orgRepo    OrgRepository
+	userRepo   UserRepository
+	reassigner ReviewReassigner
+	log        *log.Logger
+}
+
+func NewOrgUseCase(
+	org OrgRepository,
+	user UserRepository,
+	reassigner ReviewReassigner,
+	logger *log.Logger,
+) *OrgUseCase {
+	return &OrgUseCase{orgRepo: org, userRepo: user, reassigner: reassigner, log: logger}
+}
+
+// Sync brings the stored teams and users to the manifest, which lists every
+// team with all of its members. Open reviews of deactivated users get their
+// replacements in the plan, and the roster changes are written together with
+// the reassignments in one transaction: if a review has no replacement or the
+// reviewers changed since the plan was built, nothing is applied. With dryRun
+// only the plan is returned.
+//
+// Users moved to another team keep their reviews: they stay active and can
+// finish the reviews they started, and reviewers are picked from the author's
+// team only for new assignments.
+func (s *OrgUseCase) Sync(
+	ctx context.Context,
+	manifest []entity.OrgTeam,
+	dryRun bool,
+) (_ *entity.OrgSyncResult, err error) {
+	ctx, span := tracing.Start(ctx, "OrgUseCase.Sync")
+	defer func() { tracing.End(span, err) }()
+
+	logger := logging.FromContext(ctx, s.log)
+	logger.WithFields(log.Fields{
+		"teams":  len(manifest),
+		"dryRun": dryRun,
+	}).Info("OrgUseCase - syncing org")
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.plan(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+	result := &entity.OrgSyncResult{Plan: *plan, DryRun: dryRun}
+	if dryRun || plan.Empty() {
+		return result, nil
+	}
+
+	if err = s.orgRepo.ApplyOrgPlan(ctx, *plan); err != nil {
+		return nil, err
+	}
+	logger.WithFields(log.Fields{
+		"addedTeams":   len(plan.AddTeams),
+		"removedTeams": len(plan.RemoveTeams),
+		"users":        len(plan.Users),
+		"reassigned":   len(plan.Reassign),
+	}).Info("OrgUseCase - applied org plan")
+
+	return result, nil
+}
+
+// Export returns every team with all of its members, the manifest Sync
+// would leave unchanged.
+func (s *OrgUseCase) Export(ctx context.Context) (_ []entity.OrgTeam, err error) {
+	ctx, span := tracing.Start(ctx, "OrgUseCase.Export")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).Info("OrgUseCase - exporting org")
+	return s.orgRepo.ListOrg(ctx)
+}
+
+// validateManifest requires unique team names and every user to be listed once.
+func validateManifest(manifest []entity.OrgTeam) error {
+	teams := make(map[string]bool, len(manifest))
+	users := make(map[string]string)
+	for _, t := range manifest {
+		if t.Name == "" {
+			return fmt.Errorf("%w: team_name is required", apperror.ErrInvalidManifest)
+		}
+		if teams[t.Name] {
+			return fmt.Errorf("%w: team %s is listed twice", apperror.ErrInvalidManifest, t.Name)
+		}
+		teams[t.Name] = true
+
+		for _, m := range t.Members {
+			if m.ID == "" {
+				return fmt.Errorf("%w: user_id is required for each member of team %s",
+					apperror.ErrInvalidManifest, t.Name)
+			}
+			if other, ok := users[m.ID]; ok {
+				return fmt.Errorf("%w: user %s is listed in teams %s and %s",
+					apperror.ErrInvalidManifest, m.ID, other, t.Name)
+			}
+			users[m.ID] = t.Name
+		}
+	}
+	return nil
+}
+
+// plan diffs the manifest against the stored roster.
+func (s *OrgUseCase) plan(ctx context.Context, manifest []entity.OrgTeam) (*entity.OrgPlan, error) {
+	current, err := s.orgRepo.ListOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]entity.User)
+	for _, t := range current {
+		for _, u := range t.Members {
+			stored[u.ID] = u
+		}
+	}
+
+	plan := &entity.OrgPlan{}
+	listed := make(map[string]bool, len(manifest))
+	// members is the number of users in each team after the sync
+	members := make(map[string]int)
+	for _, t := range manifest {
+		listed[t.Name] = true
+		if !slices.ContainsFunc(current, func(c entity.OrgTeam) bool { return c.Name == t.Name }) {
+			plan.AddTeams = append(plan.AddTeams, t.Name)
+		}
+
+		for _, m := range t.Members {
+			members[t.Name]++
+			before, ok := stored[m.ID]
+			delete(stored, m.ID)
+			if !ok {
+				m.TeamName = t.Name
+				plan.Users = append(plan.Users, entity.UserChange{After: m})
+				continue
+			}
+
+			after := before
+			after.TeamName, after.Name, after.IsActive = t.Name, m.Name, m.IsActive
+			if after.TeamName != before.TeamName || after.Name != before.Name || after.IsActive != before.IsActive {
+				plan.Users = append(plan.Users, entity.UserChange{Before: &before, After: after})
+			}
+		}
+	}
+
+	// users missing from the manifest keep their team and are deactivated
+	for _, u := range stored {
+		members[u.TeamName]++
+		if u.IsActive {
+			after := u
+			after.IsActive = false
+			plan.Users = append(plan.Users, entity.UserChange{Before: &u, After: after})
+		}
+	}
+	for _, t := range current {
+		if !listed[t.Name] && members[t.Name] == 0 {
+			plan.RemoveTeams = append(plan.RemoveTeams, t.Name)
+		}
+	}
+
+	// leaving can not take over reviews of their team after the sync
+	var leaving []string
+	for _, c := range plan.Users {
+		if c.Before == nil {
+			continue
+		}
+		if !c.After.IsActive || c.After.TeamName != c.Before.TeamName {
+			leaving = append(leaving, c.After.ID)
+		}
+		if !c.Before.IsActive || c.After.IsActive {
+			continue
+		}
+		reviews, err := s.openReviews(ctx, *c.Before)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reviews {
+			plan.Reassign = append(plan.Reassign, entity.Reassignment{OpenReview: r})
+		}
+	}
+
+	slices.Sort(plan.AddTeams)
+	slices.Sort(plan.RemoveTeams)
+	slices.SortFunc(plan.Users, func(a, b entity.UserChange) int { return strings.Compare(a.After.ID, b.After.ID) })
+	slices.SortFunc(plan.Reassign, func(a, b entity.Reassignment) int {
+		return cmp.Or(strings.Compare(a.PRID, b.PRID), strings.Compare(a.ReviewerID, b.ReviewerID))
+	})
+	if err = s.pickReplacements(ctx, plan.Reassign, leaving); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// pickReplacements picks a member of the reviewer's team for every review.
+// Users in leaving and replacements already picked for the same PR are
+// skipped. A review without a replacement fails the whole sync.
+func (s *OrgUseCase) pickReplacements(ctx context.Context, reviews []entity.Reassignment, leaving []string) error {
+	picked := make(map[string][]string)
+	for i := range reviews {
+		r := &reviews[i]
+		sim, err := s.reassigner.SimulateReassignReviewer(ctx, r.PRID, r.ReviewerID, entity.ReassignOptions{
+			CandidateTeam: r.TeamName,
+			Exclude:       slices.Concat(leaving, picked[r.PRID]),
+		})
+		if err == nil && len(sim.Reviewers) == 0 {
+			err = apperror.ErrNoCandidate
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reassign review of %s by %s: %w", r.PRID, r.ReviewerID, err)
+		}
+		r.ReplacedBy = sim.Reviewers[0]
+		picked[r.PRID] = append(picked[r.PRID], r.ReplacedBy)
+	}
+	return nil
+}
+
+// openReviews returns the open PRs the user reviews.
+func (s *OrgUseCase) openReviews(ctx context.Context, user entity.User) ([]entity.OpenReview, error) {
+	opts := entity.PRListOptions{
+		Filter: entity.PRFilter{Status: entity.PRStatusOpen},
+		Page:   entity.PageRequest{Limit: openReviewsPageSize},
+	}
+
+	var reviews []entity.OpenReview
+	for {
+		page, err := s.userRepo.ListAssignedTo(ctx, user.ID, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range page.Items {
+			reviews = append(reviews, entity.OpenReview{PRID: pr.ID, ReviewerID: user.ID, TeamName: user.TeamName})
+		}
+		if page.NextCursor == "" {
+			return reviews, nil
+		}
+		opts.Page.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,252 @@
+package usecase_test
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+func newOrgUseCase(t *testing.T) (*usecase.OrgUseCase, *usecase.PRUseCase, *memory.Store) {
+	t.Helper()
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	prUseCase, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(3)})
+	org := usecase.NewOrgUseCase(memory.NewOrgRepository(store), memory.NewUserRepository(store), prUseCase, logger)
+	return org, prUseCase, store
+}
+
+// backendManifest lists the seeded backend team without the given members.
+func backendManifest(without ...string) entity.OrgTeam {
+	team := entity.OrgTeam{Name: "backend"}
+	for _, u := range []entity.User{
+		*entity.NewUser("u1", "Alice", "backend", true),
+		*entity.NewUser("u2", "Bob", "backend", true),
+		*entity.NewUser("u3", "Carol", "backend", true),
+		*entity.NewUser("u4", "Dave", "backend", false),
+		*entity.NewUser("u5", "Eve", "backend", true),
+		*entity.NewUser("u6", "Frank", "backend", true),
+	} {
+		if !slices.Contains(without, u.ID) {
+			team.Members = append(team.Members, u)
+		}
+	}
+	return team
+}
+
+func TestSyncWithoutChanges(t *testing.T) {
+	org, _, _ := newOrgUseCase(t)
+
+	result, err := org.Sync(t.Context(), []entity.OrgTeam{backendManifest()}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Plan.Empty() {
+		t.Fatalf("plan = %+v, want no changes", result.Plan)
+	}
+}
+
+func TestSyncPlanAndApply(t *testing.T) {
+	org, prUseCase, store := newOrgUseCase(t)
+	reviewers, err := prUseCase.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaving := reviewers[0]
+
+	// the leaving reviewer is dropped, u6 moves to a new team with a new user
+	manifest := []entity.OrgTeam{
+		backendManifest(leaving, "u6"),
+		{Name: "platform", Members: []entity.User{
+			*entity.NewUser("u6", "Frank", "", true),
+			*entity.NewUser("p1", "Paula", "", true),
+		}},
+	}
+
+	dry, err := org.Sync(t.Context(), manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := dry.Plan
+	if !slices.Equal(plan.AddTeams, []string{"platform"}) || len(plan.RemoveTeams) != 0 {
+		t.Fatalf("teams to add %v, to remove %v", plan.AddTeams, plan.RemoveTeams)
+	}
+	changes := make(map[string]entity.UserChange)
+	for _, c := range plan.Users {
+		changes[c.After.ID] = c
+	}
+	if len(changes) != 3 || changes["p1"].Before != nil || changes["u6"].After.TeamName != "platform" ||
+		changes[leaving].After.IsActive {
+		t.Fatalf("user changes = %+v", plan.Users)
+	}
+	want := entity.OpenReview{PRID: "pr-1", ReviewerID: leaving, TeamName: "backend"}
+	if len(plan.Reassign) != 1 || plan.Reassign[0].OpenReview != want {
+		t.Fatalf("reviews to reassign = %+v, want %+v", plan.Reassign, want)
+	}
+	// u6 leaves backend, so it can not take over the review
+	replacement := plan.Reassign[0].ReplacedBy
+	if slices.Contains(slices.Concat(reviewers, []string{"u1", "u4", "u6", ""}), replacement) {
+		t.Fatalf("review of %s is planned for %q", leaving, replacement)
+	}
+
+	if _, err = memory.NewUserRepository(store).GetByID(t.Context(), "p1"); !errors.Is(err, apperror.ErrNotFound) {
+		t.Fatalf("dry run created a user: %v", err)
+	}
+
+	if _, err = org.Sync(t.Context(), manifest, false); err != nil {
+		t.Fatal(err)
+	}
+
+	assigned, err := prUseCase.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(assigned, leaving) || !slices.Contains(assigned, replacement) {
+		t.Fatalf("reviewers = %v after %s left, want %s instead", assigned, leaving, replacement)
+	}
+
+	// the second run has nothing left to do
+	again, err := org.Sync(t.Context(), manifest, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Plan.Empty() {
+		t.Fatalf("plan after sync = %+v, want no changes", again.Plan)
+	}
+}
+
+func TestSyncPicksDistinctReplacements(t *testing.T) {
+	org, prUseCase, _ := newOrgUseCase(t)
+	reviewers, err := prUseCase.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both reviewers leave, the two remaining active members take over
+	result, err := org.Sync(t.Context(), []entity.OrgTeam{backendManifest(reviewers...)}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := slices.DeleteFunc([]string{"u2", "u3", "u5", "u6"}, func(id string) bool {
+		return slices.Contains(reviewers, id)
+	})
+	replacements := make([]string, 0, len(result.Plan.Reassign))
+	for _, r := range result.Plan.Reassign {
+		replacements = append(replacements, r.ReplacedBy)
+	}
+	slices.Sort(replacements)
+	if !slices.Equal(replacements, want) {
+		t.Fatalf("replacements = %v, want %v", replacements, want)
+	}
+
+	assigned, err := prUseCase.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(assigned)), want) {
+		t.Fatalf("reviewers = %v, want %v", assigned, want)
+	}
+}
+
+func TestSyncFailsWithoutReplacement(t *testing.T) {
+	org, prUseCase, store := newOrgUseCase(t)
+	reviewers, err := prUseCase.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the author stays active, nobody can take over the reviews
+	manifest := []entity.OrgTeam{backendManifest("u2", "u3", "u5", "u6")}
+	for _, dryRun := range []bool{true, false} {
+		if _, err = org.Sync(t.Context(), manifest, dryRun); !errors.Is(err, apperror.ErrNoCandidate) {
+			t.Fatalf("sync with dry run %t: %v, want %v", dryRun, err, apperror.ErrNoCandidate)
+		}
+	}
+
+	// nothing is applied
+	u, err := memory.NewUserRepository(store).GetByID(t.Context(), reviewers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.IsActive {
+		t.Fatalf("%s is deactivated by a failed sync", u.ID)
+	}
+	assigned, err := prUseCase.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(assigned)), slices.Sorted(slices.Values(reviewers))) {
+		t.Fatalf("reviewers = %v, want %v kept", assigned, reviewers)
+	}
+}
+
+func TestSyncRemovesEmptyTeams(t *testing.T) {
+	org, _, _ := newOrgUseCase(t)
+
+	// everyone moves to platform, backend is left empty
+	platform := backendManifest()
+	platform.Name = "platform"
+	result, err := org.Sync(t.Context(), []entity.OrgTeam{platform}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(result.Plan.RemoveTeams, []string{"backend"}) {
+		t.Fatalf("teams to remove = %v, want backend", result.Plan.RemoveTeams)
+	}
+
+	// a team missing from the manifest keeps its deactivated members
+	result, err = org.Sync(t.Context(), []entity.OrgTeam{{Name: "frontend"}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Plan.RemoveTeams) != 0 || len(result.Plan.Users) != 5 {
+		t.Fatalf("plan = %+v, want platform kept and its 5 active members deactivated", result.Plan)
+	}
+}
+
+func TestSyncRejectsInvalidManifest(t *testing.T) {
+	org, _, _ := newOrgUseCase(t)
+
+	tests := map[string][]entity.OrgTeam{
+		"no team name":    {{Members: []entity.User{*entity.NewUser("u1", "Alice", "", true)}}},
+		"duplicate team":  {backendManifest(), backendManifest()},
+		"user in 2 teams": {backendManifest(), {Name: "platform", Members: []entity.User{{ID: "u1"}}}},
+		"no user id":      {{Name: "platform", Members: []entity.User{{Name: "Paula"}}}},
+	}
+	for name, manifest := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := org.Sync(t.Context(), manifest, true); !errors.Is(err, apperror.ErrInvalidManifest) {
+				t.Fatalf("error = %v, want %v", err, apperror.ErrInvalidManifest)
+			}
+		})
+	}
+}
+
+func TestExportIsSyncedManifest(t *testing.T) {
+	org, _, _ := newOrgUseCase(t)
+
+	teams, err := org.Export(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(teams) != 1 || teams[0].Name != "backend" || len(teams[0].Members) != 6 {
+		t.Fatalf("exported teams = %+v, want the seeded backend team", teams)
+	}
+
+	result, err := org.Sync(t.Context(), teams, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Plan.Empty() {
+		t.Fatalf("plan for the exported org = %+v, want no changes", result.Plan)
+	}
+}
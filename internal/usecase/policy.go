@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync/atomic"
+	"time"
+)
+
+// TeamPolicy overrides the selection policy for reviewers picked from a team,
+// zero fields keep the global value.
+type TeamPolicy struct {
+	Mode           string
+	MaxReviewers   int
+	OverCapacity   string
+	FairnessWindow time.Duration
+}
+
+func (p TeamPolicy) Validate() error {
+	var errs []error
+	if p.Mode != "" && !ValidSelectionMode(p.Mode) {
+		errs = append(errs, fmt.Errorf("mode must be one of random, fairness, got %q", p.Mode))
+	}
+	if p.MaxReviewers < 0 {
+		errs = append(errs, errors.New("max_reviewers must not be negative"))
+	}
+	if p.OverCapacity != "" && !ValidCapacityPolicy(p.OverCapacity) {
+		errs = append(errs, fmt.Errorf("capacity_policy must be one of allow, understaff, fail, got %q",
+			p.OverCapacity))
+	}
+	if p.FairnessWindow < 0 {
+		errs = append(errs, errors.New("fairness_window must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// TeamPolicies holds the team overrides. The whole set is replaced at once, so
+// a reload never mixes old and new policies of different teams.
+type TeamPolicies struct {
+	teams atomic.Pointer[map[string]TeamPolicy]
+}
+
+func NewTeamPolicies(teams map[string]TeamPolicy) (*TeamPolicies, error) {
+	p := &TeamPolicies{}
+	if err := p.Replace(teams); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Replace validates every policy and swaps in the set, on error the current
+// set is kept.
+func (p *TeamPolicies) Replace(teams map[string]TeamPolicy) error {
+	for name, tp := range teams {
+		if err := tp.Validate(); err != nil {
+			return fmt.Errorf("team %s: %w", name, err)
+		}
+	}
+	teams = maps.Clone(teams)
+	p.teams.Store(&teams)
+	return nil
+}
+
+func (p *TeamPolicies) Get(teamName string) (TeamPolicy, bool) {
+	if p == nil {
+		return TeamPolicy{}, false
+	}
+	teams := p.teams.Load()
+	if teams == nil {
+		return TeamPolicy{}, false
+	}
+	tp, ok := (*teams)[teamName]
+	return tp, ok
+}
+
+// forTeam returns the policy with the overrides of the team applied.
+func (p SelectionPolicy) forTeam(teamName string) SelectionPolicy {
+	tp, ok := p.Teams.Get(teamName)
+	if !ok {
+		return p
+	}
+	if tp.Mode != "" {
+		p.Mode = tp.Mode
+	}
+	if tp.MaxReviewers > 0 {
+		p.MaxReviewers = tp.MaxReviewers
+	}
+	if tp.OverCapacity != "" {
+		p.OverCapacity = tp.OverCapacity
+	}
+	if tp.FairnessWindow > 0 {
+		p.FairnessWindow = tp.FairnessWindow
+	}
+	return p
+}
@@ -3,31 +3,87 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
+// MaxReviewersCount is the default number of reviewers of a PR.
 const MaxReviewersCount = 2
 
+// pickAttempts bounds how many times a random pick is repeated when the
+// picked reviewer is assigned to the PR concurrently.
+const pickAttempts = 3
+
 type PRUseCase struct {
-	prRepo   PRRepository
-	userRepo UserRepository
-	log      *log.Logger
+	prRepo       PRRepository
+	userRepo     UserRepository
+	teamRepo     TeamRepository
+	scheduleRepo ScheduleRepository
+	auditRepo    ReviewerAuditRepository
+	declineRepo  DeclineRepository
+	policy       SelectionPolicy
+	metrics      PRMetrics
+	log          *log.Logger
 }
 
-func NewPRUseCase(pr PRRepository, user UserRepository, logger *log.Logger) *PRUseCase {
-	return &PRUseCase{prRepo: pr, userRepo: user, log: logger}
+func NewPRUseCase(
+	pr PRRepository,
+	user UserRepository,
+	team TeamRepository,
+	schedule ScheduleRepository,
+	audit ReviewerAuditRepository,
+	decline DeclineRepository,
+	policy SelectionPolicy,
+	metrics PRMetrics,
+	logger *log.Logger,
+) *PRUseCase {
+	if policy.Mode == "" {
+		policy.Mode = entity.SelectionModeRandom
+	}
+	if policy.FairnessWindow <= 0 {
+		policy.FairnessWindow = DefaultFairnessWindow
+	}
+	if policy.Seeder == nil {
+		policy.Seeder = RandomSeeder()
+	}
+	if policy.OverCapacity == "" {
+		policy.OverCapacity = entity.CapacityPolicyUnderstaff
+	}
+	if policy.MaxReviewers <= 0 {
+		policy.MaxReviewers = MaxReviewersCount
+	}
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+	return &PRUseCase{
+		prRepo:       pr,
+		userRepo:     user,
+		teamRepo:     team,
+		scheduleRepo: schedule,
+		auditRepo:    audit,
+		declineRepo:  decline,
+		policy:       policy,
+		metrics:      metrics,
+		log:          logger,
+	}
 }
 
-func (s *PRUseCase) CreatePullRequest(ctx context.Context, pr entity.PR) ([]string, error) {
-	s.log.WithFields(log.Fields{
+func (s *PRUseCase) CreatePullRequest(ctx context.Context, pr entity.PR) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.CreatePullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
 		"prID":     pr.ID,
 		"prName":   pr.Title,
 		"authorID": pr.AuthorID,
 	}).Info("PRUseCase - creating pull request")
-	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	sim, err := s.planCreate(ctx, pr)
 	if err != nil {
 		return nil, err
 	}
@@ -36,25 +92,73 @@ func (s *PRUseCase) CreatePullRequest(ctx context.Context, pr entity.PR) ([]stri
 		return nil, err
 	}
 
-	assigned := make([]string, 0, MaxReviewersCount)
-	for range MaxReviewersCount {
-		var reviewerID string
-		reviewerID, err = s.prRepo.AssignReviewer(ctx, pr.ID, author.TeamName)
-		if errors.Is(err, apperror.ErrNoCandidate) {
-			break
-		}
-		if err != nil {
+	for _, reviewerID := range sim.Reviewers {
+		// a reviewer added by hand right after the PR was created is already in place
+		err = s.prRepo.AddReviewer(ctx, pr.ID, reviewerID)
+		if err != nil && !errors.Is(err, apperror.ErrReviewerExists) {
 			_ = s.prRepo.DeleteByID(ctx, pr.ID)
 			return nil, err
 		}
-		assigned = append(assigned, reviewerID)
 	}
 
-	return assigned, nil
+	s.metrics.ReviewersAssigned(len(sim.Reviewers))
+	if len(sim.Reviewers) == 0 {
+		s.metrics.NoCandidate(OperationCreate)
+	}
+	return sim.Reviewers, nil
+}
+
+// SimulateCreatePullRequest runs the reviewer selection for a new PR without
+// creating it.
+func (s *PRUseCase) SimulateCreatePullRequest(
+	ctx context.Context,
+	pr entity.PR,
+) (_ *entity.AssignmentSimulation, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.SimulateCreatePullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":     pr.ID,
+		"authorID": pr.AuthorID,
+	}).Info("PRUseCase - simulating pull request creation")
+	if _, err := s.prRepo.GetByID(ctx, pr.ID); err == nil {
+		return nil, apperror.ErrPRExists
+	} else if !errors.Is(err, apperror.ErrNotFound) {
+		return nil, err
+	}
+
+	return s.planCreate(ctx, pr)
 }
 
-func (s *PRUseCase) MergePullRequest(ctx context.Context, prID string) (*entity.PR, error) {
-	s.log.WithField("prID", prID).Info("PRUseCase - merging pull request")
+func (s *PRUseCase) planCreate(ctx context.Context, pr entity.PR) (*entity.AssignmentSimulation, error) {
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidates(ctx, author.TeamName, &pr, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.pickReviewers(ctx, pr.ID, author.TeamName, candidates,
+		s.policy.forTeam(author.TeamName).MaxReviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AssignmentSimulation{
+		TeamName:   author.TeamName,
+		Reviewers:  reviewers,
+		Candidates: candidates,
+	}, nil
+}
+
+func (s *PRUseCase) MergePullRequest(ctx context.Context, prID string) (_ *entity.PR, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.MergePullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("prID", prID).Info("PRUseCase - merging pull request")
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
@@ -63,48 +167,295 @@ func (s *PRUseCase) MergePullRequest(ctx context.Context, prID string) (*entity.
 		return pr, nil
 	}
 
-	return s.prRepo.UpdateStatus(ctx, prID, entity.PRStatusMerged)
+	merged, err := s.prRepo.UpdateStatus(ctx, prID, entity.PRStatusMerged)
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.PRMerged()
+	return merged, nil
 }
 
-func (s *PRUseCase) ReassignReviewer(ctx context.Context, prID, oldUserID string) (string, *entity.PR, error) {
-	s.log.WithFields(log.Fields{
-		"prID":      prID,
-		"oldUserID": oldUserID,
+func (s *PRUseCase) ReassignReviewer(
+	ctx context.Context,
+	prID, oldUserID string,
+	opts entity.ReassignOptions,
+) (_ string, _ *entity.PR, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.ReassignReviewer")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":          prID,
+		"oldUserID":     oldUserID,
+		"newUserID":     opts.NewUserID,
+		"candidateTeam": opts.CandidateTeam,
+		"allowRemoved":  opts.AllowRemoved,
 	}).Info("PRUseCase - reassigning reviewer")
+	var (
+		pr        *entity.PR
+		newUserID string
+	)
+	for attempt := 1; ; attempt++ {
+		var (
+			sim *entity.AssignmentSimulation
+			err error
+		)
+		pr, sim, err = s.planReassign(ctx, prID, oldUserID, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(sim.Reviewers) == 0 {
+			s.metrics.NoCandidate(OperationReassign)
+			return "", nil, apperror.ErrNoCandidate
+		}
+
+		newUserID = sim.Reviewers[0]
+		err = s.prRepo.AddReviewer(ctx, prID, newUserID)
+		if err == nil {
+			break
+		}
+		if !s.retryPick(ctx, err, opts.NewUserID == "", attempt) {
+			return "", nil, err
+		}
+	}
+	if err := s.prRepo.RemoveReviewer(ctx, prID, oldUserID); err != nil {
+		_ = s.prRepo.RemoveReviewer(ctx, prID, newUserID)
+		return "", nil, err
+	}
+	s.rememberRemoved(ctx, prID, oldUserID)
+	s.metrics.ReviewerReassigned()
+
+	return newUserID, pr, nil
+}
+
+// SimulateReassignReviewer runs the replacement selection without changing
+// the reviewers. An empty Reviewers list means the real call fails with
+// ErrNoCandidate.
+func (s *PRUseCase) SimulateReassignReviewer(
+	ctx context.Context,
+	prID, oldUserID string,
+	opts entity.ReassignOptions,
+) (_ *entity.AssignmentSimulation, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.SimulateReassignReviewer")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":          prID,
+		"oldUserID":     oldUserID,
+		"newUserID":     opts.NewUserID,
+		"candidateTeam": opts.CandidateTeam,
+	}).Info("PRUseCase - simulating reviewer reassignment")
+	_, sim, err := s.planReassign(ctx, prID, oldUserID, opts)
+	return sim, err
+}
+
+// planReassign picks the replacement of oldUserID, skipping users listed in
+// opts.Exclude. A replacement chosen by hand must pass the same checks as
+// AddReviewer and belong to the candidate team.
+func (s *PRUseCase) planReassign(
+	ctx context.Context,
+	prID, oldUserID string,
+	opts entity.ReassignOptions,
+) (*entity.PR, *entity.AssignmentSimulation, error) {
 	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 	if pr.Status == entity.PRStatusMerged {
-		return "", nil, apperror.ErrPRMerged
+		return nil, nil, apperror.ErrPRMerged
 	}
 
 	oldUser, err := s.userRepo.GetByID(ctx, oldUserID)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
-	isAssigned, err := s.userRepo.IsAssignedToPR(ctx, oldUserID, prID)
+	assigned, err := s.prRepo.GetAssignedReviewers(ctx, prID)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
+	}
+	if !slices.Contains(assigned, oldUserID) {
+		return nil, nil, apperror.ErrNotAssigned
 	}
-	if !isAssigned {
-		return "", nil, apperror.ErrNotAssigned
+
+	teamName := oldUser.TeamName
+	if opts.CandidateTeam != "" {
+		teamName = opts.CandidateTeam
 	}
 
-	newUserID, err := s.prRepo.AssignReviewer(ctx, prID, oldUser.TeamName)
+	candidates, err := s.candidates(ctx, teamName, pr, assigned, opts.AllowRemoved)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
-	if err = s.prRepo.RemoveReviewer(ctx, prID, oldUserID); err != nil {
-		_ = s.prRepo.RemoveReviewer(ctx, prID, newUserID)
-		return "", nil, err
+	for i, c := range candidates {
+		if slices.Contains(opts.Exclude, c.ID) && (c.Eligible() || c.ExclusionReason == entity.ExclusionAtCapacity) {
+			candidates[i].ExclusionReason = entity.ExclusionExcluded
+		}
 	}
 
-	return newUserID, pr, nil
+	var reviewers []string
+	if opts.NewUserID != "" {
+		if err = s.checkEligible(ctx, pr, assigned, opts.NewUserID); err != nil {
+			return nil, nil, err
+		}
+		if !slices.ContainsFunc(candidates, func(c entity.Candidate) bool { return c.ID == opts.NewUserID }) {
+			return nil, nil, fmt.Errorf("%w: not a member of team %s", apperror.ErrNotEligible, teamName)
+		}
+		reviewers = []string{opts.NewUserID}
+	} else {
+		reviewers, err = s.pickReviewers(ctx, prID, teamName, candidates, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return pr, &entity.AssignmentSimulation{
+		TeamName:   teamName,
+		Reviewers:  reviewers,
+		Candidates: candidates,
+	}, nil
+}
+
+// AddReviewerFromTeam assigns one more reviewer from the given team on top of
+// the already assigned ones.
+func (s *PRUseCase) AddReviewerFromTeam(ctx context.Context, prID, teamName string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.AddReviewerFromTeam")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID": prID,
+		"team": teamName,
+	}).Info("PRUseCase - adding reviewer from team")
+	for attempt := 1; ; attempt++ {
+		picked, err := s.pickAdditionalReviewer(ctx, prID, teamName)
+		if err != nil {
+			return "", err
+		}
+		err = s.prRepo.AddReviewer(ctx, prID, picked)
+		if err == nil {
+			s.metrics.ReviewersAssigned(1)
+			return picked, nil
+		}
+		if !s.retryPick(ctx, err, true, attempt) {
+			return "", err
+		}
+	}
 }
 
-func (s *PRUseCase) GetAssignedReviewers(ctx context.Context, prID string) ([]string, error) {
-	s.log.WithField("prID", prID).Info("PRUseCase - getting assigned reviewers")
+func (s *PRUseCase) pickAdditionalReviewer(ctx context.Context, prID, teamName string) (string, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return "", err
+	}
+	if pr.Status == entity.PRStatusMerged {
+		return "", apperror.ErrPRMerged
+	}
+
+	assigned, err := s.prRepo.GetAssignedReviewers(ctx, prID)
+	if err != nil {
+		return "", err
+	}
+
+	candidates, err := s.candidates(ctx, teamName, pr, assigned, false)
+	if err != nil {
+		return "", err
+	}
+
+	picked, err := s.pickReviewers(ctx, prID, teamName, candidates, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(picked) == 0 {
+		s.metrics.NoCandidate(OperationAddReviewer)
+		return "", apperror.ErrNoCandidate
+	}
+	return picked[0], nil
+}
+
+// retryPick reports whether a failed AddReviewer of a randomly picked reviewer
+// should be retried: a concurrent call on the same PR assigned that user
+// between the pick and the insert, so the next pick excludes them.
+func (s *PRUseCase) retryPick(ctx context.Context, err error, random bool, attempt int) bool {
+	if !random || !errors.Is(err, apperror.ErrReviewerExists) || attempt >= pickAttempts {
+		return false
+	}
+	logging.FromContext(ctx, s.log).WithField("attempt", attempt).
+		Warn("PRUseCase - picked reviewer was assigned concurrently, picking again")
+	return true
+}
+
+func (s *PRUseCase) GetAssignedReviewers(ctx context.Context, prID string) (_ []string, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.GetAssignedReviewers")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("prID", prID).Info("PRUseCase - getting assigned reviewers")
 	return s.prRepo.GetAssignedReviewers(ctx, prID)
 }
+
+// GetPullRequest returns the PR with its reviewers' profiles and assignment times.
+func (s *PRUseCase) GetPullRequest(ctx context.Context, prID string) (_ *entity.PRDetails, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.GetPullRequest")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("prID", prID).Info("PRUseCase - getting pull request")
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.prRepo.GetReviewerDetails(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.PRDetails{PR: *pr, Reviewers: reviewers}, nil
+}
+
+// ListPullRequests returns a page of PRs, newest first by default, with their reviewers loaded in a single
+// batch query for the whole page.
+func (s *PRUseCase) ListPullRequests(
+	ctx context.Context,
+	opts entity.PRListOptions,
+) (_ entity.Page[entity.PRWithReviewers], err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.ListPullRequests")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"team":     opts.Filter.TeamName,
+		"authorID": opts.Filter.AuthorID,
+		"status":   opts.Filter.Status,
+		"sort":     opts.Sort.Field,
+		"limit":    opts.Page.Limit,
+	}).Info("PRUseCase - listing pull requests")
+	if opts.Sort.Field == "" {
+		opts.Sort.Field = entity.PRSortCreatedAt
+	}
+	if opts.Sort.Order == "" {
+		opts.Sort.Order = entity.SortDesc
+	}
+
+	page, err := s.prRepo.List(ctx, opts)
+	if err != nil {
+		return entity.Page[entity.PRWithReviewers]{}, err
+	}
+
+	ids := make([]string, 0, len(page.Items))
+	for _, pr := range page.Items {
+		ids = append(ids, pr.ID)
+	}
+	reviewers, err := s.prRepo.GetReviewersForPRs(ctx, ids)
+	if err != nil {
+		return entity.Page[entity.PRWithReviewers]{}, err
+	}
+
+	items := make([]entity.PRWithReviewers, 0, len(page.Items))
+	for _, pr := range page.Items {
+		assigned := reviewers[pr.ID]
+		if assigned == nil {
+			assigned = make([]string, 0)
+		}
+		items = append(items, entity.PRWithReviewers{PR: pr, Reviewers: assigned})
+	}
+
+	return entity.Page[entity.PRWithReviewers]{Items: items, NextCursor: page.NextCursor}, nil
+}
@@ -0,0 +1,568 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+func newPRUseCase(t *testing.T, seeder usecase.Seeder) *usecase.PRUseCase {
+	t.Helper()
+	uc, _ := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{Seeder: seeder})
+	return uc
+}
+
+func newPRUseCaseWithPolicy(t *testing.T, policy usecase.SelectionPolicy) (*usecase.PRUseCase, *memory.Store) {
+	t.Helper()
+	return newPRUseCaseWithMetrics(t, policy, nil)
+}
+
+func newPRUseCaseWithMetrics(
+	t *testing.T,
+	policy usecase.SelectionPolicy,
+	metrics usecase.PRMetrics,
+) (*usecase.PRUseCase, *memory.Store) {
+	t.Helper()
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	store := memory.NewStore()
+	team := memory.NewTeamRepository(store)
+	members := []entity.User{
+		*entity.NewUser("u1", "Alice", "backend", true),
+		*entity.NewUser("u2", "Bob", "backend", true),
+		*entity.NewUser("u3", "Carol", "backend", true),
+		*entity.NewUser("u4", "Dave", "backend", false),
+		*entity.NewUser("u5", "Eve", "backend", true),
+		*entity.NewUser("u6", "Frank", "backend", true),
+	}
+	if err := team.CreateTeam(t.Context(), *entity.NewTeam("backend"), members); err != nil {
+		t.Fatal(err)
+	}
+
+	uc := usecase.NewPRUseCase(memory.NewPRRepository(store), memory.NewUserRepository(store), team,
+		memory.NewScheduleRepository(store), memory.NewReviewerAuditRepository(store), memory.NewDeclineRepository(store),
+		policy, metrics, logger)
+	return uc, store
+}
+
+func TestCreatePullRequestIsReproducibleWithSeed(t *testing.T) {
+	pr := *entity.NewPR("pr-1", "Add search", "u1")
+
+	simulated, err := newPRUseCase(t, usecase.FixedSeeder(42)).SimulateCreatePullRequest(t.Context(), pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 5 {
+		assigned, err := newPRUseCase(t, usecase.FixedSeeder(42)).CreatePullRequest(t.Context(), pr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(assigned, simulated.Reviewers) {
+			t.Fatalf("assigned %v, dry run with the same seed picked %v", assigned, simulated.Reviewers)
+		}
+	}
+
+	if len(simulated.Reviewers) != usecase.MaxReviewersCount {
+		t.Fatalf("picked %v, want %d reviewers", simulated.Reviewers, usecase.MaxReviewersCount)
+	}
+	for _, id := range simulated.Reviewers {
+		if id == "u1" || id == "u4" {
+			t.Fatalf("picked %v, author u1 and inactive u4 must be excluded", simulated.Reviewers)
+		}
+	}
+}
+
+func TestSimulateCreatePullRequestExplainsExclusions(t *testing.T) {
+	uc, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(1)})
+	schedules := memory.NewScheduleRepository(store)
+	now := time.Now().UTC()
+	if _, err := schedules.AddOutOfOffice(t.Context(), entity.OutOfOffice{
+		UserID: "u2", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Reason: "vacation",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	offHours := entity.NewWorkSchedule("u3", "UTC", now.Add(2*time.Hour).Format("15:04"),
+		now.Add(3*time.Hour).Format("15:04"))
+	if _, err := schedules.UpsertSchedule(t.Context(), *offHours); err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := uc.SimulateCreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reasons := make(map[string]string, len(sim.Candidates))
+	for _, c := range sim.Candidates {
+		reasons[c.ID] = c.ExclusionReason
+	}
+	want := map[string]string{
+		"u1": entity.ExclusionAuthor,
+		"u2": entity.ExclusionOutOfOffice,
+		"u3": entity.ExclusionOffHours,
+		"u4": entity.ExclusionInactive,
+		"u5": "",
+		"u6": "",
+	}
+	if !maps.Equal(reasons, want) {
+		t.Fatalf("exclusion reasons = %v, want %v", reasons, want)
+	}
+	slices.Sort(sim.Reviewers)
+	if sim.TeamName != "backend" || !slices.Equal(sim.Reviewers, []string{"u5", "u6"}) {
+		t.Fatalf("simulation picked %v from %s, want u5 and u6", sim.Reviewers, sim.TeamName)
+	}
+
+	// the dry run stores nothing, the real call still succeeds
+	if _, err = uc.GetPullRequest(t.Context(), "pr-1"); !errors.Is(err, apperror.ErrNotFound) {
+		t.Fatalf("dry run created the PR: %v", err)
+	}
+	if _, err = uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = uc.SimulateCreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1")); !errors.Is(
+		err, apperror.ErrPRExists) {
+		t.Fatalf("dry run of an existing PR: %v, want %v", err, apperror.ErrPRExists)
+	}
+}
+
+func TestSimulateReassignReviewerExplainsExclusions(t *testing.T) {
+	uc := newPRUseCase(t, usecase.FixedSeeder(1))
+	if _, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1")); err != nil {
+		t.Fatal(err)
+	}
+
+	assigned, err := uc.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := uc.SimulateReassignReviewer(t.Context(), "pr-1", assigned[0], entity.ReassignOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reasons := make(map[string]string, len(sim.Candidates))
+	for _, c := range sim.Candidates {
+		reasons[c.ID] = c.ExclusionReason
+	}
+	if reasons["u1"] != entity.ExclusionAuthor || reasons["u4"] != entity.ExclusionInactive {
+		t.Fatalf("exclusion reasons = %v", reasons)
+	}
+	for _, id := range assigned {
+		if reasons[id] != entity.ExclusionAlreadyAssigned {
+			t.Fatalf("assigned reviewer %s has reason %q", id, reasons[id])
+		}
+	}
+
+	after, err := uc.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(after, assigned) {
+		t.Fatalf("dry run changed the reviewers from %v to %v", assigned, after)
+	}
+	if _, err = uc.SimulateReassignReviewer(t.Context(), "pr-1", "u1", entity.ReassignOptions{}); !errors.Is(
+		err, apperror.ErrNotAssigned) {
+		t.Fatalf("dry run for the author: %v, want %v", err, apperror.ErrNotAssigned)
+	}
+}
+
+func TestFairnessModePicksReviewersBelowFairShare(t *testing.T) {
+	uc, _ := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{
+		Mode:   entity.SelectionModeFairness,
+		Seeder: usecase.FixedSeeder(7),
+	})
+
+	// u2, u3, u5 and u6 are eligible for PRs by u1, two of them get each PR
+	counts := make(map[string]int)
+	for i := range 6 {
+		assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR(fmt.Sprintf("pr-%d", i), "Change", "u1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range assigned {
+			counts[id]++
+		}
+	}
+	for _, id := range []string{"u2", "u3", "u5", "u6"} {
+		if counts[id] != 3 {
+			t.Fatalf("assignments = %v, want 3 for each eligible reviewer", counts)
+		}
+	}
+
+	ledger, err := uc.GetFairnessLedger(t.Context(), "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ledger.Total != 12 {
+		t.Fatalf("ledger total = %d, want 12", ledger.Total)
+	}
+	if e, ok := ledger.Entry("u1"); !ok || e.Balance() >= 0 {
+		t.Fatalf("author u1 entry = %+v, want a deficit", e)
+	}
+
+	// inactive u4 takes no share, the total is split between the active members
+	if e, ok := ledger.Entry("u4"); !ok || e.IsActive || e.Available != 0 || e.FairShare != 0 {
+		t.Fatalf("inactive u4 entry = %+v, want no availability and no share", e)
+	}
+	var shares float64
+	for _, e := range ledger.Entries {
+		shares += e.FairShare
+	}
+	if math.Abs(shares-12) > 1e-9 {
+		t.Fatalf("fair shares sum to %v, want 12", shares)
+	}
+}
+
+func TestFairnessLedgerCountsOnlyActiveTime(t *testing.T) {
+	uc, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{FairnessWindow: time.Hour})
+	users := memory.NewUserRepository(store)
+
+	const pause = 20 * time.Millisecond
+	time.Sleep(pause)
+	// u4 is reactivated and u3 deactivated in the middle of the window
+	if _, err := users.SetIsActive(t.Context(), "u4", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.SetIsActive(t.Context(), "u3", false); err != nil {
+		t.Fatal(err)
+	}
+
+	ledger, err := uc.GetFairnessLedger(t.Context(), "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	always, _ := ledger.Entry("u2")
+	reactivated, _ := ledger.Entry("u4")
+	deactivated, _ := ledger.Entry("u3")
+	if reactivated.Available <= 0 || reactivated.Available > always.Available-pause {
+		t.Fatalf("reactivated u4 is available for %v, want the time since reactivation, u2 has %v",
+			reactivated.Available, always.Available)
+	}
+	if deactivated.Available < pause || deactivated.Available >= always.Available {
+		t.Fatalf("deactivated u3 is available for %v, want the time before deactivation, u2 has %v",
+			deactivated.Available, always.Available)
+	}
+}
+
+func TestCapacityPolicyWhenEveryoneIsAtCapacity(t *testing.T) {
+	tests := []struct {
+		policy    string
+		reviewers int
+		err       error
+	}{
+		{entity.CapacityPolicyUnderstaff, 0, nil},
+		{entity.CapacityPolicyAllow, usecase.MaxReviewersCount, nil},
+		{entity.CapacityPolicyFail, 0, apperror.ErrAllAtCapacity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			uc, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{
+				Seeder:       usecase.FixedSeeder(3),
+				OverCapacity: tt.policy,
+			})
+			zero := 0
+			if err := memory.NewTeamRepository(store).SetMaxOpenReviews(t.Context(), "backend", &zero); err != nil {
+				t.Fatal(err)
+			}
+
+			sim, err := uc.SimulateCreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if len(sim.Reviewers) != tt.reviewers {
+				t.Fatalf("picked %v, want %d reviewers", sim.Reviewers, tt.reviewers)
+			}
+			for _, c := range sim.Candidates {
+				if c.IsActive && c.ID != "u1" && c.ExclusionReason != entity.ExclusionAtCapacity {
+					t.Fatalf("candidate %s has reason %q, want AT_CAPACITY", c.ID, c.ExclusionReason)
+				}
+			}
+		})
+	}
+}
+
+func TestReassignReviewerToChosenColleague(t *testing.T) {
+	uc, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(9)})
+	frontend := []entity.User{*entity.NewUser("f1", "Grace", "frontend", true)}
+	if err := memory.NewTeamRepository(store).CreateTeam(t.Context(), *entity.NewTeam("frontend"), frontend); err != nil {
+		t.Fatal(err)
+	}
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var free string
+	for _, id := range []string{"u2", "u3", "u5", "u6"} {
+		if !slices.Contains(assigned, id) {
+			free = id
+			break
+		}
+	}
+
+	for _, userID := range []string{"u1", "u4", "f1"} {
+		opts := entity.ReassignOptions{NewUserID: userID}
+		if _, _, err = uc.ReassignReviewer(t.Context(), "pr-1", assigned[0], opts); !errors.Is(err, apperror.ErrNotEligible) {
+			t.Fatalf("reassigning to %s: error = %v, want %v", userID, err, apperror.ErrNotEligible)
+		}
+	}
+
+	newUserID, _, err := uc.ReassignReviewer(t.Context(), "pr-1", assigned[0], entity.ReassignOptions{NewUserID: free})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newUserID != free {
+		t.Fatalf("replaced by %s, want %s", newUserID, free)
+	}
+
+	newUserID, _, err = uc.ReassignReviewer(t.Context(), "pr-1", free, entity.ReassignOptions{CandidateTeam: "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newUserID != "f1" {
+		t.Fatalf("replaced by %s, want f1 from the candidate team", newUserID)
+	}
+}
+
+func TestReassignDoesNotBounceBetweenReviewers(t *testing.T) {
+	uc, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(11)})
+	// leave u1 as the author, u2 and u3 as the only candidates
+	for _, id := range []string{"u5", "u6"} {
+		if _, err := memory.NewUserRepository(store).SetIsActive(t.Context(), id, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 2 {
+		t.Fatalf("assigned = %v, want u2 and u3", assigned)
+	}
+	if _, _, err = uc.RemoveReviewer(t.Context(), "pr-1", assigned[1], ""); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = uc.ReassignReviewer(t.Context(), "pr-1", assigned[0], entity.ReassignOptions{})
+	if !errors.Is(err, apperror.ErrNoCandidate) {
+		t.Fatalf("reassigning to the removed reviewer: error = %v, want %v", err, apperror.ErrNoCandidate)
+	}
+
+	sim, err := uc.SimulateReassignReviewer(t.Context(), "pr-1", assigned[0], entity.ReassignOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range sim.Candidates {
+		if c.ID == assigned[1] && c.ExclusionReason != entity.ExclusionRemoved {
+			t.Fatalf("removed reviewer exclusion = %q, want %q", c.ExclusionReason, entity.ExclusionRemoved)
+		}
+	}
+
+	newUserID, _, err := uc.ReassignReviewer(t.Context(), "pr-1", assigned[0],
+		entity.ReassignOptions{AllowRemoved: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newUserID != assigned[1] {
+		t.Fatalf("replaced by %s, want %s", newUserID, assigned[1])
+	}
+
+	_, _, err = uc.ReassignReviewer(t.Context(), "pr-1", newUserID, entity.ReassignOptions{})
+	if !errors.Is(err, apperror.ErrNoCandidate) {
+		t.Fatalf("bouncing back: error = %v, want %v", err, apperror.ErrNoCandidate)
+	}
+}
+
+func TestMaxReviewersPolicy(t *testing.T) {
+	uc, _ := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(5), MaxReviewers: 3})
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 3 {
+		t.Fatalf("assigned %v, want 3 reviewers", assigned)
+	}
+
+	_, _, err = uc.AddReviewer(t.Context(), "pr-1", "", "")
+	if !errors.Is(err, apperror.ErrReviewersFull) {
+		t.Fatalf("adding a fourth reviewer: error = %v, want %v", err, apperror.ErrReviewersFull)
+	}
+}
+
+func TestTeamPolicyOverridesMaxReviewers(t *testing.T) {
+	teams, err := usecase.NewTeamPolicies(map[string]usecase.TeamPolicy{"backend": {MaxReviewers: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc, _ := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{
+		Seeder:       usecase.FixedSeeder(5),
+		MaxReviewers: usecase.MaxReviewersCount,
+		Teams:        teams,
+	})
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 3 {
+		t.Fatalf("assigned %v, want 3 reviewers from the team policy", assigned)
+	}
+
+	// an invalid set is rejected and the current one stays in place
+	if err = teams.Replace(map[string]usecase.TeamPolicy{"backend": {Mode: "roundrobin"}}); err == nil {
+		t.Fatal("invalid team policy was accepted")
+	}
+	if err = teams.Replace(map[string]usecase.TeamPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	assigned, err = uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-2", "Fix search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != usecase.MaxReviewersCount {
+		t.Fatalf("assigned %v after the override was dropped, want %d", assigned, usecase.MaxReviewersCount)
+	}
+}
+
+// racingPRRepository assigns the first reviewer it is asked to add after raced
+// is reset to "" as if a concurrent call on the same PR got there first.
+type racingPRRepository struct {
+	usecase.PRRepository
+
+	raced string
+}
+
+func (r *racingPRRepository) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	if err := r.race(ctx, prID, reviewerID); err != nil {
+		return err
+	}
+	return r.PRRepository.AddReviewer(ctx, prID, reviewerID)
+}
+
+func (r *racingPRRepository) AddReviewerWithLimit(
+	ctx context.Context,
+	prID, reviewerID string,
+	maxReviewers int,
+) error {
+	if err := r.race(ctx, prID, reviewerID); err != nil {
+		return err
+	}
+	return r.PRRepository.AddReviewerWithLimit(ctx, prID, reviewerID, maxReviewers)
+}
+
+func (r *racingPRRepository) race(ctx context.Context, prID, reviewerID string) error {
+	if r.raced != "" {
+		return nil
+	}
+	r.raced = reviewerID
+	return r.PRRepository.AddReviewer(ctx, prID, reviewerID)
+}
+
+func TestReassignPicksAgainWhenReviewerIsTakenConcurrently(t *testing.T) {
+	_, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{})
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	prRepo := &racingPRRepository{PRRepository: memory.NewPRRepository(store), raced: "-"}
+	uc := usecase.NewPRUseCase(prRepo, memory.NewUserRepository(store), memory.NewTeamRepository(store),
+		memory.NewScheduleRepository(store), memory.NewReviewerAuditRepository(store), memory.NewDeclineRepository(store),
+		usecase.SelectionPolicy{Seeder: usecase.FixedSeeder(7)}, nil, logger)
+
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prRepo.raced = ""
+	newUserID, _, err := uc.ReassignReviewer(t.Context(), "pr-1", assigned[0], entity.ReassignOptions{})
+	if err != nil {
+		t.Fatalf("reassign after a concurrent assignment: %v", err)
+	}
+	if newUserID == prRepo.raced {
+		t.Fatalf("reassigned to %s, which was taken concurrently", newUserID)
+	}
+
+	reviewers, err := uc.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{assigned[1], prRepo.raced, newUserID}
+	slices.Sort(want)
+	slices.Sort(reviewers)
+	if !slices.Equal(reviewers, want) {
+		t.Fatalf("reviewers = %v, want %v", reviewers, want)
+	}
+
+	prRepo.raced = "-"
+	if _, err = uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-2", "Fix login", "u2")); err != nil {
+		t.Fatal(err)
+	}
+	prRepo.raced = ""
+	added, err := uc.AddReviewerFromTeam(t.Context(), "pr-2", "backend")
+	if err != nil {
+		t.Fatalf("add reviewer after a concurrent assignment: %v", err)
+	}
+	if added == prRepo.raced {
+		t.Fatalf("added %s, which was taken concurrently", added)
+	}
+
+	if err = prRepo.Create(t.Context(), *entity.NewPR("pr-3", "Fix search", "u1")); err != nil {
+		t.Fatal(err)
+	}
+	prRepo.raced = ""
+	_, entry, err := uc.AddReviewer(t.Context(), "pr-3", "", "lead")
+	if err != nil {
+		t.Fatalf("add random reviewer after a concurrent assignment: %v", err)
+	}
+	if entry.ReviewerID == prRepo.raced {
+		t.Fatalf("added %s, which was taken concurrently", entry.ReviewerID)
+	}
+	if _, _, err = uc.AddReviewer(t.Context(), "pr-3", "", "lead"); !errors.Is(err, apperror.ErrReviewersFull) {
+		t.Fatalf("adding a third reviewer: error = %v, want %v", err, apperror.ErrReviewersFull)
+	}
+}
+
+func TestSpansRecordUseCaseErrors(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	uc := newPRUseCase(t, usecase.FixedSeeder(1))
+	if _, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.MergePullRequest(t.Context(), "missing"); !errors.Is(err, apperror.ErrNotFound) {
+		t.Fatalf("error = %v, want %v", err, apperror.ErrNotFound)
+	}
+
+	status := make(map[string]codes.Code)
+	for _, s := range exporter.GetSpans() {
+		status[s.Name] = s.Status.Code
+	}
+	if status["PRUseCase.CreatePullRequest"] != codes.Unset || status["PRUseCase.MergePullRequest"] != codes.Error {
+		t.Fatalf("span statuses = %v, want only the failed merge marked as an error", status)
+	}
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+// AddReviewer assigns one more reviewer to the PR on behalf of actor. An empty
+// userID picks one from the author's team like PR creation does. An explicit
+// user must not be the author, must be active and, unless CapacityPolicyAllow
+// is set, below the review capacity. The PR can have at most MaxReviewers of
+// the policy of the author's team; the limit is checked again by the insert,
+// so concurrent calls can not exceed it.
+func (s *PRUseCase) AddReviewer(
+	ctx context.Context,
+	prID, userID, actor string,
+) (_ *entity.PR, _ *entity.ReviewerAuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.AddReviewer")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":   prID,
+		"userID": userID,
+		"actor":  actor,
+	}).Info("PRUseCase - adding reviewer")
+	pr, assigned, err := s.openPRReviewers(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxReviewers := s.policy.forTeam(author.TeamName).MaxReviewers
+
+	for attempt := 1; ; attempt++ {
+		if len(assigned) >= maxReviewers {
+			return nil, nil, apperror.ErrReviewersFull
+		}
+
+		reviewerID := userID
+		if reviewerID == "" {
+			reviewerID, err = s.pickForPR(ctx, pr, assigned)
+		} else {
+			err = s.checkEligible(ctx, pr, assigned, reviewerID)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = s.prRepo.AddReviewerWithLimit(ctx, prID, reviewerID, maxReviewers)
+		if err == nil {
+			userID = reviewerID
+			break
+		}
+		if !s.retryPick(ctx, err, userID == "", attempt) {
+			return nil, nil, err
+		}
+		if assigned, err = s.prRepo.GetAssignedReviewers(ctx, prID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	entry, err := s.auditRepo.Record(ctx, *entity.NewReviewerAuditEntry(prID, userID, entity.ReviewerAuditAdded, actor))
+	if err != nil {
+		_ = s.prRepo.RemoveReviewer(ctx, prID, userID)
+		return nil, nil, err
+	}
+	s.metrics.ReviewersAssigned(1)
+
+	return pr, entry, nil
+}
+
+// RemoveReviewer unassigns the reviewer from the PR on behalf of actor without
+// a replacement.
+func (s *PRUseCase) RemoveReviewer(
+	ctx context.Context,
+	prID, userID, actor string,
+) (_ *entity.PR, _ *entity.ReviewerAuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.RemoveReviewer")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":   prID,
+		"userID": userID,
+		"actor":  actor,
+	}).Info("PRUseCase - removing reviewer")
+	pr, assigned, err := s.openPRReviewers(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(assigned, userID) {
+		return nil, nil, apperror.ErrNotAssigned
+	}
+
+	if err = s.prRepo.RemoveReviewer(ctx, prID, userID); err != nil {
+		return nil, nil, err
+	}
+	entry, err := s.auditRepo.Record(ctx, *entity.NewReviewerAuditEntry(prID, userID, entity.ReviewerAuditRemoved, actor))
+	if err != nil {
+		_ = s.prRepo.AddReviewer(ctx, prID, userID)
+		return nil, nil, err
+	}
+	s.rememberRemoved(ctx, prID, userID)
+
+	return pr, entry, nil
+}
+
+// ListReviewerAudit returns manual reviewer changes of the PR, oldest first.
+func (s *PRUseCase) ListReviewerAudit(ctx context.Context, prID string) (_ []entity.ReviewerAuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "PRUseCase.ListReviewerAudit")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("prID", prID).Info("PRUseCase - listing reviewer audit")
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.auditRepo.ListForPR(ctx, prID)
+}
+
+// rememberRemoved records that the reviewer was taken off the PR. The record
+// only steers later picks, so a failure is logged instead of undoing the change.
+func (s *PRUseCase) rememberRemoved(ctx context.Context, prID, reviewerID string) {
+	if err := s.prRepo.MarkReviewerRemoved(ctx, prID, reviewerID); err != nil {
+		logging.FromContext(ctx, s.log).WithError(err).WithFields(log.Fields{
+			"prID":       prID,
+			"reviewerID": reviewerID,
+		}).Warn("PRUseCase - failed to remember removed reviewer")
+	}
+}
+
+// openPRReviewers returns the PR with its reviewers, failing for merged PRs.
+func (s *PRUseCase) openPRReviewers(ctx context.Context, prID string) (*entity.PR, []string, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr.Status == entity.PRStatusMerged {
+		return nil, nil, apperror.ErrPRMerged
+	}
+
+	assigned, err := s.prRepo.GetAssignedReviewers(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pr, assigned, nil
+}
+
+func (s *PRUseCase) pickForPR(ctx context.Context, pr *entity.PR, assigned []string) (string, error) {
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return "", err
+	}
+
+	candidates, err := s.candidates(ctx, author.TeamName, pr, assigned, false)
+	if err != nil {
+		return "", err
+	}
+
+	picked, err := s.pickReviewers(ctx, pr.ID, author.TeamName, candidates, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(picked) == 0 {
+		s.metrics.NoCandidate(OperationAddReviewer)
+		return "", apperror.ErrNoCandidate
+	}
+
+	return picked[0], nil
+}
+
+// checkEligible applies the assignment rules to a reviewer chosen by hand.
+// Working hours and out-of-office periods are not checked: the choice is explicit.
+func (s *PRUseCase) checkEligible(ctx context.Context, pr *entity.PR, assigned []string, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case userID == pr.AuthorID:
+		return fmt.Errorf("%w: %s", apperror.ErrNotEligible, entity.ExclusionAuthor)
+	case slices.Contains(assigned, userID):
+		return apperror.ErrReviewerExists
+	case !user.IsActive:
+		return fmt.Errorf("%w: %s", apperror.ErrNotEligible, entity.ExclusionInactive)
+	case s.policy.forTeam(user.TeamName).OverCapacity == entity.CapacityPolicyAllow:
+		return nil
+	}
+
+	team, _, err := s.teamRepo.GetTeam(ctx, user.TeamName)
+	if err != nil {
+		return err
+	}
+	openReviews, err := s.prRepo.CountOpenReviews(ctx, user.TeamName)
+	if err != nil {
+		return err
+	}
+	if entity.AtCapacity(*team, *user, openReviews[userID]) {
+		return fmt.Errorf("%w: %s", apperror.ErrNotEligible, entity.ExclusionAtCapacity)
+	}
+
+	return nil
+}
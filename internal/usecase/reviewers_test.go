@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+func TestManualReviewerChangesAreCheckedAndAudited(t *testing.T) {
+	uc := newPRUseCase(t, usecase.FixedSeeder(5))
+	assigned, err := uc.CreatePullRequest(t.Context(), *entity.NewPR("pr-1", "Add search", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = uc.AddReviewer(t.Context(), "pr-1", "", "lead"); !errors.Is(err, apperror.ErrReviewersFull) {
+		t.Fatalf("adding a third reviewer: error = %v, want %v", err, apperror.ErrReviewersFull)
+	}
+
+	_, removed, err := uc.RemoveReviewer(t.Context(), "pr-1", assigned[0], "lead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed.Action != entity.ReviewerAuditRemoved || removed.ReviewerID != assigned[0] {
+		t.Fatalf("audit entry = %+v", removed)
+	}
+	if _, _, err = uc.RemoveReviewer(t.Context(), "pr-1", assigned[0], "lead"); !errors.Is(err, apperror.ErrNotAssigned) {
+		t.Fatalf("removing twice: error = %v, want %v", err, apperror.ErrNotAssigned)
+	}
+
+	for _, userID := range []string{"u1", "u4"} {
+		if _, _, err = uc.AddReviewer(t.Context(), "pr-1", userID, "lead"); !errors.Is(err, apperror.ErrNotEligible) {
+			t.Fatalf("adding %s: error = %v, want %v", userID, err, apperror.ErrNotEligible)
+		}
+	}
+	if _, _, err = uc.AddReviewer(t.Context(), "pr-1", assigned[1], "lead"); !errors.Is(err, apperror.ErrReviewerExists) {
+		t.Fatalf("adding an assigned reviewer: error = %v, want %v", err, apperror.ErrReviewerExists)
+	}
+
+	_, added, err := uc.AddReviewer(t.Context(), "pr-1", assigned[0], "lead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Action != entity.ReviewerAuditAdded || added.Actor != "lead" {
+		t.Fatalf("audit entry = %+v", added)
+	}
+
+	reviewers, err := uc.GetAssignedReviewers(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviewers) != 2 || !slices.Contains(reviewers, assigned[0]) {
+		t.Fatalf("reviewers = %v, want %v back", reviewers, assigned)
+	}
+
+	entries, err := uc.ListReviewerAudit(t.Context(), "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != removed.ID || entries[1].ID != added.ID {
+		t.Fatalf("audit = %+v, want the removal and the addition", entries)
+	}
+
+	if _, err = uc.MergePullRequest(t.Context(), "pr-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = uc.RemoveReviewer(t.Context(), "pr-1", assigned[0], "lead"); !errors.Is(err, apperror.ErrPRMerged) {
+		t.Fatalf("removing from a merged PR: error = %v, want %v", err, apperror.ErrPRMerged)
+	}
+}
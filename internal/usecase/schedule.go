@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+type ScheduleUseCase struct {
+	scheduleRepo ScheduleRepository
+	userRepo     UserRepository
+	log          *log.Logger
+}
+
+func NewScheduleUseCase(schedule ScheduleRepository, user UserRepository, logger *log.Logger) *ScheduleUseCase {
+	return &ScheduleUseCase{scheduleRepo: schedule, userRepo: user, log: logger}
+}
+
+func (s *ScheduleUseCase) SetSchedule(
+	ctx context.Context,
+	schedule entity.WorkSchedule,
+) (_ *entity.WorkSchedule, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleUseCase.SetSchedule")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"userID":   schedule.UserID,
+		"timezone": schedule.Timezone,
+		"start":    schedule.WorkStart,
+		"end":      schedule.WorkEnd,
+	}).Info("ScheduleUseCase - setting work schedule")
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperror.ErrInvalidSchedule, err)
+	}
+	return s.scheduleRepo.UpsertSchedule(ctx, schedule)
+}
+
+// GetSchedule returns the user's schedule, nil if none is set, and out-of-office
+// periods that have not ended yet.
+func (s *ScheduleUseCase) GetSchedule(
+	ctx context.Context,
+	userID string,
+) (_ *entity.WorkSchedule, _ []entity.OutOfOffice, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleUseCase.GetSchedule")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("userID", userID).Info("ScheduleUseCase - getting work schedule")
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, nil, err
+	}
+
+	schedule, err := s.scheduleRepo.GetSchedule(ctx, userID)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		return nil, nil, err
+	}
+
+	periods, err := s.scheduleRepo.ListOutOfOffice(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schedule, periods, nil
+}
+
+func (s *ScheduleUseCase) DeleteSchedule(ctx context.Context, userID string) (err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleUseCase.DeleteSchedule")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("userID", userID).Info("ScheduleUseCase - deleting work schedule")
+	return s.scheduleRepo.DeleteSchedule(ctx, userID)
+}
+
+func (s *ScheduleUseCase) AddOutOfOffice(ctx context.Context, o entity.OutOfOffice) (_ *entity.OutOfOffice, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleUseCase.AddOutOfOffice")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"userID":   o.UserID,
+		"startsAt": o.StartsAt,
+		"endsAt":   o.EndsAt,
+	}).Info("ScheduleUseCase - adding out of office")
+	if !o.EndsAt.After(o.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", apperror.ErrInvalidSchedule)
+	}
+	if !o.EndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: period has already ended", apperror.ErrInvalidSchedule)
+	}
+	return s.scheduleRepo.AddOutOfOffice(ctx, o)
+}
+
+func (s *ScheduleUseCase) DeleteOutOfOffice(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "ScheduleUseCase.DeleteOutOfOffice")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("oooID", id).Info("ScheduleUseCase - deleting out of office")
+	return s.scheduleRepo.DeleteOutOfOffice(ctx, id)
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+// SelectionPolicy configures how reviewers are picked among eligible candidates.
+type SelectionPolicy struct {
+	// Mode is one of entity.SelectionMode*, random by default.
+	Mode string
+	// FairnessWindow is the sliding window of the fairness ledger, 30 days by default.
+	FairnessWindow time.Duration
+	// Seeder seeds the random choice, RandomSeeder by default.
+	Seeder Seeder
+	// OverCapacity is one of entity.CapacityPolicy*, understaff by default.
+	OverCapacity string
+	// MaxReviewers is the number of reviewers of a PR, MaxReviewersCount by default.
+	MaxReviewers int
+	// Teams overrides the policy for reviewers picked from a team, nil means
+	// no overrides. The overrides can be replaced while the service runs.
+	Teams *TeamPolicies
+}
+
+// Seeder returns the seed of the random source used to pick reviewers for a PR.
+type Seeder func(prID string) uint64
+
+// RandomSeeder draws a new seed for every selection.
+func RandomSeeder() Seeder {
+	return func(string) uint64 {
+		return rand.Uint64() //nolint:gosec // reviewer choice is not security sensitive
+	}
+}
+
+// FixedSeeder uses the same seed for every selection, so the same data always
+// yields the same reviewers. It is meant for tests and for replaying a logged
+// selection: every PR with the same candidates gets the same reviewers.
+func FixedSeeder(seed uint64) Seeder {
+	return func(string) uint64 {
+		return seed
+	}
+}
+
+// candidates evaluates every member of the team as a reviewer of the PR that
+// already has the assigned reviewers. Reviewers taken off the PR earlier are
+// excluded unless allowRemoved is set. Members are ordered by id.
+func (s *PRUseCase) candidates(
+	ctx context.Context,
+	teamName string,
+	pr *entity.PR,
+	assigned []string,
+	allowRemoved bool,
+) ([]entity.Candidate, error) {
+	team, members, err := s.teamRepo.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	declines, err := s.declineRepo.ListForPR(ctx, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+	declined := make(map[string]bool, len(declines))
+	for _, d := range declines {
+		declined[d.ReviewerID] = true
+	}
+
+	var removed []string
+	if !allowRemoved {
+		if removed, err = s.prRepo.ListRemovedReviewers(ctx, pr.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	unavailable, err := s.unavailableReviewers(ctx, teamName, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	openReviews, err := s.prRepo.CountOpenReviews(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(members, func(a, b entity.User) int { return strings.Compare(a.ID, b.ID) })
+	candidates := make([]entity.Candidate, 0, len(members))
+	for _, m := range members {
+		c := entity.Candidate{User: m}
+		switch {
+		case m.ID == pr.AuthorID:
+			c.ExclusionReason = entity.ExclusionAuthor
+		case slices.Contains(assigned, m.ID):
+			c.ExclusionReason = entity.ExclusionAlreadyAssigned
+		case declined[m.ID]:
+			c.ExclusionReason = entity.ExclusionDeclined
+		case slices.Contains(removed, m.ID):
+			c.ExclusionReason = entity.ExclusionRemoved
+		case !m.IsActive:
+			c.ExclusionReason = entity.ExclusionInactive
+		case unavailable[m.ID] != "":
+			c.ExclusionReason = unavailable[m.ID]
+		case entity.AtCapacity(*team, m, openReviews[m.ID]):
+			c.ExclusionReason = entity.ExclusionAtCapacity
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// unavailableReviewers returns members of the team who are out of office or
+// outside of their working hours at now, with the reason.
+func (s *PRUseCase) unavailableReviewers(
+	ctx context.Context,
+	teamName string,
+	now time.Time,
+) (map[string]string, error) {
+	periods, err := s.scheduleRepo.ListTeamOutOfOffice(ctx, teamName, now)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.scheduleRepo.ListTeamSchedules(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	unavailable := make(map[string]string, len(periods))
+	for _, sch := range schedules {
+		if !sch.IsWorkingAt(now) {
+			unavailable[sch.UserID] = entity.ExclusionOffHours
+		}
+	}
+	for _, p := range periods {
+		unavailable[p.UserID] = entity.ExclusionOutOfOffice
+	}
+
+	return unavailable, nil
+}
+
+// pickReviewers picks up to n eligible candidates from the team for the PR. In
+// the fairness mode the candidates furthest below their fair share go first,
+// ties are broken at random. Candidates at capacity fill the missing slots only
+// under CapacityPolicyAllow, CapacityPolicyFail returns ErrAllAtCapacity when
+// nobody else is left. The seed is logged, running with FixedSeeder(seed) on
+// the same data replays the choice.
+func (s *PRUseCase) pickReviewers(
+	ctx context.Context,
+	prID, teamName string,
+	candidates []entity.Candidate,
+	n int,
+) ([]string, error) {
+	policy := s.policy.forTeam(teamName)
+	seed := policy.Seeder(prID)
+	rng := rand.New(rand.NewPCG(seed, 0)) //nolint:gosec // reviewer choice is not security sensitive
+
+	picked, err := s.rank(ctx, policy, teamName, shuffleCandidates(candidates, "", rng), n)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(picked) < n {
+		overloaded, err := s.rank(ctx, policy, teamName,
+			shuffleCandidates(candidates, entity.ExclusionAtCapacity, rng), n-len(picked))
+		if err != nil {
+			return nil, err
+		}
+		switch policy.OverCapacity {
+		case entity.CapacityPolicyAllow:
+			picked = append(picked, overloaded...)
+		case entity.CapacityPolicyFail:
+			if len(picked) == 0 && len(overloaded) > 0 {
+				return nil, apperror.ErrAllAtCapacity
+			}
+		}
+	}
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"prID":   prID,
+		"mode":   policy.Mode,
+		"seed":   seed,
+		"picked": picked,
+	}).Info("PRUseCase - picked reviewers")
+	return picked, nil
+}
+
+// rank returns the first n of the shuffled ids, in the fairness mode ordered
+// by the balance in the team ledger.
+func (s *PRUseCase) rank(
+	ctx context.Context,
+	policy SelectionPolicy,
+	teamName string,
+	ids []string,
+	n int,
+) ([]string, error) {
+	if policy.Mode == entity.SelectionModeFairness && len(ids) > n {
+		ledger, err := s.fairnessLedger(ctx, teamName, policy.FairnessWindow, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(ids, func(a, b string) int {
+			ea, _ := ledger.Entry(a)
+			eb, _ := ledger.Entry(b)
+			return cmp.Compare(ea.Balance(), eb.Balance())
+		})
+	}
+	return ids[:min(n, len(ids))], nil
+}
+
+// shuffleCandidates returns ids of candidates with the exclusion reason in
+// random order, an empty reason selects eligible candidates.
+func shuffleCandidates(candidates []entity.Candidate, reason string, rng *rand.Rand) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if c.ExclusionReason == reason {
+			ids = append(ids, c.ID)
+		}
+	}
+
+	rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	return ids
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+// ReviewEscalator performs the automatic actions on overdue reviews. It is
+// satisfied by PRUseCase.
+type ReviewEscalator interface {
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, opts entity.ReassignOptions) (string, *entity.PR, error)
+	AddReviewerFromTeam(ctx context.Context, prID, teamName string) (string, error)
+}
+
+type SLAUseCase struct {
+	slaRepo   SLARepository
+	escalator ReviewEscalator
+	mode      string
+	log       *log.Logger
+}
+
+// NewSLAUseCase creates the use case. mode is one of entity.EscalationMode* and
+// selects what happens to overdue reviews besides being flagged.
+func NewSLAUseCase(sla SLARepository, escalator ReviewEscalator, mode string, logger *log.Logger) *SLAUseCase {
+	return &SLAUseCase{slaRepo: sla, escalator: escalator, mode: mode, log: logger}
+}
+
+func ValidEscalationMode(mode string) bool {
+	switch mode {
+	case entity.EscalationModeNone, entity.EscalationModeReassign, entity.EscalationModeAddReviewer:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *SLAUseCase) SetTeamSLA(ctx context.Context, teamName string, sla *time.Duration) (err error) {
+	ctx, span := tracing.Start(ctx, "SLAUseCase.SetTeamSLA")
+	defer func() { tracing.End(span, err) }()
+
+	entry := logging.FromContext(ctx, s.log).WithField("team", teamName)
+	if sla != nil {
+		entry = entry.WithField("sla", sla.String())
+	}
+	entry.Info("SLAUseCase - setting team review SLA")
+	if sla != nil && *sla < time.Second {
+		return fmt.Errorf("%w: sla must be at least one second", apperror.ErrInvalidSLA)
+	}
+	return s.slaRepo.SetTeamSLA(ctx, teamName, sla)
+}
+
+func (s *SLAUseCase) GetReviewStats(ctx context.Context, teamName string) (_ *entity.ReviewStats, err error) {
+	ctx, span := tracing.Start(ctx, "SLAUseCase.GetReviewStats")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("team", teamName).Info("SLAUseCase - getting review stats")
+	return s.slaRepo.GetReviewStats(ctx, teamName, time.Now().UTC())
+}
+
+func (s *SLAUseCase) ListOverdue(ctx context.Context) (_ []entity.Escalation, err error) {
+	ctx, span := tracing.Start(ctx, "SLAUseCase.ListOverdue")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).Info("SLAUseCase - listing overdue reviews")
+	return s.slaRepo.ListOpenEscalations(ctx)
+}
+
+// EscalateOverdue flags every assignment that exceeded its team's SLA and, depending
+// on the mode, reassigns the review or adds an extra reviewer. It is meant to be
+// polled by a background worker. Each assignment is escalated only once, even when
+// several replicas run the job.
+func (s *SLAUseCase) EscalateOverdue(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "SLAUseCase.EscalateOverdue")
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now().UTC()
+	overdue, err := s.slaRepo.ListOverdueAssignments(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, o := range overdue {
+		escalation, created, recErr := s.slaRepo.RecordEscalation(ctx, *entity.NewEscalation(o, now))
+		if recErr != nil {
+			errs = errors.Join(errs, recErr)
+			continue
+		}
+		if !created {
+			continue
+		}
+
+		entry := logging.FromContext(ctx, s.log).WithFields(log.Fields{
+			"prID":       o.PRID,
+			"reviewerID": o.ReviewerID,
+			"dueAt":      o.DueAt(),
+		})
+		entry.Warn("SLAUseCase - review is overdue")
+
+		if actErr := s.act(ctx, escalation); actErr != nil {
+			entry.WithError(actErr).Warn("SLAUseCase - failed to escalate overdue review")
+		}
+	}
+
+	return errs
+}
+
+func (s *SLAUseCase) act(ctx context.Context, e *entity.Escalation) error {
+	var (
+		action        string
+		newReviewerID string
+		err           error
+	)
+	switch s.mode {
+	case entity.EscalationModeReassign:
+		action = entity.EscalationActionReassigned
+		newReviewerID, _, err = s.escalator.ReassignReviewer(ctx, e.PRID, e.ReviewerID, entity.ReassignOptions{})
+	case entity.EscalationModeAddReviewer:
+		action = entity.EscalationActionReviewerAdded
+		newReviewerID, err = s.escalator.AddReviewerFromTeam(ctx, e.PRID, e.TeamName)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.slaRepo.UpdateEscalationAction(ctx, e.ID, action, newReviewerID)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
+)
+
+const statusChangeBatchSize = 100
+
+// ActiveStatusSetter applies is_active updates. It is satisfied by UserUseCase so
+// scheduled changes go through the same path as manual ones.
+type ActiveStatusSetter interface {
+	SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error)
+}
+
+type StatusChangeUseCase struct {
+	changeRepo StatusChangeRepository
+	userRepo   UserRepository
+	setter     ActiveStatusSetter
+	log        *log.Logger
+}
+
+func NewStatusChangeUseCase(
+	change StatusChangeRepository,
+	user UserRepository,
+	setter ActiveStatusSetter,
+	logger *log.Logger,
+) *StatusChangeUseCase {
+	return &StatusChangeUseCase{changeRepo: change, userRepo: user, setter: setter, log: logger}
+}
+
+// ScheduleStatusChange plans the change of the user's activity. The user must
+// exist and apply_at must be in the future.
+func (s *StatusChangeUseCase) ScheduleStatusChange(
+	ctx context.Context,
+	c entity.StatusChange,
+) (_ *entity.StatusChange, err error) {
+	ctx, span := tracing.Start(ctx, "StatusChangeUseCase.ScheduleStatusChange")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"userID":   c.UserID,
+		"isActive": c.IsActive,
+		"applyAt":  c.ApplyAt,
+	}).Info("StatusChangeUseCase - scheduling status change")
+	if _, err := s.userRepo.GetByID(ctx, c.UserID); err != nil {
+		return nil, err
+	}
+	if !c.ApplyAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: apply_at must be in the future", apperror.ErrInvalidSchedule)
+	}
+	return s.changeRepo.Create(ctx, c)
+}
+
+func (s *StatusChangeUseCase) ListScheduledChanges(
+	ctx context.Context,
+	userID string,
+) (_ []entity.StatusChange, err error) {
+	ctx, span := tracing.Start(ctx, "StatusChangeUseCase.ListScheduledChanges")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("userID", userID).
+		Info("StatusChangeUseCase - listing scheduled status changes")
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.changeRepo.ListPending(ctx, userID)
+}
+
+func (s *StatusChangeUseCase) CancelScheduledChange(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "StatusChangeUseCase.CancelScheduledChange")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithField("changeID", id).
+		Info("StatusChangeUseCase - cancelling scheduled status change")
+	return s.changeRepo.Cancel(ctx, id)
+}
+
+// ApplyDueChanges applies every change whose time has come. It is meant to be
+// polled by a background worker and is safe to run on several replicas at once.
+func (s *StatusChangeUseCase) ApplyDueChanges(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "StatusChangeUseCase.ApplyDueChanges")
+	defer func() { tracing.End(span, err) }()
+
+	applied, err := s.changeRepo.ApplyDue(ctx, time.Now().UTC(), statusChangeBatchSize,
+		func(ctx context.Context, c entity.StatusChange) error {
+			_, setErr := s.setter.SetIsActive(ctx, c.UserID, c.IsActive)
+			if errors.Is(setErr, apperror.ErrNotFound) {
+				logging.FromContext(ctx, s.log).WithField("changeID", c.ID).
+					Warn("StatusChangeUseCase - user of scheduled change not found")
+				return nil
+			}
+			return setErr
+		})
+	if applied > 0 {
+		logging.FromContext(ctx, s.log).WithField("count", applied).
+			Info("StatusChangeUseCase - applied scheduled status changes")
+	}
+	return err
+}
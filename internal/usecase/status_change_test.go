@@ -0,0 +1,46 @@
+package usecase_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/apperror"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/repository/memory"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/usecase"
+)
+
+func TestScheduleStatusChangeValidatesUserAndTime(t *testing.T) {
+	_, store := newPRUseCaseWithPolicy(t, usecase.SelectionPolicy{})
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+	users := memory.NewUserRepository(store)
+	changes := memory.NewStatusChangeRepository(store)
+	uc := usecase.NewStatusChangeUseCase(changes, users, users, logger)
+
+	past := time.Now().Add(-time.Minute)
+	_, err := uc.ScheduleStatusChange(t.Context(), *entity.NewStatusChange("missing", false, past))
+	if !errors.Is(err, apperror.ErrNotFound) {
+		t.Fatalf("missing user: error = %v, want %v", err, apperror.ErrNotFound)
+	}
+	_, err = uc.ScheduleStatusChange(t.Context(), *entity.NewStatusChange("u2", false, past))
+	if !errors.Is(err, apperror.ErrInvalidSchedule) {
+		t.Fatalf("past apply_at: error = %v, want %v", err, apperror.ErrInvalidSchedule)
+	}
+
+	if _, err = uc.ScheduleStatusChange(t.Context(),
+		*entity.NewStatusChange("u2", false, time.Now().Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := uc.ListScheduledChanges(t.Context(), "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("pending changes = %v, want the one in the future", pending)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
 )
 
 type TeamUseCase struct {
@@ -17,15 +19,50 @@ func NewTeamUseCase(team TeamRepository, logger *log.Logger) *TeamUseCase {
 	return &TeamUseCase{teamRepo: team, log: logger}
 }
 
-func (s *TeamUseCase) AddTeam(ctx context.Context, team entity.Team, users []entity.User) error {
-	s.log.WithFields(log.Fields{
+func (s *TeamUseCase) AddTeam(ctx context.Context, team entity.Team, users []entity.User) (err error) {
+	ctx, span := tracing.Start(ctx, "TeamUseCase.AddTeam")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
 		"team":  team.Name,
 		"count": len(users),
 	}).Info("TeamUseCase - adding team")
 	return s.teamRepo.CreateTeam(ctx, team, users)
 }
 
-func (s *TeamUseCase) GetTeam(ctx context.Context, name string) (*entity.Team, []entity.User, error) {
-	s.log.WithField("team", name).Info("TeamUseCase - getting team")
-	return s.teamRepo.GetTeam(ctx, name)
+func (s *TeamUseCase) GetTeam(
+	ctx context.Context,
+	name string,
+	sort entity.Sort,
+	page entity.PageRequest,
+) (_ *entity.Team, _ entity.Page[entity.User], err error) {
+	ctx, span := tracing.Start(ctx, "TeamUseCase.GetTeam")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"team":  name,
+		"sort":  sort.Field,
+		"limit": page.Limit,
+	}).Info("TeamUseCase - getting team")
+	if page.Limit == 0 {
+		// clients from before pagination expect the whole team
+		page.Limit = entity.NoPageLimit
+	}
+	return s.teamRepo.ListMembers(ctx, name, sort, page)
+}
+
+// SetMaxOpenReviews sets the limit of concurrent open reviews of every team
+// member without an own limit, nil removes it.
+func (s *TeamUseCase) SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) (err error) {
+	ctx, span := tracing.Start(ctx, "TeamUseCase.SetMaxOpenReviews")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"team":  teamName,
+		"limit": limit,
+	}).Info("TeamUseCase - setting team review capacity")
+	if err := validateCapacity(limit); err != nil {
+		return err
+	}
+	return s.teamRepo.SetMaxOpenReviews(ctx, teamName, limit)
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
 )
@@ -10,21 +11,96 @@ type PRRepository interface {
 	Create(ctx context.Context, pr entity.PR) error
 	GetByID(ctx context.Context, id string) (*entity.PR, error)
 	UpdateStatus(ctx context.Context, id, status string) (*entity.PR, error)
-	AssignReviewer(ctx context.Context, prID, teamName string) (reviewerID string, err error)
+	AddReviewer(ctx context.Context, prID, reviewerID string) error
+	AddReviewerWithLimit(ctx context.Context, prID, reviewerID string, maxReviewers int) error
 	RemoveReviewer(ctx context.Context, prID, reviewerID string) error
+	MarkReviewerRemoved(ctx context.Context, prID, reviewerID string) error
+	ListRemovedReviewers(ctx context.Context, prID string) ([]string, error)
 	DeleteByID(ctx context.Context, prID string) error
 	GetAssignedReviewers(ctx context.Context, prID string) (assignedIDs []string, err error)
+	List(ctx context.Context, opts entity.PRListOptions) (entity.Page[entity.PR], error)
+	GetReviewersForPRs(ctx context.Context, prIDs []string) (map[string][]string, error)
+	GetReviewerDetails(ctx context.Context, prID string) ([]entity.AssignedReviewer, error)
+	CountAssignments(ctx context.Context, teamName string, since time.Time) (map[string]int, error)
+	CountOpenReviews(ctx context.Context, teamName string) (map[string]int, error)
+}
+
+type ReviewerAuditRepository interface {
+	Record(ctx context.Context, e entity.ReviewerAuditEntry) (*entity.ReviewerAuditEntry, error)
+	ListForPR(ctx context.Context, prID string) ([]entity.ReviewerAuditEntry, error)
+}
+
+type DeclineRepository interface {
+	Record(ctx context.Context, d entity.Decline) (*entity.Decline, error)
+	ListForPR(ctx context.Context, prID string) ([]entity.Decline, error)
 }
 
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, team entity.Team, users []entity.User) error
 	GetTeam(ctx context.Context, name string) (*entity.Team, []entity.User, error)
+	ListMembers(
+		ctx context.Context,
+		name string,
+		sort entity.Sort,
+		page entity.PageRequest,
+	) (*entity.Team, entity.Page[entity.User], error)
 	GetTeamForUser(ctx context.Context, userID string) (string, error)
+	SetMaxOpenReviews(ctx context.Context, teamName string, limit *int) error
+	ListLoad(ctx context.Context) ([]entity.TeamLoad, error)
+}
+
+// OrgRepository reads and replaces the roster of the whole organization.
+type OrgRepository interface {
+	// ListOrg returns every team with all of its members.
+	ListOrg(ctx context.Context) ([]entity.OrgTeam, error)
+	// ApplyOrgPlan creates teams and users, updates users and deletes teams of
+	// the plan in one transaction.
+	ApplyOrgPlan(ctx context.Context, plan entity.OrgPlan) error
 }
 
 type UserRepository interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error)
-	ListAssignedTo(ctx context.Context, userID string) ([]entity.PR, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*entity.User, error)
+	ListAssignedTo(ctx context.Context, userID string, opts entity.PRListOptions) (entity.Page[entity.PR], error)
 	IsAssignedToPR(ctx context.Context, userID, prID string) (bool, error)
 	GetByID(ctx context.Context, userID string) (*entity.User, error)
+	ListTeamStatusHistory(
+		ctx context.Context,
+		teamName string,
+		from, to time.Time,
+	) ([]entity.StatusHistoryEntry, error)
+}
+
+type ScheduleRepository interface {
+	UpsertSchedule(ctx context.Context, s entity.WorkSchedule) (*entity.WorkSchedule, error)
+	GetSchedule(ctx context.Context, userID string) (*entity.WorkSchedule, error)
+	DeleteSchedule(ctx context.Context, userID string) error
+	ListTeamSchedules(ctx context.Context, teamName string) ([]entity.WorkSchedule, error)
+	AddOutOfOffice(ctx context.Context, o entity.OutOfOffice) (*entity.OutOfOffice, error)
+	DeleteOutOfOffice(ctx context.Context, id int64) error
+	ListOutOfOffice(ctx context.Context, userID string, now time.Time) ([]entity.OutOfOffice, error)
+	ListTeamOutOfOffice(ctx context.Context, teamName string, at time.Time) ([]entity.OutOfOffice, error)
+	ListTeamOutOfOfficeBetween(ctx context.Context, teamName string, from, to time.Time) ([]entity.OutOfOffice, error)
+}
+
+type StatusChangeRepository interface {
+	Create(ctx context.Context, c entity.StatusChange) (*entity.StatusChange, error)
+	ListPending(ctx context.Context, userID string) ([]entity.StatusChange, error)
+	Cancel(ctx context.Context, id int64) error
+	ApplyDue(
+		ctx context.Context,
+		now time.Time,
+		limit uint64,
+		apply func(ctx context.Context, c entity.StatusChange) error,
+	) (int, error)
+}
+
+type SLARepository interface {
+	SetTeamSLA(ctx context.Context, teamName string, sla *time.Duration) error
+	GetTeamSLA(ctx context.Context, teamName string) (*time.Duration, error)
+	ListOverdueAssignments(ctx context.Context, now time.Time) ([]entity.OverdueAssignment, error)
+	RecordEscalation(ctx context.Context, e entity.Escalation) (*entity.Escalation, bool, error)
+	UpdateEscalationAction(ctx context.Context, id int64, action, newReviewerID string) error
+	ListOpenEscalations(ctx context.Context) ([]entity.Escalation, error)
+	GetReviewStats(ctx context.Context, teamName string, now time.Time) (*entity.ReviewStats, error)
 }
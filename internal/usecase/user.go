@@ -6,6 +6,8 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/Xausdorf/pr-reviewer-assignment/internal/entity"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/tracing"
 )
 
 type UserUseCase struct {
@@ -17,15 +19,57 @@ func NewUserUseCase(user UserRepository, logger *log.Logger) *UserUseCase {
 	return &UserUseCase{userRepo: user, log: logger}
 }
 
-func (s *UserUseCase) SetIsActive(ctx context.Context, userID string, isActive bool) (*entity.User, error) {
-	s.log.WithFields(log.Fields{
+func (s *UserUseCase) SetIsActive(ctx context.Context, userID string, isActive bool) (_ *entity.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.SetIsActive")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
 		"userID":   userID,
 		"isActive": isActive,
 	}).Info("UserUseCase - setting user active status")
 	return s.userRepo.SetIsActive(ctx, userID, isActive)
 }
 
-func (s *UserUseCase) GetAssignedTo(ctx context.Context, userID string) ([]entity.PR, error) {
-	s.log.WithField("userID", userID).Info("UserUseCase - getting assigned PRs")
-	return s.userRepo.ListAssignedTo(ctx, userID)
+// SetMaxOpenReviews sets the user's own limit of concurrent open reviews, nil
+// falls back to the team limit.
+func (s *UserUseCase) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (_ *entity.User, err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.SetMaxOpenReviews")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"userID": userID,
+		"limit":  limit,
+	}).Info("UserUseCase - setting user review capacity")
+	if err := validateCapacity(limit); err != nil {
+		return nil, err
+	}
+	return s.userRepo.SetMaxOpenReviews(ctx, userID, limit)
+}
+
+// GetAssignedTo lists PRs the user reviews, newest first unless another order is requested.
+func (s *UserUseCase) GetAssignedTo(
+	ctx context.Context,
+	userID string,
+	opts entity.PRListOptions,
+) (_ entity.Page[entity.PR], err error) {
+	ctx, span := tracing.Start(ctx, "UserUseCase.GetAssignedTo")
+	defer func() { tracing.End(span, err) }()
+
+	logging.FromContext(ctx, s.log).WithFields(log.Fields{
+		"userID": userID,
+		"status": opts.Filter.Status,
+		"sort":   opts.Sort.Field,
+		"limit":  opts.Page.Limit,
+	}).Info("UserUseCase - getting assigned PRs")
+	if opts.Sort.Field == "" {
+		opts.Sort.Field = entity.PRSortCreatedAt
+	}
+	if opts.Sort.Order == "" {
+		opts.Sort.Order = entity.SortDesc
+	}
+	if opts.Page.Limit == 0 {
+		// clients from before pagination expect all the reviews
+		opts.Page.Limit = entity.NoPageLimit
+	}
+	return s.userRepo.ListAssignedTo(ctx, userID, opts)
 }
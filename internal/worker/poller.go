@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/internal/logging"
+)
+
+type Job func(ctx context.Context) error
+
+// Poller runs a job on a fixed interval until its context is cancelled.
+type Poller struct {
+	name     string
+	interval time.Duration
+	job      Job
+	log      *log.Logger
+}
+
+func NewPoller(name string, interval time.Duration, job Job, logger *log.Logger) *Poller {
+	return &Poller{name: name, interval: interval, job: job, log: logger}
+}
+
+// Run blocks until ctx is done. Job errors are logged and do not stop the poller.
+func (p *Poller) Run(ctx context.Context) {
+	entry := p.log.WithFields(log.Fields{"component": "worker", "job": p.name})
+	entry.WithField("interval", p.interval).Info("Starting poller")
+	// usecases and repositories log with the job fields
+	ctx = logging.NewContext(ctx, entry)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.job(ctx); err != nil && ctx.Err() == nil {
+			entry.WithError(err).Error("Poller job failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			entry.Info("Poller stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS scheduled_status_changes (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  is_active BOOLEAN NOT NULL,
+  apply_at TIMESTAMPTZ NOT NULL,
+  applied_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_status_changes_pending
+  ON scheduled_status_changes(apply_at) WHERE applied_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_scheduled_status_changes_user ON scheduled_status_changes(user_id);
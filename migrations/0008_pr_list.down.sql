@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_prs_merged_at;
+DROP INDEX IF EXISTS idx_prs_title_trgm;
@@ -0,0 +1,4 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_prs_title_trgm ON prs USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_prs_merged_at ON prs(merged_at) WHERE merged_at IS NOT NULL;
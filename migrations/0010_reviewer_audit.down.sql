@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reviewer_audit_pr_id;
+
+DROP TABLE IF EXISTS reviewer_audit;
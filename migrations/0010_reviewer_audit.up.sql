@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS reviewer_audit (
+  id BIGSERIAL PRIMARY KEY,
+  pr_id VARCHAR(255) NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  reviewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  action TEXT NOT NULL,
+  actor TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_audit_pr_id ON reviewer_audit(pr_id);
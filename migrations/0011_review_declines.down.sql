@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_review_declines_reviewer_id;
+DROP INDEX IF EXISTS idx_review_declines_pr_id;
+
+DROP TABLE IF EXISTS review_declines;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS removed_reviewers;
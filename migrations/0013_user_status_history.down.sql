@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_users_record_status ON users;
+DROP FUNCTION IF EXISTS users_record_status();
+DROP TABLE IF EXISTS user_status_history;
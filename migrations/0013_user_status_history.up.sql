@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS user_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  is_active BOOLEAN NOT NULL,
+  changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_status_history_user_changed_at ON user_status_history(user_id, changed_at);
+
+CREATE OR REPLACE FUNCTION users_record_status()
+RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO user_status_history (user_id, is_active) VALUES (NEW.id, NEW.is_active);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_users_record_status
+AFTER UPDATE OF is_active ON users
+FOR EACH ROW
+WHEN (NEW.is_active IS DISTINCT FROM OLD.is_active)
+EXECUTE FUNCTION users_record_status();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS prs;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
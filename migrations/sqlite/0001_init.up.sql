@@ -0,0 +1,30 @@
+-- Timestamps are stored as INTEGER nanoseconds since the Unix epoch (UTC), so
+-- they compare and sort natively and support interval arithmetic.
+CREATE TABLE IF NOT EXISTS teams (
+  name TEXT PRIMARY KEY,
+  created_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+  id VARCHAR(255) PRIMARY KEY,
+  name TEXT NOT NULL,
+  team_name TEXT NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS prs (
+  id VARCHAR(255) PRIMARY KEY,
+  title TEXT NOT NULL,
+  author_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+  status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+  created_at INTEGER NOT NULL,
+  merged_at INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+  pr_id VARCHAR(255) NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  reviewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+  assigned_at INTEGER NOT NULL,
+  PRIMARY KEY (pr_id, reviewer_id)
+);
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS trg_prs_update_merged_at;
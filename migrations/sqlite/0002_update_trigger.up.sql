@@ -0,0 +1,8 @@
+-- SQLite triggers can not modify NEW, so merged_at is set by a follow-up update.
+CREATE TRIGGER IF NOT EXISTS trg_prs_update_merged_at
+AFTER UPDATE OF status ON prs
+FOR EACH ROW
+WHEN NEW.status = 'MERGED' AND OLD.status = 'OPEN'
+BEGIN
+  UPDATE prs SET merged_at = CAST(unixepoch('subsec') * 1000000000 AS INTEGER) WHERE id = NEW.id;
+END;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_pr_id;
+DROP INDEX IF EXISTS idx_pr_reviewers_reviewer;
+DROP INDEX IF EXISTS idx_pr_author;
+DROP INDEX IF EXISTS idx_users_is_active_team_name;
+DROP INDEX IF EXISTS idx_users_team_name;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_out_of_office_user_ends;
+
+DROP TABLE IF EXISTS user_out_of_office;
+
+DROP TABLE IF EXISTS user_schedules;
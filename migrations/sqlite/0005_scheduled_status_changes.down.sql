@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_scheduled_status_changes_user;
+DROP INDEX IF EXISTS idx_scheduled_status_changes_pending;
+
+DROP TABLE IF EXISTS scheduled_status_changes;
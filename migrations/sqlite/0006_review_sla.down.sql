@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pr_reviewers_assigned_at;
+DROP INDEX IF EXISTS idx_review_escalations_pr_id;
+
+DROP TABLE IF EXISTS review_escalations;
+
+ALTER TABLE teams DROP COLUMN review_sla_seconds;
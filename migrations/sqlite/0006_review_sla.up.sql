@@ -0,0 +1,17 @@
+ALTER TABLE teams ADD COLUMN review_sla_seconds INTEGER CHECK (review_sla_seconds > 0);
+
+CREATE TABLE IF NOT EXISTS review_escalations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  pr_id VARCHAR(255) NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  reviewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  team_name TEXT NOT NULL,
+  assigned_at INTEGER NOT NULL,
+  due_at INTEGER NOT NULL,
+  detected_at INTEGER NOT NULL,
+  action TEXT NOT NULL DEFAULT 'NONE',
+  new_reviewer_id VARCHAR(255),
+  UNIQUE (pr_id, reviewer_id, assigned_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_escalations_pr_id ON review_escalations(pr_id);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_assigned_at ON pr_reviewers(assigned_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_team_name_name_id;
+DROP INDEX IF EXISTS idx_prs_status_created_at;
+DROP INDEX IF EXISTS idx_prs_created_at_id;
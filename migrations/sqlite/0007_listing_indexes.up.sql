@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_prs_created_at_id ON prs(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_prs_status_created_at ON prs(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_users_team_name_name_id ON users(team_name, name, id);
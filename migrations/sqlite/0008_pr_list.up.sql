@@ -0,0 +1,2 @@
+-- SQLite has no trigram indexes, title substring search scans the table.
+CREATE INDEX IF NOT EXISTS idx_prs_merged_at ON prs(merged_at) WHERE merged_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN max_open_reviews;
+ALTER TABLE teams DROP COLUMN max_open_reviews;
@@ -0,0 +1,2 @@
+ALTER TABLE teams ADD COLUMN max_open_reviews INTEGER CHECK (max_open_reviews >= 0);
+ALTER TABLE users ADD COLUMN max_open_reviews INTEGER CHECK (max_open_reviews >= 0);
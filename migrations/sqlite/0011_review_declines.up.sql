@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS review_declines (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  pr_id VARCHAR(255) NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  reviewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  reason TEXT NOT NULL,
+  comment TEXT NOT NULL DEFAULT '',
+  new_reviewer_id VARCHAR(255) NOT NULL,
+  created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_declines_pr_id ON review_declines(pr_id);
+CREATE INDEX IF NOT EXISTS idx_review_declines_reviewer_id ON review_declines(reviewer_id);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS removed_reviewers (
+  pr_id VARCHAR(255) NOT NULL REFERENCES prs(id) ON DELETE CASCADE,
+  reviewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  removed_at INTEGER NOT NULL,
+  PRIMARY KEY (pr_id, reviewer_id)
+);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_users_record_status;
+DROP TABLE IF EXISTS user_status_history;
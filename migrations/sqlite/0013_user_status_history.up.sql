@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS user_status_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  is_active BOOLEAN NOT NULL,
+  changed_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_status_history_user_changed_at ON user_status_history(user_id, changed_at);
+
+CREATE TRIGGER IF NOT EXISTS trg_users_record_status
+AFTER UPDATE OF is_active ON users
+FOR EACH ROW
+WHEN NEW.is_active IS NOT OLD.is_active
+BEGIN
+  INSERT INTO user_status_history (user_id, is_active, changed_at)
+  VALUES (NEW.id, NEW.is_active, CAST(unixepoch('subsec') * 1000000000 AS INTEGER));
+END;
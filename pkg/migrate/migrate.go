@@ -7,44 +7,95 @@ import (
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	log "github.com/sirupsen/logrus"
 
 	// blank imports for database and source drivers.
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/jackc/pgx/v5/stdlib"
+
+	pkgsqlite "github.com/Xausdorf/pr-reviewer-assignment/pkg/sqlite"
 )
 
 func RunMigrations(databaseURL, migrationsDir string, logger *log.Logger) error {
 	if migrationsDir == "" {
 		migrationsDir = "./migrations"
 	}
+	return up(func() (*migrate.Migrate, *sql.DB, error) {
+		return openPostgres(databaseURL, migrationsDir)
+	}, migrationsDir, logger)
+}
 
-	db, err := sql.Open("pgx", databaseURL)
+// RunSQLiteMigrations applies the SQLite migration set to the database file.
+func RunSQLiteMigrations(path, migrationsDir string, logger *log.Logger) error {
+	return up(func() (*migrate.Migrate, *sql.DB, error) {
+		return openSQLite(path, migrationsDir)
+	}, migrationsDir, logger)
+}
+
+func up(open func() (*migrate.Migrate, *sql.DB, error), migrationsDir string, logger *log.Logger) error {
+	m, db, err := open()
 	if err != nil {
-		logger.WithError(err).Error("Failed to open database for migrations")
-		return fmt.Errorf("open database: %w", err)
+		logger.WithError(err).WithField("source", sourceURL(migrationsDir)).Error("Failed to create migrate instance")
+		return err
 	}
 	defer db.Close()
+	defer m.Close()
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		logger.WithError(err).Error("Failed running migrations")
+		return fmt.Errorf("migrate up: %w", err)
+	}
+
+	logger.WithField("source", sourceURL(migrationsDir)).Info("Migrations applied (no change or up completed)")
+	return nil
+}
+
+func sourceURL(migrationsDir string) string {
+	return "file://" + migrationsDir
+}
+
+// openPostgres returns a migrate instance on its own database handle, closing
+// the instance leaves the handle open.
+func openPostgres(databaseURL, migrationsDir string) (*migrate.Migrate, *sql.DB, error) {
+	db, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open database: %w", err)
+	}
 
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		logger.WithError(err).Error("Failed to create migrate postgres driver")
-		return fmt.Errorf("postgres driver: %w", err)
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("postgres driver: %w", err)
 	}
 
-	sourceURL := "file://" + migrationsDir
-	m, err := migrate.NewWithDatabaseInstance(sourceURL, "postgres", driver)
+	m, err := migrate.NewWithDatabaseInstance(sourceURL(migrationsDir), "postgres", driver)
 	if err != nil {
-		logger.WithError(err).WithField("source", sourceURL).Error("Failed to create migrate instance")
-		return fmt.Errorf("create migrate: %w", err)
+		_ = driver.Close()
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("create migrate: %w", err)
 	}
-	err = m.Up()
-	defer m.Close()
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		logger.WithError(err).Error("Failed running migrations")
-		return fmt.Errorf("migrate up: %w", err)
+	return m, db, nil
+}
+
+// openSQLite is openPostgres for an SQLite database file. The sqlite driver
+// closes the handle with the instance.
+func openSQLite(path, migrationsDir string) (*migrate.Migrate, *sql.DB, error) {
+	db, err := sql.Open("sqlite", pkgsqlite.DSN(path, 0))
+	if err != nil {
+		return nil, nil, fmt.Errorf("open database: %w", err)
 	}
 
-	logger.WithField("source", sourceURL).Info("Migrations applied (no change or up completed)")
-	return nil
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("sqlite driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance(sourceURL(migrationsDir), "sqlite", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, nil, fmt.Errorf("create migrate: %w", err)
+	}
+	return m, db, nil
 }
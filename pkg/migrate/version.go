@@ -0,0 +1,115 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errNoVersion is returned by a VersionReader when no migration was applied.
+var errNoVersion = errors.New("no schema version")
+
+// VersionReader reads the row golang-migrate keeps in schema_migrations.
+type VersionReader func(ctx context.Context) (version int64, dirty bool, err error)
+
+// PostgresVersion reads the schema version through the pool of the service.
+func PostgresVersion(pool *pgxpool.Pool) VersionReader {
+	return func(ctx context.Context) (int64, bool, error) {
+		var (
+			version int64
+			dirty   bool
+		)
+		err := pool.QueryRow(ctx, versionQuery).Scan(&version, &dirty)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, errNoVersion
+		}
+		return version, dirty, err
+	}
+}
+
+// SQLiteVersion reads the schema version through the database handle of the
+// service.
+func SQLiteVersion(db *sql.DB) VersionReader {
+	return func(ctx context.Context) (int64, bool, error) {
+		var (
+			version int64
+			dirty   bool
+		)
+		err := db.QueryRowContext(ctx, versionQuery).Scan(&version, &dirty)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, errNoVersion
+		}
+		return version, dirty, err
+	}
+}
+
+const versionQuery = "SELECT version, dirty FROM schema_migrations LIMIT 1"
+
+// VersionCheck verifies that the database schema is at the latest migration
+// of the migrations directory.
+type VersionCheck struct {
+	read     VersionReader
+	expected uint
+}
+
+func NewVersionCheck(read VersionReader, migrationsDir string) (*VersionCheck, error) {
+	expected, err := latestVersion(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionCheck{read: read, expected: expected}, nil
+}
+
+// Expected returns the version of the latest migration.
+func (c *VersionCheck) Expected() uint {
+	return c.expected
+}
+
+// Check returns an error if the schema is at another version or dirty. The
+// version is read with a plain query under ctx, so the check neither takes
+// the migration lock nor outlives the probe.
+func (c *VersionCheck) Check(ctx context.Context) error {
+	version, dirty, err := c.read(ctx)
+	if errors.Is(err, errNoVersion) {
+		return fmt.Errorf("no migrations applied, want version %d", c.expected)
+	}
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+	if version < 0 || uint(version) != c.expected {
+		return fmt.Errorf("schema version %d, want %d", version, c.expected)
+	}
+	return nil
+}
+
+func latestVersion(migrationsDir string) (uint, error) {
+	src, err := source.Open(sourceURL(migrationsDir))
+	if err != nil {
+		return 0, fmt.Errorf("open migrations source: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("read first migration: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read migration after %d: %w", version, err)
+		}
+		version = next
+	}
+}
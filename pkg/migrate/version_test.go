@@ -0,0 +1,91 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Xausdorf/pr-reviewer-assignment/pkg/migrate"
+	pkgsqlite "github.com/Xausdorf/pr-reviewer-assignment/pkg/sqlite"
+)
+
+func TestSQLiteVersionCheck(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	migrationsDir, err := filepath.Abs("../../migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := pkgsqlite.Open(t.Context(), pkgsqlite.Config{Path: path}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkgsqlite.Close(db)
+
+	check, err := migrate.NewVersionCheck(migrate.SQLiteVersion(db), migrationsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = check.Check(t.Context()); err == nil {
+		t.Fatal("check passed before migrations were applied")
+	}
+
+	if err = migrate.RunSQLiteMigrations(path, migrationsDir, logger); err != nil {
+		t.Fatal(err)
+	}
+	if err = check.Check(t.Context()); err != nil {
+		t.Fatalf("check after migrations: %v", err)
+	}
+
+	// a newer release ships one more migration than the database has
+	newerDir := t.TempDir()
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(migrationsDir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(newerDir, e.Name()), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"9999_next.up.sql", "9999_next.down.sql"} {
+		if err = os.WriteFile(filepath.Join(newerDir, name), []byte("SELECT 1;"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newer, err := migrate.NewVersionCheck(migrate.SQLiteVersion(db), newerDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newer.Expected() != 9999 {
+		t.Fatalf("expected version = %d, want 9999", newer.Expected())
+	}
+	if err = newer.Check(t.Context()); err == nil {
+		t.Fatal("check passed with a missing migration")
+	}
+
+	// the single connection is busy, the check gives up with the probe context
+	conn, err := db.Conn(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	if err = check.Check(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("check with a busy connection = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
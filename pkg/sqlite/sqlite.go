@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	// registers the pure Go "sqlite" driver.
+	_ "modernc.org/sqlite"
+)
+
+const (
+	defaultBusyTimeout = 5 * time.Second
+	defaultPingTimeout = 5 * time.Second
+)
+
+type Config struct {
+	Path        string
+	BusyTimeout time.Duration
+}
+
+// DSN returns the driver connection string for the database file with foreign
+// keys enforced and WAL journaling enabled.
+func DSN(path string, busyTimeout time.Duration) string {
+	if busyTimeout == 0 {
+		busyTimeout = defaultBusyTimeout
+	}
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)",
+		path, busyTimeout.Milliseconds())
+}
+
+// Open opens the database file. SQLite allows a single writer, so the pool is
+// limited to one connection and statements are serialized instead of failing
+// with SQLITE_BUSY.
+func Open(ctx context.Context, cfg Config, logger *log.Logger) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DSN(cfg.Path, cfg.BusyTimeout))
+	if err != nil {
+		logger.WithError(err).Error("Failed to open sqlite database")
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	ctxPing, cancel := context.WithTimeout(ctx, defaultPingTimeout)
+	defer cancel()
+	if err = db.PingContext(ctxPing); err != nil {
+		logger.WithError(err).Error("Failed to ping sqlite database")
+		_ = db.Close()
+		return nil, err
+	}
+
+	logger.WithFields(log.Fields{"component": "sqlite", "path": cfg.Path}).Info("Opened sqlite database")
+	return db, nil
+}
+
+func Close(db *sql.DB) {
+	if db != nil {
+		_ = db.Close()
+	}
+}